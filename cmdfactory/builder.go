@@ -7,6 +7,7 @@ package cmdfactory
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"reflect"
@@ -181,7 +182,15 @@ func Main(ctx context.Context, cmd *cobra.Command) int {
 	expandRegisteredFlags(cmd)
 
 	if err := cmd.ExecuteContext(ctx); err != nil {
-		log.G(ctx).Error(err)
+		if !errors.Is(err, ErrSilent) {
+			log.G(ctx).Error(err)
+		}
+
+		var exitErr *ExitError
+		if errors.As(err, &exitErr) {
+			return exitErr.Code
+		}
+
 		return 1
 	}
 
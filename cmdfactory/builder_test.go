@@ -6,6 +6,9 @@
 package cmdfactory
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"os"
 	"testing"
 
@@ -220,3 +223,34 @@ func equalMaps(got, expect map[string]string) bool {
 
 	return true
 }
+
+func TestMain_ExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "success", err: nil, want: 0},
+		{name: "plain error", err: errors.New("failed"), want: 1},
+		{name: "exit error", err: &ExitError{Code: 3, Err: errors.New("not found")}, want: 3},
+		{name: "wrapped exit error", err: fmt.Errorf("removing: %w", &ExitError{Code: 5, Err: errors.New("conflict")}), want: 5},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd := &cobra.Command{
+				Use:           "kraft",
+				SilenceErrors: true,
+				SilenceUsage:  true,
+				RunE: func(*cobra.Command, []string) error {
+					return tc.err
+				},
+			}
+			cmd.SetArgs([]string{})
+
+			if got := Main(context.Background(), cmd); got != tc.want {
+				t.Errorf("Main() = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
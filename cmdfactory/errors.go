@@ -34,6 +34,21 @@ func (fe *FlagError) Unwrap() error {
 	return fe.err
 }
 
+// An *ExitError indicates the exit code with which the program should
+// terminate when the wrapped error is returned by a command.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (ee *ExitError) Error() string {
+	return ee.Err.Error()
+}
+
+func (ee *ExitError) Unwrap() error {
+	return ee.Err
+}
+
 // ErrSilent is an error that triggers exit code 1 without any error messaging
 var ErrSilent = errors.New("ErrSilent")
 
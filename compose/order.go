@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package compose
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+)
+
+// LookupService returns the service with the provided name.  The name can
+// either be the name used in the Compose file or the project-prefixed name
+// which is assigned during validation.
+func (project *Project) LookupService(name string) (types.ServiceConfig, error) {
+	for _, service := range project.Services {
+		if service.Name == name || service.Name == project.Name+"-"+name {
+			return service, nil
+		}
+	}
+
+	return types.ServiceConfig{}, fmt.Errorf("no such service: %s", name)
+}
+
+// ServiceWaves returns the services of the project grouped into waves based
+// on their `depends_on` declarations, such that every service only depends on
+// services in earlier waves.  When names are provided, only the matching
+// services are returned, whilst retaining their relative order.  An error is
+// returned listing the services involved if the dependencies form a cycle.
+func (project *Project) ServiceWaves(names ...string) ([]types.Services, error) {
+	byName := make(map[string]types.ServiceConfig, len(project.Services))
+	for _, service := range project.Services {
+		byName[service.Name] = service
+	}
+
+	// Resolve the dependencies of each service to their (validated) names.
+	deps := make(map[string][]string, len(project.Services))
+	for _, service := range project.Services {
+		for dep := range service.DependsOn {
+			depService, err := project.LookupService(dep)
+			if err != nil {
+				return nil, fmt.Errorf("service %s depends on unknown service %s", service.Name, dep)
+			}
+
+			deps[service.Name] = append(deps[service.Name], depService.Name)
+		}
+	}
+
+	var waves []types.Services
+	done := make(map[string]bool, len(project.Services))
+
+	for len(done) < len(project.Services) {
+		var wave []string
+		for name := range byName {
+			if done[name] {
+				continue
+			}
+
+			ready := true
+			for _, dep := range deps[name] {
+				if !done[dep] {
+					ready = false
+					break
+				}
+			}
+
+			if ready {
+				wave = append(wave, name)
+			}
+		}
+
+		if len(wave) == 0 {
+			return nil, fmt.Errorf("dependency cycle detected: %s", strings.Join(findCycle(deps, done), " -> "))
+		}
+
+		sort.Strings(wave)
+
+		services := types.Services{}
+		for _, name := range wave {
+			done[name] = true
+			services = append(services, byName[name])
+		}
+
+		waves = append(waves, services)
+	}
+
+	if len(names) == 0 {
+		return waves, nil
+	}
+
+	selected := make(map[string]bool, len(names))
+	for _, name := range names {
+		service, err := project.LookupService(name)
+		if err != nil {
+			return nil, err
+		}
+
+		selected[service.Name] = true
+	}
+
+	var filtered []types.Services
+	for _, wave := range waves {
+		services := types.Services{}
+		for _, service := range wave {
+			if selected[service.Name] {
+				services = append(services, service)
+			}
+		}
+
+		if len(services) > 0 {
+			filtered = append(filtered, services)
+		}
+	}
+
+	return filtered, nil
+}
+
+// findCycle returns a cycle amongst the services which have not yet been
+// ordered, starting and ending with the same service.
+func findCycle(deps map[string][]string, done map[string]bool) []string {
+	var pending []string
+	for name := range deps {
+		if !done[name] {
+			pending = append(pending, name)
+		}
+	}
+
+	sort.Strings(pending)
+
+	// Every pending service has at least one pending dependency, so following
+	// them from any service must eventually revisit one.
+	visited := map[string]int{}
+	var path []string
+
+	for current := pending[0]; ; {
+		if i, ok := visited[current]; ok {
+			return append(path[i:], current)
+		}
+
+		visited[current] = len(path)
+		path = append(path, current)
+
+		next := ""
+		for _, dep := range deps[current] {
+			if !done[dep] && (next == "" || dep < next) {
+				next = dep
+			}
+		}
+
+		current = next
+	}
+}
@@ -37,40 +37,70 @@ var DefaultFileNames = []string{
 	"Composefile",
 }
 
+// ProjectOption is an option which customizes how a project is loaded.
+type ProjectOption func(*projectOptions)
+
+type projectOptions struct {
+	profiles []string
+}
+
+// WithProfiles sets the profiles which are active when loading the project.
+// Services which declare profiles are only part of the project if at least one
+// of them is active, whilst services without any profiles are always included.
+func WithProfiles(profiles ...string) ProjectOption {
+	return func(popts *projectOptions) {
+		popts.profiles = profiles
+	}
+}
+
 // NewProjectFromComposeFile loads a compose file and returns a project. If no
 // compose file is specified, it will look for one in the current directory.
-func NewProjectFromComposeFile(ctx context.Context, workdir, composefile string) (*Project, error) {
-	if composefile == "" {
+func NewProjectFromComposeFile(ctx context.Context, workdir, composefile string, opts ...ProjectOption) (*Project, error) {
+	var composefiles []string
+	if composefile != "" {
+		composefiles = []string{composefile}
+	}
+
+	return NewProjectFromComposeFiles(ctx, workdir, composefiles, opts...)
+}
+
+// NewProjectFromComposeFiles loads the compose files and returns a project,
+// where each file is merged on top of the ones preceding it. If no compose file
+// is specified, it will look for one in the current directory.
+func NewProjectFromComposeFiles(ctx context.Context, workdir string, composefiles []string, opts ...ProjectOption) (*Project, error) {
+	popts := projectOptions{}
+	for _, opt := range opts {
+		opt(&popts)
+	}
+
+	if len(composefiles) == 0 {
 		for _, file := range DefaultFileNames {
 			fullpath := filepath.Join(workdir, file)
 			if _, err := os.Stat(fullpath); err == nil {
 				log.G(ctx).Debugf("Found compose file: %s", file)
-				composefile = file
+				composefiles = []string{file}
 				break
 			}
 		}
 	}
 
-	if composefile == "" {
+	if len(composefiles) == 0 {
 		return nil, fmt.Errorf("no compose file found")
 	}
 
-	fullpath := filepath.Join(workdir, composefile)
-
-	config := types.ConfigDetails{
-		ConfigFiles: []types.ConfigFile{
-			{
-				Filename: fullpath,
-			},
-		},
+	config := types.ConfigDetails{}
+	for _, composefile := range composefiles {
+		config.ConfigFiles = append(config.ConfigFiles, types.ConfigFile{
+			Filename: filepath.Join(workdir, composefile),
+		})
 	}
 
-	project, err := loader.Load(config)
+	project, err := loader.Load(config, loader.WithProfiles(popts.profiles))
 	if err != nil {
 		return nil, err
 	}
 
-	project.ComposeFiles = []string{composefile}
+	project.ComposeFiles = composefiles
 	project.WorkingDir = workdir
 
 	return &Project{project}, err
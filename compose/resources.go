@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package compose
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/compose-spec/compose-go/types"
+)
+
+// ServiceReplicas returns the number of machines which should be run for the
+// provided service as set via `deploy.replicas`, which defaults to one.
+func ServiceReplicas(service types.ServiceConfig) int {
+	if service.Deploy != nil && service.Deploy.Replicas != nil {
+		return int(*service.Deploy.Replicas)
+	}
+
+	return 1
+}
+
+// ServiceMemory returns the memory in bytes which should be assigned to each
+// machine of the provided service as set via `mem_limit` or, alternatively,
+// `deploy.resources.limits.memory`.  Zero is returned if neither is set.
+func ServiceMemory(service types.ServiceConfig) int64 {
+	if service.MemLimit > 0 {
+		return int64(service.MemLimit)
+	}
+
+	if service.Deploy != nil && service.Deploy.Resources.Limits != nil {
+		return int64(service.Deploy.Resources.Limits.MemoryBytes)
+	}
+
+	return 0
+}
+
+// ReplicaName returns the name of the machine of the i-th replica of the
+// provided service, counting from one.  The first replica carries the name of
+// the service itself, such that services without replicas are unaffected.
+func ReplicaName(service types.ServiceConfig, i int) string {
+	if i <= 1 {
+		return service.Name
+	}
+
+	return fmt.Sprintf("%s-%d", service.Name, i)
+}
+
+// IsReplicaOf returns whether the machine with the provided name is one of
+// the replicas of the provided service.
+func IsReplicaOf(service types.ServiceConfig, machineName string) bool {
+	if machineName == service.Name {
+		return true
+	}
+
+	suffix, ok := strings.CutPrefix(machineName, service.Name+"-")
+	if !ok {
+		return false
+	}
+
+	i, err := strconv.Atoi(suffix)
+
+	return err == nil && i > 1
+}
+
+// SetServiceReplicas overrides the number of replicas of the service with the
+// provided name.
+func (project *Project) SetServiceReplicas(name string, replicas int) error {
+	if replicas < 0 {
+		return fmt.Errorf("number of replicas of service %s cannot be negative", name)
+	}
+
+	i, err := project.serviceIndex(name)
+	if err != nil {
+		return err
+	}
+
+	if project.Services[i].Deploy == nil {
+		project.Services[i].Deploy = &types.DeployConfig{}
+	}
+
+	n := uint64(replicas)
+	project.Services[i].Deploy.Replicas = &n
+
+	return nil
+}
+
+// SetServiceMemory overrides the memory in bytes of the service with the
+// provided name.
+func (project *Project) SetServiceMemory(name string, bytes int64) error {
+	if bytes <= 0 {
+		return fmt.Errorf("memory of service %s must be positive", name)
+	}
+
+	i, err := project.serviceIndex(name)
+	if err != nil {
+		return err
+	}
+
+	project.Services[i].MemLimit = types.UnitBytes(bytes)
+
+	return nil
+}
+
+// serviceIndex returns the index of the service with the provided name in
+// the list of services of the project.
+func (project *Project) serviceIndex(name string) (int, error) {
+	service, err := project.LookupService(name)
+	if err != nil {
+		return -1, err
+	}
+
+	for i := range project.Services {
+		if project.Services[i].Name == service.Name {
+			return i, nil
+		}
+	}
+
+	return -1, fmt.Errorf("no such service: %s", name)
+}
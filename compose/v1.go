@@ -57,7 +57,7 @@ func NewComposeProjectV1(ctx context.Context, opts ...any) (composev1.ComposeSer
 }
 
 func (v1 *v1Compose) refreshRunningServices(ctx context.Context, embeddedProject *composev1.Compose) error {
-	project, err := NewProjectFromComposeFile(ctx, embeddedProject.Spec.Workdir, embeddedProject.Spec.Composefile)
+	project, err := NewProjectFromComposeFiles(ctx, embeddedProject.Spec.Workdir, specComposefiles(embeddedProject.Spec))
 	if err != nil {
 		return err
 	}
@@ -133,7 +133,7 @@ func (v1 *v1Compose) refreshRunningServices(ctx context.Context, embeddedProject
 }
 
 func (v1 *v1Compose) refreshExistingNetworks(ctx context.Context, embeddedProject *composev1.Compose) error {
-	project, err := NewProjectFromComposeFile(ctx, embeddedProject.Spec.Workdir, embeddedProject.Spec.Composefile)
+	project, err := NewProjectFromComposeFiles(ctx, embeddedProject.Spec.Workdir, specComposefiles(embeddedProject.Spec))
 	if err != nil {
 		return err
 	}
@@ -240,3 +240,18 @@ func (v1 *v1Compose) Get(ctx context.Context, project *composev1.Compose) (*comp
 func (v1 *v1Compose) Update(ctx context.Context, project *composev1.Compose) (*composev1.Compose, error) {
 	return project, nil
 }
+
+// specComposefiles returns the compose files of the provided spec, falling
+// back to the single compose file of projects stored before multiple files
+// were supported.
+func specComposefiles(spec composev1.ComposeSpec) []string {
+	if len(spec.Composefiles) > 0 {
+		return spec.Composefiles
+	}
+
+	if spec.Composefile == "" {
+		return nil
+	}
+
+	return []string{spec.Composefile}
+}
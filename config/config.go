@@ -50,6 +50,10 @@ type KraftKit struct {
 		Manifests []string `yaml:"manifests" env:"KRAFTKIT_UNIKRAFT_MANIFESTS" long:"with-manifest" usage:"Paths to package or component manifests"`
 	} `yaml:"unikraft"`
 
+	KraftCloud struct {
+		MetroFromFQDN bool `yaml:"metro_from_fqdn" env:"KRAFTKIT_KRAFTCLOUD_METRO_FROM_FQDN" long:"kraftcloud-metro-from-fqdn" usage:"Infer the KraftCloud metro from the provided FQDN when no metro is set"`
+	} `yaml:"kraftcloud"`
+
 	Auth map[string]AuthConfig `yaml:"auth,omitempty" noattribute:"true"`
 
 	Aliases map[string]map[string]string `yaml:"aliases" noattribute:"true"`
@@ -113,6 +117,10 @@ var configDetails = []ConfigDetail{
 		Key:         "log.timestamps",
 		Description: "Show timestamps with log output",
 	},
+	{
+		Key:         "kraftcloud.metro_from_fqdn",
+		Description: "Infer the KraftCloud metro from the provided FQDN when no metro is set",
+	},
 }
 
 func ConfigDetails() []ConfigDetail {
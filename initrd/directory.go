@@ -10,9 +10,12 @@ import (
 	"io/fs"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/cavaliergopher/cpio"
+	"github.com/dustin/go-humanize"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
 
 	"kraftkit.sh/log"
 )
@@ -75,6 +78,18 @@ func (initrd *directory) Build(ctx context.Context) (string, error) {
 	writer := cpio.NewWriter(f)
 	defer writer.Close()
 
+	var ignore gitignore.Matcher
+	var ignorePrefix []string
+	if initrd.opts.ignoreFile != "" {
+		ignore, ignorePrefix, err = newIgnoreMatcher(initrd.opts.ignoreFile, initrd.path)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	var excludedFiles int
+	var excludedBytes int64
+
 	if err := filepath.WalkDir(initrd.path, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return fmt.Errorf("received error before parsing path: %w", err)
@@ -86,6 +101,22 @@ func (initrd *directory) Build(ctx context.Context) (string, error) {
 		}
 		internal = "." + filepath.ToSlash(internal)
 
+		if ignore != nil && ignore.Match(append(slices.Clone(ignorePrefix), strings.Split(internal, "/")[1:]...), d.IsDir()) {
+			files, size := excludedSize(path)
+			excludedFiles += files
+			excludedBytes += size
+
+			log.G(ctx).
+				WithField("file", internal).
+				Trace("excluding")
+
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
 		info, err := d.Info()
 		if err != nil {
 			return fmt.Errorf("could not get directory entry info: %w", err)
@@ -155,9 +186,38 @@ func (initrd *directory) Build(ctx context.Context) (string, error) {
 		return "", fmt.Errorf("could not walk output path: %w", err)
 	}
 
+	if excludedFiles > 0 {
+		log.G(ctx).
+			WithField("ignore", initrd.opts.ignoreFile).
+			Infof("excluded %d file(s) (%s) from rootfs", excludedFiles, humanize.Bytes(uint64(excludedBytes)))
+	}
+
 	return initrd.opts.output, nil
 }
 
+// excludedSize returns the number of files and their total size in bytes of
+// the provided path, which is walked if it is a directory.
+func excludedSize(path string) (int, int64) {
+	var files int
+	var size int64
+
+	_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+
+		files++
+
+		if info, err := d.Info(); err == nil && info.Mode().IsRegular() {
+			size += info.Size()
+		}
+
+		return nil
+	})
+
+	return files, size
+}
+
 // Files implements Initrd.
 func (initrd *directory) Files() []string {
 	return initrd.files
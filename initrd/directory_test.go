@@ -8,6 +8,7 @@ import (
 	"context"
 	"io"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/cavaliergopher/cpio"
@@ -96,6 +97,70 @@ func TestNewFromDirectory(t *testing.T) {
 	}
 }
 
+func TestNewFromDirectoryWithIgnoreFile(t *testing.T) {
+	workdir := t.TempDir()
+	rootDir := filepath.Join(workdir, "rootfs")
+
+	for path, content := range map[string]string{
+		".kraftignore":                     "# comment\n.git\nrootfs/node_modules/cache/\n*.conf\n",
+		"rootfs/entrypoint.sh":             "#!/bin/sh\n",
+		"rootfs/etc/app.conf":              "key=value\n",
+		"rootfs/.git/HEAD":                 "ref: refs/heads/main\n",
+		"rootfs/node_modules/cache/object": "cached\n",
+	} {
+		path = filepath.Join(workdir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal("Failed to create directory:", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal("Failed to write file:", err)
+		}
+	}
+
+	ctx := context.Background()
+
+	ird, err := initrd.NewFromDirectory(ctx, rootDir,
+		initrd.WithOutput(filepath.Join(workdir, "initramfs.cpio")),
+		initrd.WithIgnoreFile(initrd.FindIgnoreFile(workdir)),
+	)
+	if err != nil {
+		t.Fatal("NewFromDirectory:", err)
+	}
+
+	irdPath, err := ird.Build(ctx)
+	if err != nil {
+		t.Fatal("Build:", err)
+	}
+
+	r := cpio.NewReader(openFile(t, irdPath))
+
+	expectNames := map[string]bool{
+		"./entrypoint.sh": true,
+		"./etc":           true,
+		"./node_modules":  true,
+	}
+
+	for {
+		hdr, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal("Failed to read next cpio header:", err)
+		}
+
+		if !expectNames[hdr.Name] {
+			t.Error("Encountered excluded file in cpio archive:", hdr.Name)
+		}
+
+		delete(expectNames, hdr.Name)
+	}
+
+	for name := range expectNames {
+		t.Error("Expected file missing from cpio archive:", name)
+	}
+}
+
 // openFile opens a file for reading, and closes it when the test completes.
 func openFile(t *testing.T, path string) io.Reader {
 	t.Helper()
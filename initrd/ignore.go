@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+package initrd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+)
+
+// IgnoreFileNames are the names of the files, in order of precedence, which
+// list the paths to exclude from a rootfs serialized from a directory.
+var IgnoreFileNames = []string{".kraftignore", ".dockerignore"}
+
+// FindIgnoreFile returns the path of the first of IgnoreFileNames which exists
+// in the provided directory, or an empty string if there is none.
+func FindIgnoreFile(dir string) string {
+	for _, name := range IgnoreFileNames {
+		path := filepath.Join(dir, name)
+		if fi, err := os.Stat(path); err == nil && fi.Mode().IsRegular() {
+			return path
+		}
+	}
+
+	return ""
+}
+
+// IgnoreFunc returns a function which reports whether the provided path,
+// relative to the root directory and slash-separated, is excluded by the
+// provided ignore file.  Nothing is excluded if no ignore file is provided.
+func IgnoreFunc(file, root string) (func(rel string, isDir bool) bool, error) {
+	if file == "" {
+		return func(string, bool) bool { return false }, nil
+	}
+
+	matcher, prefix, err := newIgnoreMatcher(file, root)
+	if err != nil {
+		return nil, err
+	} else if matcher == nil {
+		return func(string, bool) bool { return false }, nil
+	}
+
+	return func(rel string, isDir bool) bool {
+		return matcher.Match(append(slices.Clone(prefix), strings.Split(rel, "/")...), isDir)
+	}, nil
+}
+
+// newIgnoreMatcher parses the gitignore-style patterns of the provided ignore
+// file into a matcher for the paths of the provided root directory.  Patterns
+// are relative to the directory containing the ignore file, such that only
+// those which apply beneath the root directory take effect.  A nil matcher is
+// returned if the root directory is not within the directory of the file.
+func newIgnoreMatcher(file, root string) (gitignore.Matcher, []string, error) {
+	base, err := filepath.Abs(filepath.Dir(file))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	root, err = filepath.Abs(root)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rel, err := filepath.Rel(base, root)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil, nil, nil
+	}
+
+	var prefix []string
+	if rel != "." {
+		prefix = strings.Split(filepath.ToSlash(rel), "/")
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not open ignore file: %w", err)
+	}
+
+	defer f.Close()
+
+	var patterns []gitignore.Pattern
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// Patterns of a .dockerignore may refer to its directory as `./`.
+		line = strings.TrimPrefix(line, "./")
+
+		patterns = append(patterns, gitignore.ParsePattern(line, nil))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("could not read ignore file: %w", err)
+	}
+
+	return gitignore.NewMatcher(patterns), prefix, nil
+}
@@ -0,0 +1,172 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+package initrd
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/cavaliergopher/cpio"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+
+	"kraftkit.sh/log"
+)
+
+type ociimage struct {
+	opts  InitrdOptions
+	image v1.Image
+	files []string
+}
+
+// NewFromOCIImage returns an instantiated Initrd interface which is able to
+// serialize a rootfs from the filesystem of the provided (container) image,
+// e.g. one which was pulled for the desired architecture via the OCI package
+// manager.
+func NewFromOCIImage(_ context.Context, image v1.Image, opts ...InitrdOption) (Initrd, error) {
+	if image == nil {
+		return nil, fmt.Errorf("no image provided")
+	}
+
+	rootfs := ociimage{
+		opts:  InitrdOptions{},
+		image: image,
+	}
+
+	for _, opt := range opts {
+		if err := opt(&rootfs.opts); err != nil {
+			return nil, err
+		}
+	}
+
+	return &rootfs, nil
+}
+
+// Build implements Initrd.
+func (initrd *ociimage) Build(ctx context.Context) (string, error) {
+	if initrd.opts.output == "" {
+		fi, err := os.CreateTemp("", "")
+		if err != nil {
+			return "", fmt.Errorf("could not make temporary file: %w", err)
+		}
+
+		initrd.opts.output = fi.Name()
+		err = fi.Close()
+		if err != nil {
+			return "", fmt.Errorf("could not close temporary file: %w", err)
+		}
+	}
+
+	f, err := os.OpenFile(initrd.opts.output, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return "", fmt.Errorf("could not open initramfs file: %w", err)
+	}
+
+	defer f.Close()
+
+	writer := cpio.NewWriter(f)
+	defer writer.Close()
+
+	// Flatten the layers of the image into a single filesystem.
+	rc := mutate.Extract(initrd.image)
+	defer rc.Close()
+
+	initrd.files, err = tarToCpio(ctx, rc, writer)
+	if err != nil {
+		return "", fmt.Errorf("could not extract filesystem of image: %w", err)
+	}
+
+	return initrd.opts.output, nil
+}
+
+// Files implements Initrd.
+func (initrd *ociimage) Files() []string {
+	return initrd.files
+}
+
+// tarToCpio serializes each entry of the provided tar stream to the provided
+// CPIO writer and returns the files which were archived.
+func tarToCpio(ctx context.Context, r io.Reader, writer *cpio.Writer) ([]string, error) {
+	var files []string
+
+	reader := tar.NewReader(r)
+	for {
+		hdr, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("could not read tar header: %w", err)
+		}
+
+		internal := "./" + strings.TrimPrefix(path.Clean("/"+hdr.Name), "/")
+		if internal == "./" {
+			continue // Do not archive empty paths
+		}
+
+		header := &cpio.Header{
+			Name:    internal,
+			Mode:    cpio.FileMode(hdr.FileInfo().Mode().Perm()),
+			Uid:     hdr.Uid,
+			Guid:    hdr.Gid,
+			ModTime: hdr.ModTime,
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			header.Mode |= cpio.TypeDir
+
+		case tar.TypeReg:
+			header.Mode |= cpio.TypeReg
+			header.Size = hdr.Size
+
+		case tar.TypeSymlink:
+			header.Mode |= cpio.TypeSymlink
+			header.Linkname = hdr.Linkname
+			header.Size = int64(len(hdr.Linkname))
+
+		case tar.TypeLink:
+			// CPIO archives express hard links through shared inodes which are
+			// not supported by the writer, so refer to the target via an absolute
+			// symbolic link instead.
+			header.Mode |= cpio.TypeSymlink
+			header.Linkname = path.Clean("/" + hdr.Linkname)
+			header.Size = int64(len(header.Linkname))
+
+		default:
+			log.G(ctx).Warnf("unsupported file: %s", hdr.Name)
+			continue
+		}
+
+		if hdr.Typeflag != tar.TypeDir {
+			files = append(files, internal)
+		}
+
+		log.G(ctx).
+			WithField("file", internal).
+			Trace("archiving")
+
+		if err := writer.WriteHeader(header); err != nil {
+			return nil, fmt.Errorf("writing cpio header for %q: %w", internal, err)
+		}
+
+		switch {
+		case header.Mode&^cpio.ModePerm == cpio.TypeSymlink:
+			_, err = writer.Write([]byte(header.Linkname))
+		case header.Mode.IsRegular():
+			_, err = io.Copy(writer, reader)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not write CPIO data for %s: %w", internal, err)
+		}
+	}
+
+	return files, nil
+}
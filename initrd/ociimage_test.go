@@ -0,0 +1,137 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+package initrd_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/cavaliergopher/cpio"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+
+	"kraftkit.sh/initrd"
+)
+
+func TestNewFromOCIImage(t *testing.T) {
+	ctx := context.Background()
+
+	image := newImage(t, []tarEntry{
+		{hdr: tar.Header{Name: "etc/", Typeflag: tar.TypeDir, Mode: 0o755}},
+		{hdr: tar.Header{Name: "etc/app.conf", Typeflag: tar.TypeReg, Mode: 0o644}, data: "listen 8080\n"},
+		{hdr: tar.Header{Name: "bin/", Typeflag: tar.TypeDir, Mode: 0o755}},
+		{hdr: tar.Header{Name: "bin/busybox", Typeflag: tar.TypeReg, Mode: 0o755}, data: "busybox"},
+		{hdr: tar.Header{Name: "bin/sh", Typeflag: tar.TypeSymlink, Linkname: "busybox", Mode: 0o777}},
+		{hdr: tar.Header{Name: "bin/ash", Typeflag: tar.TypeLink, Linkname: "bin/busybox", Mode: 0o755}},
+	})
+
+	ird, err := initrd.NewFromOCIImage(ctx, image)
+	if err != nil {
+		t.Fatal("NewFromOCIImage:", err)
+	}
+
+	irdPath, err := ird.Build(ctx)
+	if err != nil {
+		t.Fatal("Build:", err)
+	}
+	t.Cleanup(func() {
+		if err := os.Remove(irdPath); err != nil {
+			t.Fatal("Failed to remove initrd file:", err)
+		}
+	})
+
+	const expectFiles = 4 // only regular and symlink files are indexed
+	if gotFiles := len(ird.Files()); gotFiles != expectFiles {
+		t.Errorf("Expected %d files in InitrdConfig, got %d: %v", expectFiles, gotFiles, ird.Files())
+	}
+
+	expectHeaders := map[string]cpio.Header{
+		"./etc":          {Mode: cpio.TypeDir},
+		"./etc/app.conf": {Mode: cpio.TypeReg, Size: 12},
+		"./bin":          {Mode: cpio.TypeDir},
+		"./bin/busybox":  {Mode: cpio.TypeReg, Size: 7},
+		"./bin/sh":       {Mode: cpio.TypeSymlink, Linkname: "busybox"},
+		"./bin/ash":      {Mode: cpio.TypeSymlink, Linkname: "/bin/busybox"},
+	}
+
+	r := cpio.NewReader(openFile(t, irdPath))
+
+	for {
+		hdr, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal("Failed to read next cpio header:", err)
+		}
+
+		expectHdr, ok := expectHeaders[hdr.Name]
+		if !ok {
+			t.Error("Encountered unexpected file in cpio archive:", hdr.Name)
+			continue
+		}
+
+		if gotMode := hdr.Mode & cpio.ModeType; gotMode != expectHdr.Mode {
+			t.Errorf("file [%s]: got mode %s, expected %s", hdr.Name, gotMode, expectHdr.Mode)
+		}
+		if hdr.Linkname != expectHdr.Linkname {
+			t.Errorf("file [%s]: got linkname %q, expected %q", hdr.Name, hdr.Linkname, expectHdr.Linkname)
+		}
+		if expectHdr.Mode == cpio.TypeReg && hdr.Size != expectHdr.Size {
+			t.Errorf("file [%s]: got size %d, expected %d", hdr.Name, hdr.Size, expectHdr.Size)
+		}
+	}
+}
+
+func TestNewFromOCIImage_NoImage(t *testing.T) {
+	if _, err := initrd.NewFromOCIImage(context.Background(), nil); err == nil {
+		t.Error("expected an error without an image")
+	}
+}
+
+type tarEntry struct {
+	hdr  tar.Header
+	data string
+}
+
+// newImage returns an image with a single layer made of the provided entries.
+func newImage(t *testing.T, entries []tarEntry) v1.Image {
+	t.Helper()
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, entry := range entries {
+		entry.hdr.Size = int64(len(entry.data))
+		if err := tw.WriteHeader(&entry.hdr); err != nil {
+			t.Fatal("Failed to write tar header:", err)
+		}
+		if _, err := tw.Write([]byte(entry.data)); err != nil {
+			t.Fatal("Failed to write tar data:", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal("Failed to close tar writer:", err)
+	}
+
+	layer, err := tarball.LayerFromOpener(func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(buf.Bytes())), nil
+	})
+	if err != nil {
+		t.Fatal("Failed to create layer:", err)
+	}
+
+	image, err := mutate.AppendLayers(empty.Image, layer)
+	if err != nil {
+		t.Fatal("Failed to create image:", err)
+	}
+
+	return image
+}
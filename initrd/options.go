@@ -5,9 +5,10 @@
 package initrd
 
 type InitrdOptions struct {
-	output   string
-	cacheDir string
-	arch     string
+	output     string
+	cacheDir   string
+	arch       string
+	ignoreFile string
 }
 
 type InitrdOption func(*InitrdOptions) error
@@ -41,3 +42,14 @@ func WithArchitecture(arch string) InitrdOption {
 		return nil
 	}
 }
+
+// WithIgnoreFile sets the path of a file with gitignore-style patterns, e.g. a
+// `.kraftignore` or `.dockerignore`, of the paths to exclude when serializing a
+// rootfs from a directory.  The patterns are relative to the directory
+// containing the file.
+func WithIgnoreFile(path string) InitrdOption {
+	return func(opts *InitrdOptions) error {
+		opts.ignoreFile = path
+		return nil
+	}
+}
@@ -32,30 +32,41 @@ import (
 )
 
 type BuildOptions struct {
-	All          bool           `long:"all" usage:"Build all targets"`
-	Architecture string         `long:"arch" short:"m" usage:"Filter the creation of the build by architecture of known targets"`
-	DotConfig    string         `long:"config" short:"c" usage:"Override the path to the KConfig .config file"`
-	ForcePull    bool           `long:"force-pull" usage:"Force pulling packages before building"`
-	Jobs         int            `long:"jobs" short:"j" usage:"Allow N jobs at once"`
-	KernelDbg    bool           `long:"dbg" usage:"Build the debuggable (symbolic) kernel image instead of the stripped image"`
-	Kraftfile    string         `long:"kraftfile" short:"K" usage:"Set an alternative path of the Kraftfile"`
-	NoCache      bool           `long:"no-cache" short:"F" usage:"Force a rebuild even if existing intermediate artifacts already exist"`
-	NoConfigure  bool           `long:"no-configure" usage:"Do not run Unikraft's configure step before building"`
-	NoFast       bool           `long:"no-fast" usage:"Do not use maximum parallelization when performing the build"`
-	NoFetch      bool           `long:"no-fetch" usage:"Do not run Unikraft's fetch step before building"`
-	NoUpdate     bool           `long:"no-update" usage:"Do not update package index before running the build"`
-	Platform     string         `long:"plat" short:"p" usage:"Filter the creation of the build by platform of known targets"`
-	PrintStats   bool           `long:"print-stats" usage:"Print build statistics"`
-	Rootfs       string         `long:"rootfs" usage:"Specify a path to use as root file system (can be volume or initramfs)"`
-	SaveBuildLog string         `long:"build-log" usage:"Use the specified file to save the output from the build"`
-	Target       *target.Target `noattribute:"true"`
-	TargetName   string         `long:"target" short:"t" usage:"Build a particular known target"`
-	Workdir      string         `noattribute:"true"`
+	All          bool              `long:"all" usage:"Build all targets"`
+	Architecture string            `long:"arch" short:"m" usage:"Filter the creation of the build by architecture of known targets"`
+	DotConfig    string            `long:"config" short:"c" usage:"Override the path to the KConfig .config file"`
+	ForcePull    bool              `long:"force-pull" usage:"Force pulling packages before building (same as --pull-policy=always)"`
+	Jobs         int               `long:"jobs" short:"j" usage:"Allow N jobs at once (default is one per CPU)"`
+	KernelDbg    bool              `long:"dbg" usage:"Build the debuggable (symbolic) kernel image instead of the stripped image"`
+	Kraftfile    string            `long:"kraftfile" short:"K" usage:"Set an alternative path of the Kraftfile"`
+	NoCache      bool              `long:"no-cache" short:"F" usage:"Force a rebuild even if existing intermediate artifacts already exist"`
+	NoConfigure  bool              `long:"no-configure" usage:"Do not run Unikraft's configure step before building"`
+	NoFast       bool              `long:"no-fast" usage:"Build with a single job, overriding --jobs"`
+	NoFetch      bool              `long:"no-fetch" usage:"Do not run Unikraft's fetch step before building"`
+	NoUpdate     bool              `long:"no-update" usage:"Do not update package index before running the build"`
+	Platform     string            `long:"plat" short:"p" usage:"Filter the creation of the build by platform of known targets"`
+	PrintStats   bool              `long:"print-stats" usage:"Print build statistics"`
+	PullPolicy   PullPolicy        `noattribute:"true"`
+	QuietBuild   bool              `long:"quiet-build" usage:"Print a single line per step instead of rendering the progress, and show the output from the build only if it fails"`
+	Rootfs       string            `long:"rootfs" usage:"Specify a path to use as root file system (can be volume or initramfs)"`
+	SaveBuildLog string            `long:"build-log" usage:"Use the specified file to save the output from the build, or - for stdout"`
+	Target       *target.Target    `noattribute:"true"`
+	TargetName   string            `long:"target" short:"t" usage:"Build a particular known target"`
+	Timings      utils.StepTimings `noattribute:"true"`
+	VerboseBuild bool              `long:"verbose-build" usage:"Stream the output from the build to the terminal as it is produced"`
+	Workdir      string            `noattribute:"true"`
 
 	project    app.Application
 	statistics map[string]string
 }
 
+// The steps of a build whose duration is recorded in BuildOptions.Timings.
+const (
+	StepFetch     = "fetch"
+	StepConfigure = "configure"
+	StepBuild     = "build"
+)
+
 // Build a Unikraft unikernel.
 func Build(ctx context.Context, opts *BuildOptions, args ...string) error {
 	var err error
@@ -91,6 +102,14 @@ func Build(ctx context.Context, opts *BuildOptions, args ...string) error {
 		return fmt.Errorf("could not initialize project directory: %w", err)
 	}
 
+	if err := opts.resolvePullPolicy(); err != nil {
+		return err
+	}
+
+	if opts.QuietBuild && opts.VerboseBuild {
+		return fmt.Errorf("cannot use --quiet-build with --verbose-build")
+	}
+
 	opts.Platform = platform.PlatformByName(opts.Platform).String()
 	opts.statistics = map[string]string{}
 
@@ -148,11 +167,16 @@ func Build(ctx context.Context, opts *BuildOptions, args ...string) error {
 
 	log.G(ctx).WithField("builder", build.String()).Debug("using")
 
-	if err := build.Prepare(ctx, opts, args...); err != nil {
+	if err := opts.Timings.Time(StepFetch, func() error {
+		return build.Prepare(ctx, opts, args...)
+	}); err != nil {
 		return fmt.Errorf("could not complete build: %w", err)
 	}
 
-	if opts.Rootfs, err = utils.BuildRootfs(ctx, opts.Workdir, opts.Rootfs, *opts.Target); err != nil {
+	if err := opts.Timings.Time(utils.StepPackage, func() (err error) {
+		opts.Rootfs, err = utils.BuildRootfs(ctx, opts.Workdir, opts.Rootfs, *opts.Target)
+		return err
+	}); err != nil {
 		return err
 	}
 
@@ -186,6 +210,9 @@ func NewCmd() *cobra.Command {
 
 			# Build path to a Unikraft project
 			$ kraft build path/to/app
+
+			# Build the current project without accessing the network
+			$ kraft build --pull-policy never
 		`),
 		Annotations: map[string]string{
 			cmdfactory.AnnotationHelpGroup: "build",
@@ -195,6 +222,15 @@ func NewCmd() *cobra.Command {
 		panic(err)
 	}
 
+	cmd.Flags().Var(
+		cmdfactory.NewEnumFlag[PullPolicy](
+			PullPolicies(),
+			PullPolicyMissing,
+		),
+		"pull-policy",
+		"When to pull the packages of the project before building.",
+	)
+
 	return cmd
 }
 
@@ -204,6 +240,10 @@ func (opts *BuildOptions) Pre(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	if cmd.Flags().Changed("pull-policy") {
+		opts.PullPolicy = PullPolicy(cmd.Flag("pull-policy").Value.String())
+	}
+
 	cmd.SetContext(ctx)
 
 	return nil
@@ -7,6 +7,7 @@ package build
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	plainexec "os/exec"
 	"regexp"
@@ -88,7 +89,13 @@ func (build *builderKraftfileUnikraft) pull(ctx context.Context, opts *BuildOpti
 	auths := config.G[config.KraftKit](ctx).Auth
 
 	if template := opts.project.Template(); template != nil {
-		if stat, err := os.Stat(template.Path()); err != nil || !stat.IsDir() || opts.ForcePull {
+		if stat, err := os.Stat(template.Path()); err != nil || !stat.IsDir() || opts.PullPolicy == PullPolicyAlways {
+			if opts.PullPolicy == PullPolicyNever {
+				return fmt.Errorf("template %s is not available locally and --pull-policy=never is set",
+					unikraft.TypeNameVersion(template),
+				)
+			}
+
 			var templatePack pack.Package
 			var packs []pack.Package
 
@@ -221,8 +228,8 @@ func (build *builderKraftfileUnikraft) pull(ctx context.Context, opts *BuildOpti
 		}
 
 		// Only continue to find and pull the component if it does not exist
-		// locally or the user has requested to --force-pull.
-		if stat, err := os.Stat(component.Path()); err == nil && stat.IsDir() && !opts.ForcePull {
+		// locally or the user has requested to always pull.
+		if stat, err := os.Stat(component.Path()); err == nil && stat.IsDir() && opts.PullPolicy != PullPolicyAlways {
 			continue
 		}
 
@@ -233,6 +240,12 @@ func (build *builderKraftfileUnikraft) pull(ctx context.Context, opts *BuildOpti
 			continue
 		}
 
+		if opts.PullPolicy == PullPolicyNever {
+			return fmt.Errorf("component %s is not available locally and --pull-policy=never is set",
+				unikraft.TypeNameVersion(component),
+			)
+		}
+
 		searches = append(searches, processtree.NewProcessTreeItem(
 			fmt.Sprintf("finding %s",
 				unikraft.TypeNameVersion(component),
@@ -328,7 +341,7 @@ func (build *builderKraftfileUnikraft) pull(ctx context.Context, opts *BuildOpti
 
 func (build *builderKraftfileUnikraft) Prepare(ctx context.Context, opts *BuildOptions, args ...string) error {
 	build.nameWidth = -1
-	norender := log.LoggerTypeFromString(config.G[config.KraftKit](ctx).Log.Type) != log.FANCY
+	norender := opts.norender(ctx)
 
 	// Calculate the width of the longest process name so that we can align the
 	// two independent processtrees if we are using "render" mode (aka the fancy
@@ -356,7 +369,7 @@ func (build *builderKraftfileUnikraft) Prepare(ctx context.Context, opts *BuildO
 		}
 	}
 
-	if opts.ForcePull || !opts.NoUpdate {
+	if opts.PullPolicy == PullPolicyAlways || (opts.PullPolicy != PullPolicyNever && !opts.NoUpdate) {
 		model, err := processtree.NewProcessTree(
 			ctx,
 			[]processtree.ProcessTreeOption{
@@ -387,49 +400,89 @@ func (build *builderKraftfileUnikraft) Prepare(ctx context.Context, opts *BuildO
 
 func (build *builderKraftfileUnikraft) Build(ctx context.Context, opts *BuildOptions, args ...string) error {
 	var processes []*paraprogress.Process
-	var mopts []make.MakeOption
-	if opts.Jobs > 0 {
-		mopts = append(mopts, make.WithJobs(opts.Jobs))
+	fast := !opts.NoFast && !config.G[config.KraftKit](ctx).NoParallel
+	jobs := make.ResolveJobs(opts.Jobs, fast)
+
+	log.G(ctx).Infof("building with %d job(s), fast=%t", jobs, fast)
+
+	mopts := []make.MakeOption{make.WithJobs(jobs)}
+
+	// With --quiet-build, the output of the build is collected and only shown if
+	// it fails.
+	var stdout, stderr io.Writer
+	var quietLog *quietBuildLog
+	if opts.QuietBuild {
+		quietLog = &quietBuildLog{}
+		stdout, stderr = quietLog, quietLog
 	} else {
-		mopts = append(mopts, make.WithMaxJobs(!opts.NoFast && !config.G[config.KraftKit](ctx).NoParallel))
+		stdout, stderr = log.G(ctx).Writer(), log.G(ctx).WriterLevel(logrus.WarnLevel)
 	}
 
 	if !opts.NoConfigure {
 		processes = append(processes, paraprogress.NewProcess(
 			fmt.Sprintf("configuring %s (%s)", (*opts.Target).Name(), target.TargetPlatArchName(*opts.Target)),
 			func(ctx context.Context, w func(progress float64)) error {
-				return opts.project.Configure(
-					ctx,
-					*opts.Target, // Target-specific options
-					nil,          // No extra configuration options
-					make.WithProgressFunc(w),
-					make.WithSilent(true),
-					make.WithExecOptions(
-						exec.WithStdin(iostreams.G(ctx).In),
-						exec.WithStdout(log.G(ctx).Writer()),
-						exec.WithStderr(log.G(ctx).WriterLevel(logrus.WarnLevel)),
-					),
-				)
+				return opts.Timings.Time(StepConfigure, func() error {
+					return opts.project.Configure(
+						ctx,
+						*opts.Target, // Target-specific options
+						nil,          // No extra configuration options
+						make.WithProgressFunc(w),
+						make.WithSilent(true),
+						make.WithExecOptions(
+							exec.WithStdin(iostreams.G(ctx).In),
+							exec.WithStdout(stdout),
+							exec.WithStderr(stderr),
+						),
+					)
+				})
 			},
 		))
 	}
 
+	// Streaming the output from the build to the terminal would otherwise be
+	// garbled by the fancy renderer.
+	norender := opts.norender(ctx)
+	if opts.VerboseBuild || opts.SaveBuildLog == "-" {
+		norender = true
+	}
+
+	if opts.VerboseBuild {
+		mopts = append(mopts, make.WithExecOptions(
+			exec.WithStdoutCallback(iostreams.G(ctx).Out),
+			exec.WithStderrCallback(iostreams.G(ctx).ErrOut),
+		))
+	}
+
 	processes = append(processes, paraprogress.NewProcess(
 		fmt.Sprintf("building %s (%s)", (*opts.Target).Name(), target.TargetPlatArchName(*opts.Target)),
 		func(ctx context.Context, w func(progress float64)) error {
-			err := opts.project.Build(
-				ctx,
-				*opts.Target, // Target-specific options
-				app.WithBuildProgressFunc(w),
-				app.WithBuildMakeOptions(append(mopts,
-					make.WithExecOptions(
-						exec.WithStdout(log.G(ctx).Writer()),
-						exec.WithStderr(log.G(ctx).WriterLevel(logrus.WarnLevel)),
-						// exec.WithOSEnv(true),
-					),
-				)...),
-				app.WithBuildLogFile(opts.SaveBuildLog),
-			)
+			buildLog := app.WithBuildLogFile(opts.SaveBuildLog)
+			if opts.SaveBuildLog == "-" {
+				// The output is already streamed to stdout with --verbose-build.
+				var out io.Writer
+				if !opts.VerboseBuild {
+					out = iostreams.G(ctx).Out
+				}
+
+				buildLog = app.WithBuildLogWriter(out)
+			}
+
+			err := opts.Timings.Time(StepBuild, func() error {
+				return opts.project.Build(
+					ctx,
+					*opts.Target, // Target-specific options
+					app.WithBuildProgressFunc(w),
+					app.WithBuildMakeOptions(append(mopts,
+						make.WithExecOptions(
+							exec.WithStdout(stdout),
+							exec.WithStderr(stderr),
+							// exec.WithOSEnv(true),
+						),
+					)...),
+					buildLog,
+				)
+			})
 			if err != nil {
 				return fmt.Errorf("build failed: %w", err)
 			}
@@ -447,7 +500,7 @@ func (build *builderKraftfileUnikraft) Build(ctx context.Context, opts *BuildOpt
 		//  - The Unikraft build system can re-use compiled files from previous
 		//    compilations (if the architecture does not change).
 		paraprogress.IsParallel(false),
-		paraprogress.WithRenderer(log.LoggerTypeFromString(config.G[config.KraftKit](ctx).Log.Type) != log.FANCY),
+		paraprogress.WithRenderer(norender),
 		paraprogress.WithFailFast(true),
 		paraprogress.WithNameWidth(build.nameWidth),
 	)
@@ -455,7 +508,15 @@ func (build *builderKraftfileUnikraft) Build(ctx context.Context, opts *BuildOpt
 		return err
 	}
 
-	return paramodel.Start()
+	if err := paramodel.Start(); err != nil {
+		if quietLog != nil {
+			quietLog.dump(ctx)
+		}
+
+		return err
+	}
+
+	return nil
 }
 
 func (build *builderKraftfileUnikraft) Statistics(ctx context.Context, opts *BuildOptions, args ...string) error {
@@ -476,7 +537,7 @@ func (build *builderKraftfileUnikraft) Statistics(ctx context.Context, opts *Bui
 		ctx,
 		processes,
 		paraprogress.IsParallel(false),
-		paraprogress.WithRenderer(log.LoggerTypeFromString(config.G[config.KraftKit](ctx).Log.Type) != log.FANCY),
+		paraprogress.WithRenderer(opts.norender(ctx)),
 		paraprogress.WithFailFast(true),
 		paraprogress.WithNameWidth(build.nameWidth),
 	)
@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+package build
+
+import (
+	"fmt"
+)
+
+// PullPolicy describes when the packages which a project depends on are pulled
+// before building.
+type PullPolicy string
+
+const (
+	// The 'always' policy updates the package index and pulls all packages,
+	// even if they are already available locally.
+	PullPolicyAlways = PullPolicy("always")
+
+	// The 'missing' policy only pulls the packages which are not available
+	// locally.
+	PullPolicyMissing = PullPolicy("missing")
+
+	// The 'never' policy does not access the network and fails if any of the
+	// packages is not available locally.
+	PullPolicyNever = PullPolicy("never")
+)
+
+var _ fmt.Stringer = (*PullPolicy)(nil)
+
+// String implements fmt.Stringer
+func (policy PullPolicy) String() string {
+	return string(policy)
+}
+
+// PullPolicies returns the list of possible pull policies.
+func PullPolicies() []PullPolicy {
+	return []PullPolicy{
+		PullPolicyAlways,
+		PullPolicyMissing,
+		PullPolicyNever,
+	}
+}
+
+// ResolvePullPolicy reconciles the `--pull-policy` and the legacy
+// `--force-pull` flags, the latter of which is equivalent to 'always'.  An
+// unset policy defaults to 'missing'.
+func ResolvePullPolicy(policy PullPolicy, forcePull bool) (PullPolicy, error) {
+	switch policy {
+	case "":
+		if forcePull {
+			return PullPolicyAlways, nil
+		}
+
+		return PullPolicyMissing, nil
+
+	case PullPolicyAlways, PullPolicyMissing, PullPolicyNever:
+		if forcePull && policy != PullPolicyAlways {
+			return "", fmt.Errorf("cannot use --force-pull with --pull-policy=%s", policy)
+		}
+
+		return policy, nil
+
+	default:
+		return "", fmt.Errorf("unknown pull policy '%s'", policy)
+	}
+}
+
+// resolvePullPolicy resolves the pull policy of the build in place.
+func (opts *BuildOptions) resolvePullPolicy() error {
+	policy, err := ResolvePullPolicy(opts.PullPolicy, opts.ForcePull)
+	if err != nil {
+		return err
+	}
+
+	opts.PullPolicy = policy
+
+	return nil
+}
@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package build
+
+import "testing"
+
+func TestResolvePullPolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    PullPolicy
+		forcePull bool
+		expected  PullPolicy
+		err       bool
+	}{
+		{name: "default", expected: PullPolicyMissing},
+		{name: "force pull", forcePull: true, expected: PullPolicyAlways},
+		{name: "always", policy: PullPolicyAlways, expected: PullPolicyAlways},
+		{name: "missing", policy: PullPolicyMissing, expected: PullPolicyMissing},
+		{name: "never", policy: PullPolicyNever, expected: PullPolicyNever},
+		{name: "always with force pull", policy: PullPolicyAlways, forcePull: true, expected: PullPolicyAlways},
+		{name: "missing with force pull", policy: PullPolicyMissing, forcePull: true, err: true},
+		{name: "never with force pull", policy: PullPolicyNever, forcePull: true, err: true},
+		{name: "unknown", policy: PullPolicy("sometimes"), err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ResolvePullPolicy(tt.policy, tt.forcePull)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("ResolvePullPolicy(%q, %t) = %q, expected an error", tt.policy, tt.forcePull, actual)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolvePullPolicy(%q, %t) returned unexpected error: %v", tt.policy, tt.forcePull, err)
+			}
+			if actual != tt.expected {
+				t.Errorf("ResolvePullPolicy(%q, %t) = %q, expected %q", tt.policy, tt.forcePull, actual, tt.expected)
+			}
+		})
+	}
+}
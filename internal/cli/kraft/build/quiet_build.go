@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package build
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+
+	"kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+	"kraftkit.sh/log"
+)
+
+// norender returns whether the progress of the build should be printed as
+// plain log lines instead of being rendered interactively.
+func (opts *BuildOptions) norender(ctx context.Context) bool {
+	return opts.QuietBuild || log.LoggerTypeFromString(config.G[config.KraftKit](ctx).Log.Type) != log.FANCY
+}
+
+// quietBuildLog collects the output of the build when using --quiet-build such
+// that it is only shown if the build fails.
+type quietBuildLog struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// Write implements io.Writer.  It is safe to call concurrently, since the
+// standard output and standard error of the build are both written to it.
+func (l *quietBuildLog) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.buf.Write(p)
+}
+
+// dump prints the collected output of the build to the standard error.
+func (l *quietBuildLog) dump(ctx context.Context) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.buf.Len() == 0 {
+		return
+	}
+
+	fmt.Fprintln(iostreams.G(ctx).ErrOut, "build log:")
+	_, _ = l.buf.WriteTo(iostreams.G(ctx).ErrOut)
+}
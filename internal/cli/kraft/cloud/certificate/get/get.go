@@ -21,7 +21,7 @@ import (
 )
 
 type GetOptions struct {
-	Output string `long:"output" short:"o" usage:"Set output format. Options: table,yaml,json,list" default:"table"`
+	Output string `long:"output" short:"o" usage:"Set output format. Options: table,yaml,json,list,go-template=TEMPLATE" default:"table"`
 
 	metro string
 	token string
@@ -64,6 +64,10 @@ func NewCmd() *cobra.Command {
 }
 
 func (opts *GetOptions) Pre(cmd *cobra.Command, _ []string) error {
+	if err := utils.ValidateOutput(opts.Output); err != nil {
+		return err
+	}
+
 	err := utils.PopulateMetroToken(cmd, &opts.metro, &opts.token)
 	if err != nil {
 		return fmt.Errorf("could not populate metro and token: %w", err)
@@ -80,6 +84,7 @@ func (opts *GetOptions) Run(ctx context.Context, args []string) error {
 
 	client := kraftcloud.NewCertificatesClient(
 		kraftcloud.WithToken(config.GetKraftCloudTokenAuthConfig(*auth)),
+		kraftcloud.WithHTTPClient(utils.NewHTTPClient(ctx)),
 	)
 
 	var certs []kccerts.GetResponseItem
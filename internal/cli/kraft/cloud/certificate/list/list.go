@@ -16,10 +16,12 @@ import (
 	"kraftkit.sh/cmdfactory"
 	"kraftkit.sh/config"
 	"kraftkit.sh/internal/cli/kraft/cloud/utils"
+	"kraftkit.sh/internal/tableprinter"
 )
 
 type ListOptions struct {
-	Output string `long:"output" short:"o" usage:"Set output format. Options: table,yaml,json,list" default:"table"`
+	Columns []string `long:"columns" usage:"Only show the given comma-separated columns, in the given order"`
+	Output  string   `long:"output" short:"o" usage:"Set output format. Options: table,yaml,json,jsonl,list,go-template=TEMPLATE" default:"table"`
 
 	metro string
 	token string
@@ -50,6 +52,10 @@ func NewCmd() *cobra.Command {
 }
 
 func (opts *ListOptions) Pre(cmd *cobra.Command, _ []string) error {
+	if err := utils.ValidateOutput(opts.Output); err != nil {
+		return err
+	}
+
 	err := utils.PopulateMetroToken(cmd, &opts.metro, &opts.token)
 	if err != nil {
 		return fmt.Errorf("could not populate metro and token: %w", err)
@@ -59,6 +65,8 @@ func (opts *ListOptions) Pre(cmd *cobra.Command, _ []string) error {
 }
 
 func (opts *ListOptions) Run(ctx context.Context, args []string) error {
+	ctx = tableprinter.WithColumnsInContext(ctx, opts.Columns)
+
 	auth, err := config.GetKraftCloudAuthConfig(ctx, opts.token)
 	if err != nil {
 		return fmt.Errorf("could not retrieve credentials: %w", err)
@@ -66,6 +74,7 @@ func (opts *ListOptions) Run(ctx context.Context, args []string) error {
 
 	client := kraftcloud.NewCertificatesClient(
 		kraftcloud.WithToken(config.GetKraftCloudTokenAuthConfig(*auth)),
+		kraftcloud.WithHTTPClient(utils.NewHTTPClient(ctx)),
 	)
 
 	certListResp, err := client.WithMetro(opts.metro).List(ctx)
@@ -91,6 +91,7 @@ func (opts *RemoveOptions) Run(ctx context.Context, args []string) error {
 
 	client := kraftcloud.NewCertificatesClient(
 		kraftcloud.WithToken(config.GetKraftCloudTokenAuthConfig(*auth)),
+		kraftcloud.WithHTTPClient(utils.NewHTTPClient(ctx)),
 	)
 
 	if opts.All {
@@ -114,25 +115,17 @@ func (opts *RemoveOptions) Run(ctx context.Context, args []string) error {
 
 	log.G(ctx).Infof("Removing %d certificate(s)", len(args))
 
-	allUUIDs := true
-	allNames := true
-	for _, arg := range args {
-		if utils.IsUUID(arg) {
-			allNames = false
-		} else {
-			allUUIDs = false
-		}
-		if !(allUUIDs || allNames) {
-			break
-		}
+	uuids, names, err := utils.ClassifyArgs(args)
+	if err != nil {
+		return err
 	}
 
 	switch {
-	case allUUIDs:
+	case len(names) == 0:
 		if _, err := client.WithMetro(opts.metro).DeleteByUUIDs(ctx, args...); err != nil {
 			return fmt.Errorf("removing %d certificate(s): %w", len(args), err)
 		}
-	case allNames:
+	case len(uuids) == 0:
 		if _, err := client.WithMetro(opts.metro).DeleteByNames(ctx, args...); err != nil {
 			return fmt.Errorf("removing %d certificate(s): %w", len(args), err)
 		}
@@ -7,6 +7,7 @@ package cloud
 
 import (
 	"context"
+	"time"
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/spf13/cobra"
@@ -26,8 +27,10 @@ import (
 )
 
 type CloudOptions struct {
-	Metro string `long:"metro" env:"KRAFTCLOUD_METRO" usage:"Set the KraftCloud metro"`
-	Token string `long:"token" env:"KRAFTCLOUD_TOKEN" usage:"Set the KraftCloud token"`
+	Metro         string        `long:"metro" env:"KRAFTCLOUD_METRO" usage:"Set the KraftCloud metro"`
+	Timeout       time.Duration `long:"timeout" usage:"Set the timeout for each remote procedure call, 0 to disable (ms/s/m/h)"`
+	Token         string        `long:"token" env:"KRAFTCLOUD_TOKEN" usage:"Set the KraftCloud token, or - to read it from stdin"`
+	TokenFromFile string        `long:"token-from-file" usage:"Read the KraftCloud token from a file"`
 }
 
 func NewCmd() *cobra.Command {
@@ -47,6 +50,48 @@ func NewCmd() *cobra.Command {
 
 			Set authentication by using %[1]skraft login%[1]s or set
 			%[1]sKRAFTCLOUD_TOKEN%[1]s environmental variable.
+
+			To keep the token out of the shell history and process listings, read it
+			from a file with %[1]s--token-from-file PATH%[1]s, e.g. a mounted CI secret,
+			or from the standard input with %[1]s--token -%[1]s.  The token is taken,
+			in order of precedence, from the %[1]s--token%[1]s flag, the token file,
+			the standard input, %[1]sKRAFTCLOUD_TOKEN%[1]s and finally the configuration.
+
+			The metro last deployed to from a project directory (i.e. one with a
+			Kraftfile) is recorded in its %[1]s.kraft/cloud.yaml%[1]s file and is used
+			by subsequent commands in that directory when it is not otherwise set.
+			The token is recorded likewise, but in the %[1]scloud-tokens.yaml%[1]s file
+			of the KraftKit configuration directory, such that it is never part of
+			the project itself.
+
+			Use %[1]s--timeout%[1]s to bound each call to the KraftCloud API made by any
+			subcommand, e.g. %[1]skraft cloud --timeout 30s instance list%[1]s.  Subcommands
+			which define their own %[1]s--timeout%[1]s flag, such as %[1]sdeploy%[1]s,
+			%[1]sinstance stop%[1]s and %[1]sinstance remove%[1]s, use theirs instead.
+
+			The %[1]sdeploy%[1]s, %[1]sinstance stop%[1]s and %[1]sinstance remove%[1]s commands exit
+			with a code which indicates why they failed:
+
+			  2  authentication failed
+			  3  a resource was not found
+			  4  a quota was exceeded
+			  5  a resource conflicts with an existing one
+
+			Any other failure exits with the code 1.
+
+			Commands which support %[1]s--output list%[1]s print each item on a single
+			line without a header, with its fields separated by a tab and in the
+			same order as the columns of the table.  Fields may contain spaces but
+			never tabs, such that the output can be parsed by splitting on tabs,
+			e.g. with %[1]sawk -F'\t'%[1]s or %[1]scut%[1]s.  Empty fields are printed as %[1]s-%[1]s,
+			and fields which are %[1]s-%[1]s or start with %[1]s\%[1]s are prefixed with %[1]s\%[1]s.
+			Use %[1]s--columns%[1]s on list commands to select the fields.
+
+			Commands which support %[1]s--output go-template=TEMPLATE%[1]s evaluate the Go
+			template once for each item.  The fields of instances, volumes, service
+			groups, certificates and quotas are accessible by their Go names, e.g.
+			%[1]s{{.Name}} {{.PrivateFQDN}}%[1]s; those of other commands by their column
+			header in the same style, e.g. %[1]s{{.CreatedAt}}%[1]s for %[1]sCREATED AT%[1]s.
 		`, "`"),
 		Example: heredoc.Doc(`
 			# List all images in your account
@@ -62,7 +107,7 @@ func NewCmd() *cobra.Command {
 				nginx:latest
 
 			# Get the status of an instance based on its UUID and output as JSON
-			$ kraft cloud --metro fra0 instance status -o json UUID
+			$ kraft cloud --metro fra0 instance get -o json UUID
 
 			# Stop an instance based on its UUID
 			$ kraft cloud instance stop UUID
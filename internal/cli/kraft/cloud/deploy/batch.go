@@ -0,0 +1,404 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package deploy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	kcinstances "sdk.kraft.cloud/instances"
+	kcservices "sdk.kraft.cloud/services"
+
+	"kraftkit.sh/internal/cli/kraft/cloud/utils"
+	kraftutils "kraftkit.sh/internal/cli/kraft/utils"
+	"kraftkit.sh/internal/tableprinter"
+	"kraftkit.sh/iostreams"
+	"kraftkit.sh/log"
+)
+
+// batchEntry describes one of the deployments read via `--from-json`.  Unset
+// fields default to the values of the corresponding flags.
+type batchEntry struct {
+	Image  string            `json:"image"`
+	Name   string            `json:"name,omitempty"`
+	Metro  string            `json:"metro,omitempty"`
+	Memory batchSize         `json:"memory,omitempty"`
+	Ports  []string          `json:"ports,omitempty"`
+	Env    map[string]string `json:"env,omitempty"`
+	Args   []string          `json:"args,omitempty"`
+}
+
+// batchSize is a size in MiB, given either as a number or as a string with a
+// unit, e.g. "512Mi".
+type batchSize int
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (size *batchSize) UnmarshalJSON(data []byte) error {
+	var mb int
+	if err := json.Unmarshal(data, &mb); err == nil {
+		*size = batchSize(mb)
+		return nil
+	}
+
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return errors.New("memory must be a number or a string")
+	}
+
+	mb, err := kraftutils.ParseSizeMB(str)
+	if err != nil {
+		return err
+	}
+
+	*size = batchSize(mb)
+
+	return nil
+}
+
+// batchResult is the outcome of the deployment of an entry, reported once
+// per created instance.
+type batchResult struct {
+	Entry int    `json:"entry"`
+	Image string `json:"image"`
+	Metro string `json:"metro,omitempty"`
+	Name  string `json:"name,omitempty"`
+	UUID  string `json:"uuid,omitempty"`
+	FQDN  string `json:"fqdn,omitempty"`
+	State string `json:"state"`
+	Error string `json:"error,omitempty"`
+}
+
+// The states reported for entries which did not result in an instance.
+const (
+	batchStateFailed  = "failed"
+	batchStateSkipped = "skipped"
+)
+
+// readBatch reads the entries from the file provided via `--from-json`, or
+// from stdin if it is `-`.  The file contains either a single entry or a list
+// of entries.  Each entry is validated, and the error of each invalid entry
+// is returned at its index.
+func (opts *DeployOptions) readBatch(ctx context.Context) ([]batchEntry, []error, error) {
+	var data []byte
+	var err error
+
+	if opts.FromJSON == stdinArg {
+		data, err = io.ReadAll(iostreams.G(ctx).In)
+	} else {
+		data, err = os.ReadFile(opts.FromJSON)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not read deployments: %w", err)
+	}
+
+	data = bytes.TrimSpace(data)
+
+	var raws []json.RawMessage
+	if len(data) > 0 && data[0] == '{' {
+		raws = []json.RawMessage{data}
+	} else if err := json.Unmarshal(data, &raws); err != nil {
+		return nil, nil, fmt.Errorf("could not parse deployments: expected an object or a list of objects: %w", err)
+	}
+
+	if len(raws) == 0 {
+		return nil, nil, errors.New("no deployments were provided")
+	}
+
+	entries := make([]batchEntry, len(raws))
+	errs := make([]error, len(raws))
+
+	for i, raw := range raws {
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.DisallowUnknownFields()
+
+		if err := dec.Decode(&entries[i]); err != nil {
+			errs[i] = fmt.Errorf("invalid deployment: %w", err)
+			continue
+		}
+
+		errs[i] = entries[i].validate()
+	}
+
+	return entries, errs, nil
+}
+
+// validate checks the fields of the entry which are not validated by the
+// deployment itself before anything is created.
+func (entry *batchEntry) validate() error {
+	if entry.Image == "" {
+		return errors.New("invalid deployment: missing image")
+	}
+
+	if entry.Memory < 0 {
+		return errors.New("invalid deployment: memory must be positive")
+	}
+
+	for _, port := range entry.Ports {
+		if _, err := utils.ParsePort(port); err != nil {
+			return fmt.Errorf("invalid deployment: %w", err)
+		}
+	}
+
+	for key := range entry.Env {
+		if key == "" || strings.Contains(key, "=") {
+			return fmt.Errorf("invalid deployment: invalid env key '%s'", key)
+		}
+	}
+
+	return nil
+}
+
+// forEntry returns a copy of the options which deploys the provided entry.
+// The environmental variables provided via flags, including labels and
+// annotations, apply to every entry.
+func (opts *DeployOptions) forEntry(entry batchEntry) *DeployOptions {
+	eopts := *opts
+	eopts.DeployAs = (&deployerImageName{}).Name()
+	eopts.Name = entry.Name
+	eopts.instanceMetros = nil
+	eopts.replaceFirst = false
+	eopts.replacing = nil
+	eopts.conflicting = nil
+	eopts.deployName = ""
+
+	if entry.Metro != "" {
+		eopts.Metro = entry.Metro
+		eopts.Metros = []string{entry.Metro}
+	}
+
+	if entry.Memory > 0 {
+		eopts.Memory = int(entry.Memory)
+	}
+
+	if len(entry.Ports) > 0 {
+		eopts.Ports = entry.Ports
+	}
+
+	keys := make([]string, 0, len(entry.Env))
+	for key := range entry.Env {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	eopts.Env = make([]string, 0, len(keys)+len(opts.Env))
+	for _, key := range keys {
+		eopts.Env = append(eopts.Env, key+"="+entry.Env[key])
+	}
+
+	eopts.Env = append(eopts.Env, opts.Env...)
+
+	return &eopts
+}
+
+// runBatch deploys each of the entries provided via `--from-json` in turn and
+// reports the result of each.  Unless `--continue-on-error` is set, an invalid
+// entry fails the whole batch before anything is deployed, and a failed
+// deployment skips the remaining entries.
+func (opts *DeployOptions) runBatch(ctx context.Context, args []string) error {
+	if len(args) > 0 {
+		return errors.New("cannot provide an image or project with --from-json")
+	}
+
+	entries, errs, err := opts.readBatch(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The fields of an entry may contradict the flags which apply to it.
+	for i, entry := range entries {
+		if errs[i] == nil {
+			errs[i] = opts.forEntry(entry).checkOptions()
+		}
+	}
+
+	if !opts.ContinueOnError {
+		for i, err := range errs {
+			if err != nil {
+				return fmt.Errorf("entry %d: %w", i, err)
+			}
+		}
+	}
+
+	var results []batchResult
+	var insts []kcinstances.GetResponseItem
+	var sgs []kcservices.GetResponseItem
+	var failed int
+
+	for i, entry := range entries {
+		if ctx.Err() != nil || (failed > 0 && !opts.ContinueOnError) {
+			results = append(results, batchResult{
+				Entry: i,
+				Image: entry.Image,
+				Name:  entry.Name,
+				State: batchStateSkipped,
+			})
+			continue
+		}
+
+		err := errs[i]
+
+		var einsts []kcinstances.GetResponseItem
+		var esgs []kcservices.GetResponseItem
+
+		eopts := opts.forEntry(entry)
+
+		if err == nil {
+			log.G(ctx).
+				WithField("image", entry.Image).
+				Infof("deploying entry %d", i)
+
+			einsts, esgs, err = eopts.deploy(ctx, append([]string{entry.Image}, entry.Args...))
+		}
+
+		for _, inst := range einsts {
+			results = append(results, batchResult{
+				Entry: i,
+				Image: entry.Image,
+				Metro: eopts.metroOf(inst),
+				Name:  inst.Name,
+				UUID:  inst.UUID,
+				FQDN:  inst.FQDN,
+				State: inst.State,
+			})
+		}
+
+		insts = append(insts, einsts...)
+		sgs = append(sgs, esgs...)
+
+		if err != nil {
+			failed++
+
+			results = append(results, batchResult{
+				Entry: i,
+				Image: entry.Image,
+				Metro: strings.Join(eopts.Metros, ","),
+				Name:  entry.Name,
+				State: batchStateFailed,
+				Error: err.Error(),
+			})
+		}
+	}
+
+	if ctx.Err() == nil {
+		opts.created.settle()
+	}
+
+	if len(opts.OutputFile) > 0 {
+		if err := writeOutputFile(opts.OutputFile, insts, sgs, nil); err != nil {
+			return fmt.Errorf("could not write output file: %w", err)
+		}
+	}
+
+	if err := printBatchResults(ctx, opts.Output, results); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d deployment(s) failed", failed, len(entries))
+	}
+
+	return nil
+}
+
+// printBatchResults prints the results of the deployment of a batch in the
+// provided output format.
+func printBatchResults(ctx context.Context, format string, results []batchResult) error {
+	switch {
+	case format == "json":
+		b, err := json.Marshal(results)
+		if err != nil {
+			return fmt.Errorf("serializing data to JSON: %w", err)
+		}
+
+		fmt.Fprintln(iostreams.G(ctx).Out, string(b))
+
+		return nil
+
+	case format == "jsonl":
+		for _, result := range results {
+			b, err := json.Marshal(result)
+			if err != nil {
+				return fmt.Errorf("serializing data to JSON: %w", err)
+			}
+
+			fmt.Fprintln(iostreams.G(ctx).Out, string(b))
+		}
+
+		return nil
+
+	case tableprinter.IsGoTemplate(format):
+		tmpl, err := tableprinter.ParseGoTemplate(format)
+		if err != nil {
+			return err
+		}
+
+		for _, result := range results {
+			if err := tmpl.Execute(iostreams.G(ctx).Out, result); err != nil {
+				return fmt.Errorf("could not execute output template: %w", err)
+			}
+
+			fmt.Fprintln(iostreams.G(ctx).Out)
+		}
+
+		return nil
+	}
+
+	if format == "" {
+		format = "table"
+	}
+
+	cs := iostreams.G(ctx).ColorScheme()
+	table, err := tableprinter.NewTablePrinter(ctx,
+		tableprinter.WithMaxWidth(iostreams.G(ctx).TerminalWidth()),
+		tableprinter.WithOutputFormatFromString(format),
+	)
+	if err != nil {
+		return err
+	}
+
+	table.AddField("ENTRY", cs.Bold)
+	table.AddField("IMAGE", cs.Bold)
+	table.AddField("METRO", cs.Bold)
+	table.AddField("NAME", cs.Bold)
+	if format != "table" {
+		table.AddField("UUID", cs.Bold)
+	}
+	table.AddField("FQDN", cs.Bold)
+	table.AddField("STATE", cs.Bold)
+	table.AddField("ERROR", cs.Bold)
+	table.EndRow()
+
+	for _, result := range results {
+		table.AddField(fmt.Sprint(result.Entry), nil)
+		table.AddField(result.Image, nil)
+		table.AddField(result.Metro, nil)
+		table.AddField(result.Name, nil)
+		if format != "table" {
+			table.AddField(result.UUID, nil)
+		}
+		table.AddField(result.FQDN, nil)
+
+		if slices.Contains([]string{batchStateFailed, batchStateSkipped}, result.State) {
+			table.AddField(result.State, cs.Red)
+		} else {
+			table.AddField(result.State, nil)
+		}
+
+		table.AddField(result.Error, nil)
+		table.EndRow()
+	}
+
+	return table.Render(iostreams.G(ctx).Out)
+}
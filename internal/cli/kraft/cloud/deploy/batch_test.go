@@ -0,0 +1,177 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package deploy
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestBatchSizeUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		data     string
+		expected batchSize
+		err      bool
+	}{
+		{data: `512`, expected: 512},
+		{data: `"512Mi"`, expected: 512},
+		{data: `"1Gi"`, expected: 1024},
+		{data: `"512"`, expected: 512},
+		{data: `"lots"`, err: true},
+		{data: `true`, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			var size batchSize
+			err := json.Unmarshal([]byte(tt.data), &size)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("expected error")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if size != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, size)
+			}
+		})
+	}
+}
+
+func TestBatchEntryValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		entry batchEntry
+		err   bool
+	}{
+		{
+			name:  "image only",
+			entry: batchEntry{Image: "nginx:latest"},
+		},
+		{
+			name: "all fields",
+			entry: batchEntry{
+				Image:  "nginx:latest",
+				Memory: 256,
+				Ports:  []string{"443:8080/http+tls"},
+				Env:    map[string]string{"KEY": "value"},
+			},
+		},
+		{
+			name:  "missing image",
+			entry: batchEntry{Name: "my-app"},
+			err:   true,
+		},
+		{
+			name:  "negative memory",
+			entry: batchEntry{Image: "nginx:latest", Memory: -1},
+			err:   true,
+		},
+		{
+			name:  "invalid port",
+			entry: batchEntry{Image: "nginx:latest", Ports: []string{"443:99999"}},
+			err:   true,
+		},
+		{
+			name:  "empty env key",
+			entry: batchEntry{Image: "nginx:latest", Env: map[string]string{"": "value"}},
+			err:   true,
+		},
+		{
+			name:  "env key with equals sign",
+			entry: batchEntry{Image: "nginx:latest", Env: map[string]string{"A=B": "value"}},
+			err:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.entry.validate()
+			if tt.err && err == nil {
+				t.Fatalf("expected error")
+			} else if !tt.err && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestForEntry(t *testing.T) {
+	opts := &DeployOptions{
+		Memory: 128,
+		Metro:  "fra0",
+		Metros: []string{"fra0"},
+		Name:   "flag-name",
+		Ports:  []string{"443:8080"},
+		Env:    []string{"FLAG=1"},
+	}
+
+	tests := []struct {
+		name   string
+		entry  batchEntry
+		memory int
+		metro  string
+		ports  []string
+		env    []string
+	}{
+		{
+			name:   "defaults from flags",
+			entry:  batchEntry{Image: "nginx:latest"},
+			memory: 128,
+			metro:  "fra0",
+			ports:  []string{"443:8080"},
+			env:    []string{"FLAG=1"},
+		},
+		{
+			name: "overrides",
+			entry: batchEntry{
+				Image:  "nginx:latest",
+				Name:   "my-app",
+				Metro:  "sin0",
+				Memory: 512,
+				Ports:  []string{"443:80"},
+				Env:    map[string]string{"B": "2", "A": "1"},
+			},
+			memory: 512,
+			metro:  "sin0",
+			ports:  []string{"443:80"},
+			env:    []string{"A=1", "B=2", "FLAG=1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			eopts := opts.forEntry(tt.entry)
+
+			if eopts.DeployAs != (&deployerImageName{}).Name() {
+				t.Errorf("expected to deploy as %q, got %q", (&deployerImageName{}).Name(), eopts.DeployAs)
+			}
+			if eopts.Name != tt.entry.Name {
+				t.Errorf("expected name %q, got %q", tt.entry.Name, eopts.Name)
+			}
+			if eopts.Memory != tt.memory {
+				t.Errorf("expected memory %d, got %d", tt.memory, eopts.Memory)
+			}
+			if eopts.Metro != tt.metro || !slices.Equal(eopts.Metros, []string{tt.metro}) {
+				t.Errorf("expected metro %q, got %q and %v", tt.metro, eopts.Metro, eopts.Metros)
+			}
+			if !slices.Equal(eopts.Ports, tt.ports) {
+				t.Errorf("expected ports %v, got %v", tt.ports, eopts.Ports)
+			}
+			if !slices.Equal(eopts.Env, tt.env) {
+				t.Errorf("expected env %v, got %v", tt.env, eopts.Env)
+			}
+		})
+	}
+
+	if !slices.Equal(opts.Env, []string{"FLAG=1"}) {
+		t.Errorf("expected the env of the original options to be unchanged, got %v", opts.Env)
+	}
+}
@@ -10,16 +10,21 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"syscall"
 	"time"
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/spf13/cobra"
-	"sdk.kraft.cloud/instances"
 
 	"kraftkit.sh/cmdfactory"
 	"kraftkit.sh/config"
+	"kraftkit.sh/internal/cli/kraft/build"
+	"kraftkit.sh/internal/cli/kraft/cloud/instance/create"
 	"kraftkit.sh/internal/cli/kraft/cloud/utils"
+	kraftutils "kraftkit.sh/internal/cli/kraft/utils"
+	"kraftkit.sh/iostreams"
 	"kraftkit.sh/log"
 	"kraftkit.sh/packmanager"
 	"kraftkit.sh/tui/processtree"
@@ -27,45 +32,90 @@ import (
 	"kraftkit.sh/unikraft/app"
 
 	kraftcloud "sdk.kraft.cloud"
+	kcinstances "sdk.kraft.cloud/instances"
+	kcservices "sdk.kraft.cloud/services"
 )
 
 type DeployOptions struct {
+	Annotations            []string                  `local:"true" long:"annotation" split:"false" usage:"Attach free-form metadata to the instance in the form KEY=VALUE, e.g. a commit SHA, which is visible in its environment (can be used multiple times)"`
 	Auth                   *config.AuthConfig        `noattribute:"true"`
 	Client                 kraftcloud.KraftCloud     `noattribute:"true"`
+	ContinueOnError        bool                      `local:"true" long:"continue-on-error" usage:"With --from-json, deploy the remaining entries when one of them is invalid or fails to deploy"`
 	DeployAs               string                    `local:"true" long:"as" short:"D" usage:"Set the deployment type"`
+	Detach                 bool                      `local:"true" long:"detach" usage:"Return once the instance is created instead of tailing its console until it is ready (default when not attached to a terminal)"`
 	DotConfig              string                    `long:"config" short:"c" usage:"Override the path to the KConfig .config file"`
 	Env                    []string                  `local:"true" long:"env" short:"e" usage:"Environmental variables"`
+	EnvExpand              bool                      `local:"true" long:"env-expand" usage:"Expand ${VAR} and ${VAR:-default} in the values of --env from the caller's environment"`
+	EnvFile                string                    `local:"true" long:"env-file" usage:"Read environmental variables from a dotenv file"`
+	Entrypoint             string                    `local:"true" long:"entrypoint" usage:"Override the command run by the image, followed by the arguments given after -- (only when deploying an image)"`
 	Features               []string                  `local:"true" long:"feature" short:"f" usage:"Specify the special features to enable"`
-	ForcePull              bool                      `long:"force-pull" usage:"Force pulling packages before building"`
-	FQDN                   string                    `local:"true" long:"fqdn" short:"d" usage:"Set the fully qualified domain name for the service"`
-	Jobs                   int                       `long:"jobs" short:"j" usage:"Allow N jobs at once"`
+	ForcePull              bool                      `long:"force-pull" usage:"Force pulling packages before building (same as --pull-policy=always)"`
+	Force                  bool                      `local:"true" long:"force" usage:"Create a new instance even if an identical deployment is already running"`
+	FromJSON               string                    `local:"true" long:"from-json" usage:"Deploy the images described by the given JSON file, or - for stdin, where the flags provide the defaults of each entry"`
+	FQDN                   []string                  `local:"true" long:"fqdn" short:"d" split:"false" usage:"Set a fully qualified domain name for the service (can be used multiple times)"`
+	HealthCheck            string                    `local:"true" long:"health-check" usage:"Only consider the instance ready once GET requests to its FQDN succeed, in the form PROTOCOL:PATH[:INTERVAL], e.g. http:/healthz:10s (implies waiting for the instance)"`
+	Jobs                   int                       `long:"jobs" short:"j" usage:"Allow N jobs at once (default is one per CPU)"`
 	KernelDbg              bool                      `long:"dbg" usage:"Build the debuggable (symbolic) kernel image instead of the stripped image"`
 	Kraftfile              string                    `local:"true" long:"kraftfile" short:"K" usage:"Set the Kraftfile to use"`
-	Memory                 int                       `local:"true" long:"memory" short:"M" usage:"Specify the amount of memory to allocate (MiB)"`
+	Labels                 []string                  `local:"true" long:"label" split:"false" usage:"Set a label on the instance in the form KEY=VALUE (can be used multiple times)"`
+	LabelFromGit           bool                      `local:"true" long:"label-from-git" usage:"Label the instance with the commit (git.sha), branch (git.branch) and dirtiness (git.dirty) of the Git repository being deployed, if any"`
+	Memory                 int                       `noattribute:"true"`
 	Metro                  string                    `noattribute:"true"`
+	Metros                 []string                  `noattribute:"true"`
 	Name                   string                    `local:"true" long:"name" short:"n" usage:"Name of the deployment"`
-	NoCache                bool                      `long:"no-cache" short:"F" usage:"Force a rebuild even if existing intermediate artifacts already exist"`
+	NoCache                bool                      `long:"no-cache" short:"F" usage:"Force a rebuild even if existing intermediate artifacts already exist, or the project is unchanged since it was last deployed"`
 	NoConfigure            bool                      `long:"no-configure" usage:"Do not run Unikraft's configure step before building"`
-	NoFast                 bool                      `long:"no-fast" usage:"Do not use maximum parallelization when performing the build"`
+	NoCreateGroup          bool                      `local:"true" long:"no-create-group" usage:"Fail instead of creating the service group given by --service-group if it does not exist"`
+	NoFast                 bool                      `long:"no-fast" usage:"Build with a single job, overriding --jobs"`
 	NoFetch                bool                      `long:"no-fetch" usage:"Do not run Unikraft's fetch step before building"`
 	NoStart                bool                      `local:"true" long:"no-start" short:"S" usage:"Do not start the instance after creation"`
 	NoUpdate               bool                      `long:"no-update" usage:"Do not update package index before running the build"`
 	Output                 string                    `local:"true" long:"output" short:"o" usage:"Set output format"`
+	OutputFile             string                    `local:"true" long:"output-file" usage:"Write the result of the deployment as JSON to the specified file"`
 	Ports                  []string                  `local:"true" long:"port" short:"p" usage:"Specify the port mapping between external to internal"`
 	Project                app.Application           `noattribute:"true"`
-	Replicas               int                       `local:"true" long:"replicas" short:"R" usage:"Number of replicas of the instance" default:"0"`
+	PullPolicy             build.PullPolicy          `noattribute:"true"`
+	QuietBuild             bool                      `long:"quiet-build" usage:"Print a single line per step instead of rendering the progress, and show the output from the build only if it fails"`
+	ReplaceOnConflict      bool                      `local:"true" long:"replace-on-conflict" usage:"Remove the instances holding the --subdomain or --fqdn after confirmation, instead of failing"`
+	Replicas               int                       `local:"true" long:"replicas" short:"R" usage:"Number of replicas of the instance (with --scale-to-zero, idle replicas are stopped)" default:"0"`
+	Retries                int                       `local:"true" long:"retries" usage:"Number of times to retry API calls failing with a transient error (env: KRAFTCLOUD_RETRIES)" default:"3"`
 	Rollout                string                    `local:"true" long:"rollout" short:"r" usage:"Name or UUID of the instance to rollout over"`
-	Rootfs                 string                    `local:"true" long:"rootfs" usage:"Specify a path to use as root filesystem"`
-	Runtime                string                    `local:"true" long:"runtime" usage:"Set an alternative project runtime"`
-	SaveBuildLog           string                    `long:"build-log" usage:"Use the specified file to save the output from the build"`
+	RolloutStartPeriod     time.Duration             `local:"true" long:"rollout-start-period" usage:"Time for which a new instance must keep running, and pass the --health-check if given, before the old instance is stopped during a rollout (ms/s/m/h)" default:"10000000000"`
+	RolloutStrategy        RolloutStrategy           `noattribute:"true"`
+	RolloutWait            time.Duration             `local:"true" long:"rollout-wait" usage:"Maximum time to wait for an instance to change state during a rollout (ms/s/m/h)" default:"60000000000"`
+	Rootfs                 string                    `local:"true" long:"rootfs" usage:"Specify a path or an OCI image reference with a tag or digest (e.g. docker.io/library/alpine:3) to use as root filesystem"`
+	Runtime                string                    `local:"true" long:"runtime" usage:"Set an alternative project runtime (list with: kraft pkg ls --apps --remote --plat kraftcloud --arch x86_64)"`
+	SaveBuildLog           string                    `long:"build-log" usage:"Use the specified file to save the output from the build, or - for stdout"`
 	ScaleToZero            bool                      `local:"true" long:"scale-to-zero" short:"0" usage:"Scale the instance to zero after deployment"`
-	ServiceGroupNameOrUUID string                    `long:"service-group" short:"g" usage:"Attach the new deployment to an existing service group"`
+	ScaleToZeroCooldown    time.Duration             `local:"true" long:"scale-to-zero-cooldown" usage:"Idle time after which the instance is scaled to zero (requires --scale-to-zero)"`
+	ServiceGroupNameOrUUID string                    `long:"service-group" short:"g" usage:"Attach the new deployment to a service group, which is created with the given --port(s) if it does not exist"`
+	StartAt                string                    `local:"true" long:"start-at" usage:"Create the instance stopped and start it at the given RFC3339 time or after the given duration, which requires the command to keep running until then"`
+	StartTimeout           time.Duration             `local:"true" long:"start-timeout" usage:"Maximum time to wait for the instance to become ready when not detached (ms/s/m/h)" default:"60000000000"`
 	Strategy               packmanager.MergeStrategy `noattribute:"true"`
 	SubDomain              string                    `local:"true" long:"subdomain" short:"s" usage:"Set the name to use when provisioning a subdomain"`
 	Timeout                time.Duration             `local:"true" long:"timeout" usage:"Set the timeout for remote procedure calls"`
+	Timings                bool                      `local:"true" long:"timings" usage:"Print the time taken by each phase of the deployment (fetch, configure, build, package, push and provision)"`
 	Token                  string                    `noattribute:"true"`
-	Volumes                []string                  `long:"volume" short:"v" usage:"Specify the volume mapping(s) in the form NAME:DEST or NAME:DEST:OPTIONS"`
+	UpdateIfExists         bool                      `local:"true" long:"update-if-exists" usage:"Replace the instance with the name given by --name if it already exists, keeping its name and service group"`
+	VerboseBuild           bool                      `long:"verbose-build" usage:"Stream the output from the build to the terminal as it is produced"`
+	Volumes                []string                  `long:"volume" short:"v" usage:"Specify the volume mapping(s) in the form NAME:DEST or NAME:DEST:OPTIONS (options: ro, create=SIZE)"`
 	Workdir                string                    `local:"true" long:"workdir" short:"w" usage:"Set an alternative working directory (default is cwd)"`
+
+	created        *createdInstances
+	conflicting    []*kcservices.GetResponseItem
+	createVolumes  map[string]int
+	deployName     string
+	healthCheck    *healthCheck
+	instanceConfig string
+	instanceMetros map[string]string
+	norender       bool
+	preflighted    map[string]*DeployOptions
+	pulledRootfs   string
+	replaceFirst   bool
+	replacing      *kcinstances.GetResponseItem
+	startTime      time.Time
+	state          *deployState
+	timings        kraftutils.StepTimings
 }
 
 func NewCmd() *cobra.Command {
@@ -80,10 +130,75 @@ func NewCmd() *cobra.Command {
 			'kraft cloud deploy' combines a number of kraft cloud sub-commands
 			to enable you to build, package, ship and deploy your application
 			with a single command.
+
+			Labels, annotations and the digest of the deployment are stored
+			in the environment of the instance as variables prefixed with
+			KRAFTKIT_LABEL_ and KRAFTKIT_ANNOTATION_, which makes them visible
+			to the application.  Do not use them to pass secrets.
 		`),
 		Example: heredoc.Docf(`
 			# Run an image from KraftCloud's catalog:
 			$ kraft cloud --metro fra0 deploy -p 443:8080 caddy:latest
+
+			# Run an image from the catalog with a different command and arguments:
+			$ kraft cloud --metro fra0 deploy -p 443:8080 --entrypoint /usr/bin/caddy caddy:latest -- file-server --listen :8080
+
+			# Run an image in multiple metros at once:
+			$ kraft cloud --metro fra0,was1,sin0 deploy -p 443:8080 caddy:latest
+
+			# Run an image and attach a new 512MiB volume, creating it if it does not exist:
+			$ kraft cloud --metro fra0 deploy -p 443:8080 -v data:/data:create=512M caddy:latest
+
+			# Run an image, setting an environment variable from the caller's environment with a fallback:
+			$ kraft cloud --metro fra0 deploy -p 443:8080 --env-expand -e 'DB_URL=${DB_URL:-postgres://localhost}' caddy:latest
+
+			# Run an image and label the instance as part of the staging environment:
+			$ kraft cloud --metro fra0 deploy -p 443:8080 --label env=staging caddy:latest
+
+			# Build and run the project in the cwd, labelling the instance with the commit and branch it was built from:
+			$ kraft cloud --metro fra0 deploy -p 443:8080 --label-from-git .
+
+			# Run an image and record the commit it was built from:
+			$ kraft cloud --metro fra0 deploy -p 443:8080 --annotation git.sha=$(git rev-parse HEAD) caddy:latest
+
+			# Build and run the project in the cwd, streaming the build output to stdout:
+			$ kraft cloud --metro fra0 deploy -p 443:8080 --build-log - .
+
+			# Build and run the project in the cwd, reporting the time taken by each phase:
+			$ kraft cloud --metro fra0 deploy -p 443:8080 --timings .
+
+			# Run an image, replacing the instance named 'my-app' if it already exists:
+			$ kraft cloud --metro fra0 deploy -p 443:8080 --name my-app --update-if-exists caddy:latest
+
+			# Run an image at the subdomain 'my-app', removing the instance which currently holds it:
+			$ kraft cloud --metro fra0 deploy -p 443:8080 --subdomain my-app --replace-on-conflict caddy:latest
+
+			# Build and run the project in the cwd, using the filesystem of a container image as rootfs:
+			$ kraft cloud --metro fra0 deploy -p 443:8080 --rootfs docker.io/library/alpine:3 .
+
+			# Build and run the project in the cwd, even if it is already running unchanged:
+			$ kraft cloud --metro fra0 deploy -p 443:8080 --force .
+
+			# Run an image and wait until GET requests to /healthz succeed, checking every 5 seconds:
+			$ kraft cloud --metro fra0 deploy -p 443:8080 --health-check https:/healthz:5s caddy:latest
+
+			# Run an image in the background, i.e. without tailing its console until it is ready:
+			$ kraft cloud --metro fra0 deploy -p 443:8080 --detach caddy:latest
+
+			# Run an image in the service group "my-group", creating it if it does not exist:
+			$ kraft cloud --metro fra0 deploy -p 443:8080 --service-group my-group caddy:latest
+
+			# Build and run the project in a Git repository at the given tag:
+			$ kraft cloud --metro fra0 deploy -p 443:8080 https://github.com/me/app.git#v1.0.0
+
+			# Create an instance now and start it at a given time (the command must keep running until then):
+			$ kraft cloud --metro fra0 deploy -p 443:8080 --start-at 2024-06-01T09:00:00Z caddy:latest
+
+			# Run each of the images described in a JSON file, e.g. [{"image": "caddy:latest", "ports": ["443:8080"], "memory": "256Mi"}]:
+			$ kraft cloud --metro fra0 deploy --from-json deployments.json
+
+			# Run an OCI image archive read from stdin:
+			$ cat image.tar | kraft cloud --metro fra0 deploy -p 443:8080 --name my-app -
 		`),
 	})
 	if err != nil {
@@ -99,28 +214,125 @@ func NewCmd() *cobra.Command {
 		"When a package of the same name exists, use this strategy when applying targets.",
 	)
 
-	cmd.Flags().String(
+	cmd.Flags().Var(
+		cmdfactory.NewEnumFlag[RolloutStrategy](
+			RolloutStrategies(),
+			RolloutStrategyStopFirst,
+		),
+		"rollout-strategy",
+		"When using --rollout, use this strategy to replace the old instance.",
+	)
+
+	cmd.Flags().Var(
+		cmdfactory.NewEnumFlag[build.PullPolicy](
+			build.PullPolicies(),
+			build.PullPolicyMissing,
+		),
+		"pull-policy",
+		"When to pull the packages of the project before building.",
+	)
+
+	cmd.Flags().StringArray(
 		"domain",
+		nil,
+		"Alias for --fqdn|-d (can be used multiple times)",
+	)
+
+	cmd.Flags().StringP(
+		"memory",
+		"M",
 		"",
-		"Alias for --fqdn|-d",
+		"Specify the amount of memory to allocate, "+kraftutils.SizeUsage,
 	)
 
 	return cmd
 }
 
-func (opts *DeployOptions) Pre(cmd *cobra.Command, _ []string) error {
-	err := utils.PopulateMetroToken(cmd, &opts.Metro, &opts.Token)
+func (opts *DeployOptions) Pre(cmd *cobra.Command, args []string) error {
+	if err := checkFlagCompatibility(cmd); err != nil {
+		return err
+	}
+
+	if err := utils.ValidateOutput(opts.Output); err != nil {
+		return err
+	}
+
+	err := utils.PopulateMetrosToken(cmd, &opts.Metros, &opts.Token)
 	if err != nil {
 		return fmt.Errorf("could not populate metro and token: %w", err)
 	}
 
+	opts.Metro = opts.Metros[0]
+
+	// This flag shadows the --timeout flag of the cloud command, so store it
+	// for the clients to pick up in its place.
+	if opts.Timeout > 0 {
+		cmd.SetContext(utils.WithRPCTimeout(cmd.Context(), opts.Timeout))
+	}
+
+	if len(opts.Metros) == 1 {
+		if err := utils.SaveProjectMetro(cmd, opts.Metro); err != nil {
+			log.G(cmd.Context()).
+				WithError(err).
+				Debug("could not record metro of project")
+		}
+	}
+
+	if err := utils.SaveProjectToken(cmd, opts.Token); err != nil {
+		log.G(cmd.Context()).
+			WithError(err).
+			Debug("could not record token of project")
+	}
+
+	if opts.StartAt != "" {
+		opts.startTime, err = parseStartAt(opts.StartAt, time.Now())
+		if err != nil {
+			return err
+		}
+
+		opts.NoStart = true
+
+		log.G(cmd.Context()).
+			Warnf("KraftCloud cannot schedule the start of an instance: keep this command running until %s", opts.startTime.Format(time.RFC3339))
+	}
+
+	if opts.HealthCheck != "" {
+		opts.healthCheck, err = parseHealthCheck(opts.HealthCheck)
+		if err != nil {
+			return err
+		}
+	}
+
 	opts.Strategy = packmanager.MergeStrategy(cmd.Flag("strategy").Value.String())
+	opts.RolloutStrategy = RolloutStrategy(cmd.Flag("rollout-strategy").Value.String())
+
+	if cmd.Flags().Changed("pull-policy") {
+		opts.PullPolicy = build.PullPolicy(cmd.Flag("pull-policy").Value.String())
+	}
+
+	opts.PullPolicy, err = build.ResolvePullPolicy(opts.PullPolicy, opts.ForcePull)
+	if err != nil {
+		return err
+	}
 
-	domain := cmd.Flag("domain").Value.String()
-	if len(domain) > 0 && len(opts.FQDN) > 0 {
-		return fmt.Errorf("cannot use --domain and --fqdn together")
-	} else if len(domain) > 0 && len(opts.FQDN) == 0 {
-		opts.FQDN = domain
+	if err := utils.PopulateSizeMB(cmd, "memory", &opts.Memory); err != nil {
+		return err
+	}
+
+	// The progress of the deployment is not rendered either, such that only
+	// plain log lines are printed.
+	if opts.QuietBuild {
+		opts.norender = true
+	}
+
+	domains, err := cmd.Flags().GetStringArray("domain")
+	if err != nil {
+		return err
+	}
+
+	opts.FQDN, err = parseDomains(append(opts.FQDN, domains...))
+	if err != nil {
+		return err
 	}
 
 	ctx, err := packmanager.WithDefaultUmbrellaManagerInContext(cmd.Context())
@@ -128,8 +340,79 @@ func (opts *DeployOptions) Pre(cmd *cobra.Command, _ []string) error {
 		return err
 	}
 
-	if opts.Rollout != "" && opts.ServiceGroupNameOrUUID == "" {
-		return errors.New("cannot use --rollout without a --service-group")
+	if err := utils.PopulateRetries(cmd, &opts.Retries); err != nil {
+		return err
+	}
+
+	if err := create.ValidateScaleToZeroCooldown(opts.ScaleToZero, opts.ScaleToZeroCooldown); err != nil {
+		return err
+	}
+
+	for _, port := range opts.Ports {
+		if _, err := utils.ParsePort(port); err != nil {
+			return err
+		}
+	}
+
+	if err := opts.parseVolumes(); err != nil {
+		return err
+	}
+
+	if opts.EnvExpand {
+		opts.Env, err = utils.ExpandEnv(opts.Env)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(opts.EnvFile) > 0 {
+		if err := opts.mergeEnvFile(); err != nil {
+			return err
+		}
+	}
+
+	labels, err := utils.ParseLabels(opts.Labels)
+	if err != nil {
+		return err
+	}
+
+	opts.Env = append(opts.Env, utils.LabelsToEnv(labels)...)
+
+	annotations, err := utils.ParseAnnotations(opts.Annotations)
+	if err != nil {
+		return err
+	}
+
+	opts.Env = append(opts.Env, utils.AnnotationsToEnv(annotations)...)
+
+	if err := opts.checkOptions(); err != nil {
+		return err
+	}
+
+	if opts.Rollout != "" && opts.RolloutWait < time.Millisecond {
+		return errors.New("rollout wait must be at least 1ms")
+	}
+
+	if opts.RolloutStartPeriod < 0 {
+		return errors.New("rollout start period cannot be negative")
+	}
+
+	if opts.Rollout != "" && opts.NoStart && opts.RolloutStrategy != RolloutStrategyStopFirst {
+		return fmt.Errorf("cannot use --no-start with --rollout-strategy=%s", opts.RolloutStrategy)
+	}
+
+	// Mirror `docker run`: stay in the foreground when interactive unless the
+	// output is meant to be consumed by a program or nothing will start.  A
+	// health check is only performed in the foreground.
+	neverStarts := opts.NoStart && opts.startTime.IsZero()
+	if !cmd.Flags().Changed("detach") {
+		opts.Detach = (!iostreams.G(ctx).IsStdoutTTY() || opts.Output != "" || neverStarts) && opts.healthCheck == nil
+	} else if !opts.Detach && neverStarts {
+		return errors.New("cannot use --detach=false with --no-start")
+	}
+
+	if !opts.Detach && opts.StartTimeout < time.Millisecond {
+		return errors.New("start timeout must be at least 1ms")
 	}
 
 	cmd.SetContext(ctx)
@@ -138,6 +421,34 @@ func (opts *DeployOptions) Pre(cmd *cobra.Command, _ []string) error {
 }
 
 func (opts *DeployOptions) Run(ctx context.Context, args []string) error {
+	// Cancel the deployment on interrupt and remove the instances and service
+	// groups which were already created by it, such that none are left
+	// orphaned.  The interrupt may equally be caught by a process tree, which
+	// is then quit, or the deployment may be cancelled by its caller.  The
+	// same applies to a deployment which fails before it has completed.
+	sigctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	opts.created = &createdInstances{}
+
+	err := opts.run(sigctx, args)
+
+	if opts.pulledRootfs != "" {
+		os.Remove(opts.pulledRootfs)
+	}
+	interrupted := sigctx.Err() != nil || errors.Is(err, processtree.ErrForceQuit)
+	if interrupted || err != nil {
+		stop()
+
+		if opts.cleanup(context.WithoutCancel(ctx)) && interrupted {
+			return errors.New("deployment interrupted")
+		}
+	}
+
+	return utils.ClassifyError(err)
+}
+
+func (opts *DeployOptions) run(ctx context.Context, args []string) error {
 	var err error
 
 	opts.Auth, err = config.GetKraftCloudAuthConfig(ctx, opts.Token)
@@ -147,22 +458,146 @@ func (opts *DeployOptions) Run(ctx context.Context, args []string) error {
 
 	opts.Client = kraftcloud.NewClient(
 		kraftcloud.WithToken(config.GetKraftCloudTokenAuthConfig(*opts.Auth)),
+		kraftcloud.WithHTTPClient(utils.NewRetryHTTPClient(ctx, opts.Retries)),
 	)
 
-	// TODO: Preflight check: check if `--subdomain` is already taken
+	if opts.FromJSON != "" {
+		return opts.runBatch(ctx, args)
+	}
 
-	// Preflight check: check if `--name` is already taken:
-	if len(opts.Name) > 0 {
-		if _, err := opts.Client.Instances().GetByNames(ctx, opts.Name); err == nil {
-			return fmt.Errorf("service name '%s' is already taken", opts.Name)
+	started := time.Now()
+	if opts.Timings {
+		opts.timings = kraftutils.StepTimings{}
+	}
+
+	// When deploying to multiple metros, the deployment may have succeeded in
+	// some of them, in which case those instances are still reported unless
+	// the deployment was quit.
+	insts, sgs, deployErr := opts.deploy(ctx, args)
+	if deployErr != nil && (len(insts) == 0 || errors.Is(deployErr, processtree.ErrForceQuit)) {
+		return deployErr
+	}
+
+	if ctx.Err() == nil {
+		opts.created.settle()
+	}
+
+	if opts.Rollout != "" {
+		if err := opts.rollout(ctx, insts); err != nil {
+			return err
+		}
+	}
+
+	if len(opts.OutputFile) > 0 {
+		if err := writeOutputFile(opts.OutputFile, insts, sgs, opts.phaseTimings()); err != nil {
+			return fmt.Errorf("could not write output file: %w", err)
+		}
+	}
+
+	if len(opts.Metros) > 1 {
+		metros := make([]string, len(insts))
+		for i, inst := range insts {
+			metros[i] = opts.metroOf(inst)
+		}
+
+		if err := utils.PrintMetroInstances(ctx, opts.Output, metros, insts...); err != nil {
+			return err
+		}
+	} else if len(insts) == 1 && opts.Output == "" {
+		utils.PrettyPrintInstance(ctx, &insts[0], &sgs[0], !opts.NoStart)
+	} else if err := utils.PrintInstances(ctx, opts.Output, insts...); err != nil {
+		return err
+	}
+
+	if opts.Timings {
+		if err := opts.printTimings(ctx, time.Since(started)); err != nil {
+			return err
+		}
+	}
+
+	if !opts.startTime.IsZero() {
+		if err := opts.startAt(ctx, insts); err != nil {
+			return errors.Join(deployErr, err)
+		}
+	}
+
+	if !opts.Detach {
+		if err := opts.foreground(ctx, insts); err != nil {
+			return errors.Join(deployErr, err)
+		}
+	}
+
+	if deployErr != nil {
+		return fmt.Errorf("could not deploy to all metros: %w", deployErr)
+	}
+
+	return nil
+}
+
+// deploy performs the preflight checks and subsequently deploys the provided
+// arguments with the capable deployer.  When deploying to multiple metros,
+// the instances and service groups of the successful metros are returned
+// alongside the failures of the others.
+func (opts *DeployOptions) deploy(ctx context.Context, args []string) ([]kcinstances.GetResponseItem, []kcservices.GetResponseItem, error) {
+	var err error
+
+	if opts.LabelFromGit && (len(args) == 0 || !isGitURL(args[0])) {
+		if _, err := opts.addGitLabels(ctx, opts.gitLabelsDir(args)); err != nil {
+			return nil, nil, err
 		}
 	}
 
-	if len(args) > 0 {
+	// Hash the configuration of the instance before the preflight checks alter
+	// it, such that an identical deployment can be recognised.
+	opts.instanceConfig = opts.configHash()
+
+	// Preflight check: check if `--runtime` exists before anything is built.
+	if opts.Runtime != "" {
+		if err := opts.validateRuntime(ctx); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// The remaining preflight checks are specific to each of the targeted
+	// metros and are performed before anything is built.
+	if len(opts.Metros) == 1 {
+		if err := opts.preflight(ctx); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		opts.preflighted = make(map[string]*DeployOptions, len(opts.Metros))
+
+		for _, metro := range opts.Metros {
+			mopts := opts.inMetro(metro)
+			if err := mopts.preflight(ctx); err != nil {
+				return nil, nil, fmt.Errorf("metro %s: %w", metro, err)
+			}
+
+			opts.preflighted[metro] = mopts
+		}
+	}
+
+	// Reading the image from stdin is unambiguous, so skip checking whether any
+	// of the other deployers are also capable.
+	if len(args) > 0 && args[0] == stdinArg {
+		stdin := (&deployerImageStdin{}).Name()
+		if opts.DeployAs != "" && opts.DeployAs != stdin {
+			return nil, nil, fmt.Errorf("cannot use --as=%s when reading the image from stdin", opts.DeployAs)
+		}
+
+		opts.DeployAs = stdin
+	} else if len(args) > 0 && isGitURL(args[0]) {
+		git := (&deployerGit{}).Name()
+		if opts.DeployAs != "" && opts.DeployAs != git {
+			return nil, nil, fmt.Errorf("cannot use --as=%s when deploying a Git repository", opts.DeployAs)
+		}
+
+		opts.DeployAs = git
+	} else if len(args) > 0 {
 		if fi, err := os.Stat(args[0]); err == nil && fi.IsDir() {
 			abs, err := filepath.Abs(args[0])
 			if err != nil {
-				return fmt.Errorf("could not calculate absolute path of '%s': %w", args[0], err)
+				return nil, nil, fmt.Errorf("could not calculate absolute path of '%s': %w", args[0], err)
 			}
 
 			opts.Workdir = abs
@@ -173,7 +608,7 @@ func (opts *DeployOptions) Run(ctx context.Context, args []string) error {
 	if opts.Workdir == "" {
 		opts.Workdir, err = os.Getwd()
 		if err != nil {
-			return fmt.Errorf("could not get current working directory")
+			return nil, nil, fmt.Errorf("could not get current working directory")
 		}
 	}
 
@@ -202,93 +637,107 @@ func (opts *DeployOptions) Run(ctx context.Context, args []string) error {
 	}
 
 	if len(candidates) == 0 {
-		return fmt.Errorf("could not determine how to run provided input: %w", errors.Join(errs...))
+		return nil, nil, fmt.Errorf("could not determine how to run provided input: %w", errors.Join(errs...))
 	} else if len(candidates) == 1 {
 		d = candidates[0]
 	} else if !config.G[config.KraftKit](ctx).NoPrompt {
 		candidate, err := selection.Select[deployer]("multiple deployable contexts discovered: how would you like to proceed?", candidates...)
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
 
 		d = *candidate
 
 		log.G(ctx).Infof("use --as=%s to skip this prompt in the future", d.Name())
 	} else {
-		return fmt.Errorf("multiple contexts discovered: %v", candidates)
+		return nil, nil, fmt.Errorf("multiple contexts discovered: %v", candidates)
 	}
 
 	log.G(ctx).WithField("deployer", d.Name()).Debug("using")
 
+	if err := opts.checkEntrypoint(d); err != nil {
+		return nil, nil, err
+	}
+
 	insts, sgs, err := d.Deploy(ctx, opts, args...)
-	if err != nil {
-		return fmt.Errorf("could not prepare deployment: %w", err)
+	if err != nil && len(insts) == 0 {
+		return nil, nil, fmt.Errorf("could not prepare deployment: %w", err)
 	}
 
-	if opts.Rollout != "" {
-		paramodel, err := processtree.NewProcessTree(
-			ctx,
-			[]processtree.ProcessTreeOption{
-				processtree.IsParallel(false),
-				processtree.WithRenderer(
-					log.LoggerTypeFromString(config.G[config.KraftKit](ctx).Log.Type) != log.FANCY,
-				),
-				processtree.WithFailFast(true),
-				processtree.WithHideOnSuccess(false),
-				processtree.WithTimeout(opts.Timeout),
-			},
-			processtree.NewProcessTreeItem(
-				"draining",
-				"",
-				func(ctx context.Context) error {
-					instanceClient := kraftcloud.NewInstancesClient(
-						kraftcloud.WithToken(config.GetKraftCloudTokenAuthConfig(*opts.Auth)),
-						kraftcloud.WithDefaultMetro(opts.Metro),
-					)
-
-					var oldInsts []instances.GetResponseItem
-					if utils.IsUUID(opts.Rollout) {
-						oldInsts, err = instanceClient.GetByUUIDs(ctx, opts.Rollout)
-					} else {
-						oldInsts, err = instanceClient.GetByNames(ctx, opts.Rollout)
-					}
-					if err != nil {
-						return fmt.Errorf("could not retrieve old instance: %w", err)
-					}
-
-					if len(oldInsts) != 1 {
-						return fmt.Errorf("expected 1 instance, got %d", len(oldInsts))
-					}
-
-					if _, err := instanceClient.StopByUUIDs(ctx, int(time.Minute.Milliseconds()), oldInsts[0].UUID); err != nil {
-						return fmt.Errorf("could not stop the old instance: %w", err)
-					}
-
-					log.G(ctx).Info("waiting one minute for the old instance to stop")
-					time.Sleep(time.Minute)
-
-					if _, err := instanceClient.DeleteByUUIDs(ctx, oldInsts[0].UUID); err != nil {
-						return fmt.Errorf("could not remove the old instance: %w", err)
-					}
-
-					return nil
-				},
-			),
-		)
+	return insts, sgs, err
+}
+
+// preflight checks that the deployment can be performed in the metro of the
+// provided options, preparing it where necessary.
+func (opts *DeployOptions) preflight(ctx context.Context) error {
+	// Check if `--memory` and `--replicas` are within the limits of the metro.
+	if opts.Memory > 0 || opts.Replicas > 0 {
+		limits, err := utils.GetMetroLimits(ctx, opts.Auth, opts.Metro)
 		if err != nil {
-			return nil
+			return fmt.Errorf("could not get limits of metro '%s': %w", opts.Metro, err)
 		}
 
-		err = paramodel.Start()
+		if err := limits.CheckLimits(opts.Memory, opts.Replicas); err != nil {
+			return err
+		}
+	}
+
+	// Check if `--subdomain` or any of the `--fqdn`s is already taken.  When
+	// updating, they may legitimately be taken by the instance which is
+	// replaced.
+	if (len(opts.SubDomain) > 0 || len(opts.FQDN) > 0) && !opts.UpdateIfExists {
+		holders, err := opts.domainHolders(ctx)
 		if err != nil {
-			return fmt.Errorf("could not start the process tree: %w", err)
+			return fmt.Errorf("could not check domain availability: %w", err)
+		}
+
+		for _, domain := range opts.domains() {
+			if holder, ok := holders[domain.name]; ok {
+				if err := opts.checkConflicting(ctx, domain.String(), holder); err != nil {
+					return err
+				}
+			}
 		}
 	}
 
-	if len(insts) == 1 && opts.Output == "" {
-		utils.PrettyPrintInstance(ctx, &insts[0], &sgs[0], !opts.NoStart)
-		return nil
+	// Check if `--name` is already taken.  When updating, the existing instance
+	// is replaced, see prepareReplace.  It may carry a generated name if it was
+	// deployed as a replacement by an earlier version of this command.
+	if len(opts.Name) > 0 {
+		name := opts.Name
+
+		existing, err := opts.Client.Instances().WithMetro(opts.Metro).GetByNames(ctx, name)
+		if err != nil && opts.UpdateIfExists {
+			existing, err = opts.findByDeployName(ctx, name)
+			if err != nil {
+				return fmt.Errorf("could not look up instance '%s': %w", name, err)
+			}
+		} else if err != nil {
+			existing = nil
+		}
+
+		if len(existing) > 0 {
+			if !opts.UpdateIfExists || len(existing) != 1 {
+				return fmt.Errorf("service name '%s' is already taken", name)
+			}
+
+			if err := opts.prepareReplace(ctx, existing[0]); err != nil {
+				return err
+			}
+		}
+
+		if opts.UpdateIfExists {
+			opts.deployName = name
+		}
+	}
+
+	// The service group is only created right before the instance, see
+	// createMissingServiceGroup, but it must be possible to create it.
+	if _, err := opts.missingServiceGroup(ctx); err != nil {
+		return err
 	}
 
-	return utils.PrintInstances(ctx, opts.Output, insts...)
+	// Likewise, the volumes are only created right before the instance, see
+	// createMissingVolumes, but existing ones must be usable.
+	return opts.checkExistingVolumes(ctx)
 }
@@ -38,6 +38,8 @@ type deployer interface {
 // is used with the controller.
 func deployers() []deployer {
 	return []deployer{
+		&deployerImageStdin{},
+		&deployerGit{},
 		&deployerImageName{},
 		&deployerKraftfileRuntime{},
 		&deployerKraftfileUnikraft{},
@@ -0,0 +1,249 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	gitplumbing "github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+
+	kcinstances "sdk.kraft.cloud/instances"
+	kcservices "sdk.kraft.cloud/services"
+
+	"kraftkit.sh/config"
+	"kraftkit.sh/log"
+)
+
+type deployerGit struct {
+	url  string
+	ref  string
+	args []string
+}
+
+// isGitURL returns whether the provided argument refers to a remote Git
+// repository, e.g. `https://github.com/me/app.git#v1.0.0` or
+// `git@github.com:me/app.git`.  HTTP(S) URLs must end in `.git` such that they
+// are not confused with other remote resources.
+func isGitURL(arg string) bool {
+	arg, _, _ = strings.Cut(arg, "#")
+
+	switch {
+	case strings.HasPrefix(arg, "git@"),
+		strings.HasPrefix(arg, "git://"),
+		strings.HasPrefix(arg, "ssh://"):
+		return true
+	case strings.HasPrefix(arg, "https://"),
+		strings.HasPrefix(arg, "http://"):
+		return strings.HasSuffix(strings.TrimSuffix(arg, "/"), ".git")
+	}
+
+	return false
+}
+
+func (deployer *deployerGit) Name() string {
+	return "git"
+}
+
+func (deployer *deployerGit) String() string {
+	if len(deployer.args) == 0 {
+		return fmt.Sprintf("clone and run the '%s' repository", deployer.url)
+	}
+
+	return fmt.Sprintf("clone and run the '%s' repository and use '%s' as arg(s)", deployer.url, strings.Join(deployer.args, " "))
+}
+
+func (deployer *deployerGit) Deployable(ctx context.Context, opts *DeployOptions, args ...string) (bool, error) {
+	if len(args) == 0 || !isGitURL(args[0]) {
+		return false, nil
+	}
+
+	deployer.url, deployer.ref, _ = strings.Cut(args[0], "#")
+	deployer.args = args[1:]
+
+	return true, nil
+}
+
+func (deployer *deployerGit) Deploy(ctx context.Context, opts *DeployOptions, _ ...string) ([]kcinstances.GetResponseItem, []kcservices.GetResponseItem, error) {
+	tmpdir, err := os.MkdirTemp("", "kraftkit-deploy-git-*")
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not create temporary directory: %w", err)
+	}
+
+	defer os.RemoveAll(tmpdir)
+
+	// Clone into a directory named after the repository, since its name is used
+	// as the name of the package when no other name is available.
+	workdir := filepath.Join(tmpdir, strings.TrimSuffix(path.Base(strings.TrimSuffix(deployer.url, "/")), ".git"))
+
+	if err := deployer.clone(ctx, workdir); err != nil {
+		return nil, nil, err
+	}
+
+	opts.Workdir = workdir
+	opts.Project = nil
+
+	// The deployed repository can only be labelled once cloned, after the
+	// configuration of the instance was hashed.
+	if opts.LabelFromGit {
+		env, err := opts.addGitLabels(ctx, workdir)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		opts.instanceConfig = extendConfigHash(opts.instanceConfig, env)
+	}
+
+	// The cloned repository is deployed like a project in the working
+	// directory.
+	for _, candidate := range []deployer{
+		&deployerKraftfileRuntime{},
+		&deployerKraftfileUnikraft{},
+	} {
+		capable, err := candidate.Deployable(ctx, opts, deployer.args...)
+		if err != nil {
+			log.G(ctx).
+				WithField("deployer", candidate.Name()).
+				Debugf("cannot run because: %v", err)
+			continue
+		} else if !capable {
+			continue
+		}
+
+		return candidate.Deploy(ctx, opts, deployer.args...)
+	}
+
+	return nil, nil, fmt.Errorf("could not determine how to run the '%s' repository: it does not contain a Kraftfile", deployer.url)
+}
+
+// clone clones the repository into the provided directory, checking out the
+// branch, tag or commit given by the `#ref` fragment of the URL if any.
+func (deployer *deployerGit) clone(ctx context.Context, dir string) error {
+	auth, err := gitAuth(ctx, deployer.url)
+	if err != nil {
+		return err
+	}
+
+	log.G(ctx).
+		WithField("from", deployer.url).
+		WithField("ref", deployer.ref).
+		Info("git clone")
+
+	if deployer.ref == "" {
+		_, err := git.PlainCloneContext(ctx, dir, false, &git.CloneOptions{
+			URL:          deployer.url,
+			Auth:         auth,
+			Depth:        1,
+			SingleBranch: true,
+			Tags:         git.NoTags,
+		})
+		if err != nil {
+			return fmt.Errorf("could not clone '%s': %w", deployer.url, err)
+		}
+
+		return nil
+	}
+
+	// Shallowly clone the reference if it is a branch or a tag.
+	for _, refName := range []gitplumbing.ReferenceName{
+		gitplumbing.NewBranchReferenceName(deployer.ref),
+		gitplumbing.NewTagReferenceName(deployer.ref),
+	} {
+		_, err := git.PlainCloneContext(ctx, dir, false, &git.CloneOptions{
+			URL:           deployer.url,
+			Auth:          auth,
+			Depth:         1,
+			ReferenceName: refName,
+			SingleBranch:  true,
+			Tags:          git.NoTags,
+		})
+		if err == nil {
+			return nil
+		}
+
+		log.G(ctx).
+			WithField("ref", refName).
+			Debugf("could not clone: %v", err)
+
+		if err := os.RemoveAll(dir); err != nil {
+			return err
+		}
+	}
+
+	// Otherwise, the reference is assumed to be a commit, which requires the
+	// full history to be available.
+	repo, err := git.PlainCloneContext(ctx, dir, false, &git.CloneOptions{
+		URL:        deployer.url,
+		Auth:       auth,
+		NoCheckout: true,
+	})
+	if err != nil {
+		return fmt.Errorf("could not clone '%s': %w", deployer.url, err)
+	}
+
+	hash, err := repo.ResolveRevision(gitplumbing.Revision(deployer.ref))
+	if err != nil {
+		return fmt.Errorf("could not find branch, tag or commit '%s' in '%s': %w", deployer.ref, deployer.url, err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+
+	if err := worktree.Checkout(&git.CheckoutOptions{Hash: *hash}); err != nil {
+		return fmt.Errorf("could not check out '%s': %w", deployer.ref, err)
+	}
+
+	return nil
+}
+
+// gitAuth returns the authentication method to clone the provided repository
+// with: the SSH agent for SSH URLs, or the credentials configured for the host
+// of HTTP(S) URLs, if any.
+func gitAuth(ctx context.Context, remote string) (transport.AuthMethod, error) {
+	if strings.HasPrefix(remote, "git@") || strings.HasPrefix(remote, "ssh://") {
+		auth, err := gitssh.NewSSHAgentAuth("git")
+		if err != nil {
+			return nil, fmt.Errorf("could not create SSH agent auth: %w", err)
+		}
+
+		return auth, nil
+	}
+
+	u, err := url.Parse(remote)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse URL: %w", err)
+	}
+
+	auth, ok := config.G[config.KraftKit](ctx).Auth[u.Host]
+	if !ok {
+		return nil, nil
+	}
+
+	switch {
+	case auth.User != "" && auth.Token != "":
+		return &githttp.BasicAuth{
+			Username: auth.User,
+			Password: auth.Token,
+		}, nil
+	case auth.Token != "":
+		return &githttp.TokenAuth{
+			Token: auth.Token,
+		}, nil
+	}
+
+	return nil, nil
+}
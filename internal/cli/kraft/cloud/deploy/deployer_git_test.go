@@ -0,0 +1,36 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package deploy
+
+import "testing"
+
+func TestIsGitURL(t *testing.T) {
+	tests := []struct {
+		arg      string
+		expected bool
+	}{
+		{"https://github.com/me/app.git", true},
+		{"https://github.com/me/app.git/", true},
+		{"https://github.com/me/app.git#v1.0.0", true},
+		{"http://git.example.com/app.git", true},
+		{"git@github.com:me/app.git", true},
+		{"git://github.com/me/app", true},
+		{"ssh://git@github.com/me/app", true},
+		{"https://github.com/me/app", false},
+		{"https://example.com/app.tar.gz", false},
+		{"nginx:latest", false},
+		{"./app", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			if actual := isGitURL(tt.arg); actual != tt.expected {
+				t.Errorf("isGitURL(%q) = %t, expected %t", tt.arg, actual, tt.expected)
+			}
+		})
+	}
+}
@@ -65,14 +65,60 @@ func (deployer *deployerImageName) Deployable(ctx context.Context, opts *DeployO
 	}
 
 	deployer.imageName = args[0]
-	deployer.args = args[1:]
+	deployer.args = opts.instanceArgs(args[1:])
 
 	return true, nil
 }
 
-func (deployer *deployerImageName) Deploy(ctx context.Context, opts *DeployOptions, args ...string) ([]kcinstances.GetResponseItem, []kcservices.GetResponseItem, error) {
+func (deployer *deployerImageName) Deploy(ctx context.Context, opts *DeployOptions, args ...string) (insts []kcinstances.GetResponseItem, sgs []kcservices.GetResponseItem, err error) {
+	_ = opts.timings.Time(timingProvision, func() error {
+		insts, sgs, err = opts.eachMetro(ctx, deployer.deployInMetro)
+		return err
+	})
+
+	return insts, sgs, err
+}
+
+// deployInMetro creates an instance from the image in the metro of the
+// provided options.
+func (deployer *deployerImageName) deployInMetro(ctx context.Context, opts *DeployOptions) ([]kcinstances.GetResponseItem, []kcservices.GetResponseItem, error) {
 	var err error
 
+	// A tag may point at another image since the previous deployment, such
+	// that only deployments of a resolved digest can be reused.
+	var deployment string
+	if pinned := opts.pinnedImage(ctx, deployer.imageName); pinned != "" {
+		deployment = opts.deployDigest(pinned, deployer.args)
+
+		if inst, sg, err := opts.reuseDeployed(ctx, deployment); err != nil {
+			return nil, nil, err
+		} else if inst != nil {
+			return []kcinstances.GetResponseItem{*inst}, []kcservices.GetResponseItem{*sg}, nil
+		}
+	} else {
+		log.G(ctx).
+			WithField("image", deployer.imageName).
+			Debug("not checking for an identical deployment as the digest of the image is unknown")
+
+		deployment = opts.deployDigest(deployer.imageName, deployer.args)
+	}
+
+	if err := opts.removeConflicting(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	if err := opts.removeReplaced(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	if err := opts.createMissingServiceGroup(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	if err := opts.createMissingVolumes(ctx); err != nil {
+		return nil, nil, err
+	}
+
 	var inst *kcinstances.GetResponseItem
 	var sg *kcservices.GetResponseItem
 
@@ -81,7 +127,7 @@ func (deployer *deployerImageName) Deploy(ctx context.Context, opts *DeployOptio
 		[]processtree.ProcessTreeOption{
 			processtree.IsParallel(false),
 			processtree.WithRenderer(
-				log.LoggerTypeFromString(config.G[config.KraftKit](ctx).Log.Type) != log.FANCY,
+				opts.norender || log.LoggerTypeFromString(config.G[config.KraftKit](ctx).Log.Type) != log.FANCY,
 			),
 			processtree.WithFailFast(true),
 			processtree.WithHideOnSuccess(true),
@@ -92,9 +138,11 @@ func (deployer *deployerImageName) Deploy(ctx context.Context, opts *DeployOptio
 			"",
 			func(ctx context.Context) error {
 				inst, sg, err = instancecreate.Create(ctx, &instancecreate.CreateOptions{
-					Env:                    opts.Env,
+					Auth:                   opts.Auth,
+					Client:                 opts.Client,
+					Env:                    opts.withDeployDigest(deployment),
 					Features:               opts.Features,
-					FQDN:                   opts.FQDN,
+					FQDN:                   opts.fqdn(),
 					Image:                  deployer.imageName,
 					Memory:                 opts.Memory,
 					Metro:                  opts.Metro,
@@ -102,6 +150,7 @@ func (deployer *deployerImageName) Deploy(ctx context.Context, opts *DeployOptio
 					Ports:                  opts.Ports,
 					Replicas:               opts.Replicas,
 					ScaleToZero:            opts.ScaleToZero,
+					ScaleToZeroCooldown:    opts.ScaleToZeroCooldown,
 					ServiceGroupNameOrUUID: opts.ServiceGroupNameOrUUID,
 					Start:                  !opts.NoStart,
 					SubDomain:              opts.SubDomain,
@@ -111,6 +160,8 @@ func (deployer *deployerImageName) Deploy(ctx context.Context, opts *DeployOptio
 					return fmt.Errorf("could not create instance: %w", err)
 				}
 
+				opts.created.add(opts.Metro, inst)
+
 				return nil
 			},
 		),
@@ -123,5 +174,7 @@ func (deployer *deployerImageName) Deploy(ctx context.Context, opts *DeployOptio
 		return nil, nil, err
 	}
 
+	opts.replaceExisting(ctx, *inst)
+
 	return []kcinstances.GetResponseItem{*inst}, []kcservices.GetResponseItem{*sg}, nil
 }
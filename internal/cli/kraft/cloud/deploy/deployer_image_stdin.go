@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	kcinstances "sdk.kraft.cloud/instances"
+	kcservices "sdk.kraft.cloud/services"
+
+	"kraftkit.sh/internal/cli/kraft/pkg"
+	"kraftkit.sh/iostreams"
+	"kraftkit.sh/oci"
+	"kraftkit.sh/pack"
+	"kraftkit.sh/packmanager"
+)
+
+// stdinArg is the argument which indicates that the image to deploy should be
+// read from standard input.
+const stdinArg = "-"
+
+type deployerImageStdin struct {
+	args []string
+}
+
+func (deployer *deployerImageStdin) Name() string {
+	return "image-stdin"
+}
+
+func (deployer *deployerImageStdin) String() string {
+	if len(deployer.args) == 0 {
+		return "run the image archive read from stdin"
+	}
+
+	return fmt.Sprintf("run the image archive read from stdin and use '%s' as arg(s)", strings.Join(deployer.args, " "))
+}
+
+func (deployer *deployerImageStdin) Deployable(ctx context.Context, opts *DeployOptions, args ...string) (bool, error) {
+	if len(args) == 0 || args[0] != stdinArg {
+		return false, nil
+	}
+
+	if len(opts.Name) == 0 {
+		return false, fmt.Errorf("a name must be provided with --name when reading an image from stdin")
+	}
+
+	deployer.args = opts.instanceArgs(args[1:])
+
+	return true, nil
+}
+
+func (deployer *deployerImageStdin) Deploy(ctx context.Context, opts *DeployOptions, _ ...string) ([]kcinstances.GetResponseItem, []kcservices.GetResponseItem, error) {
+	tmpdir, err := os.MkdirTemp("", "kraftkit-deploy-stdin-*")
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not create temporary directory: %w", err)
+	}
+
+	defer os.RemoveAll(tmpdir)
+
+	// Buffer the archive to disk since its contents may be read more than once.
+	archivePath := filepath.Join(tmpdir, "image.tar")
+	fi, err := os.Create(archivePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if _, err := io.Copy(fi, iostreams.G(ctx).In); err != nil {
+		fi.Close()
+		return nil, nil, fmt.Errorf("could not read image archive from stdin: %w", err)
+	}
+
+	if err := fi.Close(); err != nil {
+		return nil, nil, err
+	}
+
+	pkgName := imageRef(opts, opts.Name)
+
+	pm, err := packmanager.G(ctx).From(oci.OCIFormat)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	importer, ok := pm.(packmanager.Importer)
+	if !ok {
+		return nil, nil, fmt.Errorf("package manager '%s' cannot import image archives", pm.Format())
+	}
+
+	var p pack.Package
+	if err := opts.timings.Time(pkg.StepPush, func() (err error) {
+		p, err = importer.Import(ctx, archivePath, pkgName)
+		if err != nil {
+			return fmt.Errorf("could not import image archive: %w", err)
+		}
+
+		return p.Push(ctx)
+	}); err != nil {
+		return nil, nil, fmt.Errorf("could not push image: %w", err)
+	}
+
+	return deployImage(ctx, opts, pkgName, indexDigest(p), deployer.args...)
+}
@@ -19,6 +19,7 @@ import (
 	"kraftkit.sh/internal/cli/kraft/cloud/instance/create"
 	"kraftkit.sh/internal/cli/kraft/pkg"
 	"kraftkit.sh/log"
+	"kraftkit.sh/pack"
 	"kraftkit.sh/tui/processtree"
 )
 
@@ -56,13 +57,8 @@ func (deployer *deployerKraftfileRuntime) Deployable(ctx context.Context, opts *
 	if opts.Runtime != "" {
 		opts.Project.Runtime().SetName(opts.Runtime)
 	}
-	if strings.HasPrefix(opts.Project.Runtime().Name(), "unikraft.io") {
-		opts.Project.Runtime().SetName("index." + opts.Project.Runtime().Name())
-	} else if strings.Contains(opts.Project.Runtime().Name(), "/") && !strings.Contains(opts.Project.Runtime().Name(), "unikraft.io") {
-		opts.Project.Runtime().SetName("index.unikraft.io/" + opts.Project.Runtime().Name())
-	} else if !strings.HasPrefix(opts.Project.Runtime().Name(), "index.unikraft.io") {
-		opts.Project.Runtime().SetName("index.unikraft.io/official/" + opts.Project.Runtime().Name())
-	}
+
+	opts.Project.Runtime().SetName(runtimeRef(opts.Project.Runtime().Name()))
 
 	deployer.args = args
 
@@ -70,6 +66,15 @@ func (deployer *deployerKraftfileRuntime) Deployable(ctx context.Context, opts *
 }
 
 func (deployer *deployerKraftfileRuntime) Deploy(ctx context.Context, opts *DeployOptions, args ...string) ([]kcinstances.GetResponseItem, []kcservices.GetResponseItem, error) {
+	state := opts.loadState(ctx)
+	if state.done(phasePushed) && opts.pushedImageExists(ctx, state) {
+		log.G(ctx).
+			WithField("image", state.Image).
+			Info("skipping packaging: the project is unchanged since it was last pushed (use --no-cache to push again)")
+
+		return deployImage(ctx, opts, state.Image, state.Digest, args...)
+	}
+
 	var pkgName string
 
 	if len(opts.Name) > 0 {
@@ -80,21 +85,11 @@ func (deployer *deployerKraftfileRuntime) Deploy(ctx context.Context, opts *Depl
 		pkgName = filepath.Base(opts.Workdir)
 	}
 
-	user := strings.TrimSuffix(strings.TrimPrefix(opts.Auth.User, "robot$"), ".users.kraftcloud")
-	if split := strings.Split(pkgName, "/"); len(split) > 1 {
-		user = split[0]
-		pkgName = strings.Join(split[1:], "/")
-	}
+	pkgName = imageRef(opts, pkgName)
 
-	if strings.HasPrefix(pkgName, "unikraft.io") {
-		pkgName = "index." + pkgName
-	}
-	if !strings.HasPrefix(pkgName, "index.unikraft.io") {
-		pkgName = fmt.Sprintf(
-			"index.unikraft.io/%s/%s:latest",
-			user,
-			pkgName,
-		)
+	rootfs, err := opts.rootfs(ctx)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	packs, err := pkg.Pkg(ctx, &pkg.PkgOptions{
@@ -105,28 +100,58 @@ func (deployer *deployerKraftfileRuntime) Deploy(ctx context.Context, opts *Depl
 		NoPull:       true,
 		Platform:     "kraftcloud",
 		Project:      opts.Project,
+		PullPolicy:   opts.PullPolicy,
 		Push:         true,
-		Rootfs:       opts.Rootfs,
+		Rootfs:       rootfs,
 		Strategy:     opts.Strategy,
+		Timings:      opts.timings,
 		Workdir:      opts.Workdir,
 	})
 	if err != nil {
 		return nil, nil, fmt.Errorf("could not package: %w", err)
 	}
 
-	// TODO(nderjung): This is a quirk that will be removed.  Remove the `index.`
-	// from the name.
-	if pkgName[0:17] == "index.unikraft.io" {
-		pkgName = pkgName[6:]
+	digest := indexDigest(packs[0])
+
+	if state != nil {
+		state.Image = pkgName
+		state.Digest = digest
 	}
-	if pkgName[0:12] == "unikraft.io/" {
-		pkgName = pkgName[12:]
+
+	opts.recordPhase(ctx, phasePushed)
+
+	return deployImage(ctx, opts, pkgName, digest, args...)
+}
+
+// imageRef returns the fully qualified reference of the image with the
+// provided name in the registry of the authenticated KraftCloud user.
+func imageRef(opts *DeployOptions, name string) string {
+	user := strings.TrimSuffix(strings.TrimPrefix(opts.Auth.User, "robot$"), ".users.kraftcloud")
+	if split := strings.Split(name, "/"); len(split) > 1 {
+		user = split[0]
+		name = strings.Join(split[1:], "/")
+	}
+
+	if strings.HasPrefix(name, "unikraft.io") {
+		name = "index." + name
+	}
+	if !strings.HasPrefix(name, "index.unikraft.io") {
+		name = fmt.Sprintf(
+			"index.unikraft.io/%s/%s:latest",
+			user,
+			name,
+		)
 	}
 
+	return name
+}
+
+// indexDigest returns the (shortened) digest of the index of the provided
+// package.
+func indexDigest(p pack.Package) string {
 	// FIXME(nderjung): Gathering the digest like this really dirty.
-	metadata := packs[0].Columns()
 	var digest string
-	for _, m := range metadata {
+	for _, m := range p.Columns() {
 		if m.Name != "index" {
 			continue
 		}
@@ -134,6 +159,58 @@ func (deployer *deployerKraftfileRuntime) Deploy(ctx context.Context, opts *Depl
 		digest = m.Value
 	}
 
+	return digest
+}
+
+// deployImage waits for the image with the provided reference and digest to
+// become available in KraftCloud and subsequently creates an instance from it
+// in each of the targeted metros.
+func deployImage(ctx context.Context, opts *DeployOptions, pkgName, digest string, args ...string) (insts []kcinstances.GetResponseItem, sgs []kcservices.GetResponseItem, err error) {
+	_ = opts.timings.Time(timingProvision, func() error {
+		insts, sgs, err = opts.eachMetro(ctx, func(ctx context.Context, opts *DeployOptions) ([]kcinstances.GetResponseItem, []kcservices.GetResponseItem, error) {
+			return deployImageInMetro(ctx, opts, pkgName, digest, args...)
+		})
+		return err
+	})
+
+	return insts, sgs, err
+}
+
+// deployImageInMetro deploys the image with the provided reference and digest
+// in the metro of the provided options.
+func deployImageInMetro(ctx context.Context, opts *DeployOptions, pkgName, digest string, args ...string) ([]kcinstances.GetResponseItem, []kcservices.GetResponseItem, error) {
+	// TODO(nderjung): This is a quirk that will be removed.  Remove the `index.`
+	// from the name.
+	if pkgName[0:17] == "index.unikraft.io" {
+		pkgName = pkgName[6:]
+	}
+	if pkgName[0:12] == "unikraft.io/" {
+		pkgName = pkgName[12:]
+	}
+
+	deployment := opts.deployDigest(pkgName+"@"+digest, args)
+	if inst, sg, err := opts.reuseDeployed(ctx, deployment); err != nil {
+		return nil, nil, err
+	} else if inst != nil {
+		return []kcinstances.GetResponseItem{*inst}, []kcservices.GetResponseItem{*sg}, nil
+	}
+
+	if err := opts.removeConflicting(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	if err := opts.removeReplaced(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	if err := opts.createMissingServiceGroup(ctx); err != nil {
+		return nil, nil, err
+	}
+
+	if err := opts.createMissingVolumes(ctx); err != nil {
+		return nil, nil, err
+	}
+
 	var inst *kcinstances.GetResponseItem
 	var sg *kcservices.GetResponseItem
 
@@ -145,7 +222,7 @@ func (deployer *deployerKraftfileRuntime) Deploy(ctx context.Context, opts *Depl
 		[]processtree.ProcessTreeOption{
 			processtree.IsParallel(false),
 			processtree.WithRenderer(
-				log.LoggerTypeFromString(config.G[config.KraftKit](ctx).Log.Type) != log.FANCY,
+				opts.norender || log.LoggerTypeFromString(config.G[config.KraftKit](ctx).Log.Type) != log.FANCY,
 			),
 			processtree.WithFailFast(true),
 			processtree.WithHideOnSuccess(true),
@@ -202,8 +279,10 @@ func (deployer *deployerKraftfileRuntime) Deploy(ctx context.Context, opts *Depl
 					defer cancel()
 
 					inst, sg, err = create.Create(ctxTimeout, &create.CreateOptions{
-						Env:                    opts.Env,
-						FQDN:                   opts.FQDN,
+						Auth:                   opts.Auth,
+						Client:                 opts.Client,
+						Env:                    opts.withDeployDigest(deployment),
+						FQDN:                   opts.fqdn(),
 						Image:                  pkgName,
 						Memory:                 opts.Memory,
 						Metro:                  opts.Metro,
@@ -211,6 +290,7 @@ func (deployer *deployerKraftfileRuntime) Deploy(ctx context.Context, opts *Depl
 						Ports:                  opts.Ports,
 						Replicas:               opts.Replicas,
 						ScaleToZero:            opts.ScaleToZero,
+						ScaleToZeroCooldown:    opts.ScaleToZeroCooldown,
 						ServiceGroupNameOrUUID: opts.ServiceGroupNameOrUUID,
 						Start:                  !opts.NoStart,
 						SubDomain:              opts.SubDomain,
@@ -223,6 +303,8 @@ func (deployer *deployerKraftfileRuntime) Deploy(ctx context.Context, opts *Depl
 						return fmt.Errorf("could not create instance: %w", err)
 					}
 
+					opts.created.add(opts.Metro, inst)
+
 					cancel()
 					break attemptDeployment
 				}
@@ -239,5 +321,7 @@ func (deployer *deployerKraftfileRuntime) Deploy(ctx context.Context, opts *Depl
 		return nil, nil, err
 	}
 
+	opts.replaceExisting(ctx, *inst)
+
 	return []kcinstances.GetResponseItem{*inst}, []kcservices.GetResponseItem{*sg}, nil
 }
@@ -9,6 +9,7 @@ import (
 	kcservices "sdk.kraft.cloud/services"
 
 	"kraftkit.sh/internal/cli/kraft/build"
+	"kraftkit.sh/log"
 )
 
 type deployerKraftfileUnikraft struct {
@@ -48,23 +49,42 @@ func (deployer *deployerKraftfileUnikraft) Deployable(ctx context.Context, opts
 }
 
 func (deployer *deployerKraftfileUnikraft) Deploy(ctx context.Context, opts *DeployOptions, args ...string) ([]kcinstances.GetResponseItem, []kcservices.GetResponseItem, error) {
-	if err := build.Build(ctx, &build.BuildOptions{
-		Architecture: "x86_64",
-		DotConfig:    opts.DotConfig,
-		ForcePull:    opts.ForcePull,
-		Jobs:         opts.Jobs,
-		KernelDbg:    opts.KernelDbg,
-		NoCache:      opts.NoCache,
-		NoConfigure:  opts.NoConfigure,
-		NoFast:       opts.NoFast,
-		NoFetch:      opts.NoFetch,
-		NoUpdate:     opts.NoUpdate,
-		Platform:     "kraftcloud",
-		Rootfs:       opts.Rootfs,
-		SaveBuildLog: opts.SaveBuildLog,
-		Workdir:      opts.Workdir,
-	}); err != nil {
-		return nil, nil, fmt.Errorf("could not complete build: %w", err)
+	// Once the image has been pushed, the build is not needed anymore, unless
+	// the image has since been removed and the kernel has to be packaged again.
+	state := opts.loadState(ctx)
+	if (state.done(phaseBuilt) || state.done(phasePushed)) && opts.builtKernelExists() ||
+		state.done(phasePushed) && opts.pushedImageExists(ctx, state) {
+		log.G(ctx).Info("skipping build: the project is unchanged since it was last built (use --no-cache to rebuild)")
+	} else {
+		rootfs, err := opts.rootfs(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if err := build.Build(ctx, &build.BuildOptions{
+			Architecture: "x86_64",
+			DotConfig:    opts.DotConfig,
+			ForcePull:    opts.ForcePull,
+			Jobs:         opts.Jobs,
+			KernelDbg:    opts.KernelDbg,
+			NoCache:      opts.NoCache,
+			NoConfigure:  opts.NoConfigure,
+			NoFast:       opts.NoFast,
+			NoFetch:      opts.NoFetch,
+			NoUpdate:     opts.NoUpdate,
+			Platform:     "kraftcloud",
+			PullPolicy:   opts.PullPolicy,
+			QuietBuild:   opts.QuietBuild,
+			Rootfs:       rootfs,
+			SaveBuildLog: opts.SaveBuildLog,
+			Timings:      opts.timings,
+			VerboseBuild: opts.VerboseBuild,
+			Workdir:      opts.Workdir,
+		}); err != nil {
+			return nil, nil, fmt.Errorf("could not complete build: %w", err)
+		}
+
+		opts.recordPhase(ctx, phaseBuilt)
 	}
 
 	// Re-use the runtime deployer, which also handles packaging.
@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package deploy
+
+import (
+	"fmt"
+)
+
+// instanceArgs returns the arguments of the instance to create from the
+// provided arguments following the image, prefixed by the `--entrypoint`, if
+// any, such that they replace the command which the image runs by default.
+func (opts *DeployOptions) instanceArgs(args []string) []string {
+	if opts.Entrypoint == "" {
+		return args
+	}
+
+	return append([]string{opts.Entrypoint}, args...)
+}
+
+// checkEntrypoint returns an error if the command of the instance is
+// overridden via `--entrypoint` while the provided deployer builds the project
+// from source, whose entrypoint is set by its Kraftfile and baked into the
+// resulting image.  Arguments following `--` are passed to the instance by
+// every deployer.
+func (opts *DeployOptions) checkEntrypoint(d deployer) error {
+	if opts.Entrypoint == "" {
+		return nil
+	}
+
+	switch d.(type) {
+	case *deployerImageName, *deployerImageStdin:
+		return nil
+	}
+
+	return fmt.Errorf("cannot use --entrypoint with --as=%s: it only applies to deploying an existing image, as the entrypoint of a project built from source is set by its Kraftfile", d.Name())
+}
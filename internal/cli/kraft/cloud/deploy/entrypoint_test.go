@@ -0,0 +1,62 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package deploy
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestInstanceArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		entrypoint string
+		args       []string
+		expected   []string
+	}{
+		{name: "no entrypoint"},
+		{name: "args only", args: []string{"-c", "conf"}, expected: []string{"-c", "conf"}},
+		{name: "entrypoint only", entrypoint: "/bin/app", expected: []string{"/bin/app"}},
+		{name: "entrypoint and args", entrypoint: "/bin/app", args: []string{"-c", "conf"}, expected: []string{"/bin/app", "-c", "conf"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &DeployOptions{Entrypoint: tt.entrypoint}
+			if actual := opts.instanceArgs(tt.args); !slices.Equal(actual, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCheckEntrypoint(t *testing.T) {
+	tests := []struct {
+		name       string
+		entrypoint string
+		deployer   deployer
+		err        bool
+	}{
+		{name: "image without entrypoint", deployer: &deployerImageName{}},
+		{name: "kraftfile without entrypoint", deployer: &deployerKraftfileRuntime{}},
+		{name: "image", entrypoint: "/bin/app", deployer: &deployerImageName{}},
+		{name: "image from stdin", entrypoint: "/bin/app", deployer: &deployerImageStdin{}},
+		{name: "kraftfile", entrypoint: "/bin/app", deployer: &deployerKraftfileRuntime{}, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &DeployOptions{Entrypoint: tt.entrypoint}
+
+			err := opts.checkEntrypoint(tt.deployer)
+			if tt.err && err == nil {
+				t.Fatalf("expected error")
+			} else if !tt.err && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
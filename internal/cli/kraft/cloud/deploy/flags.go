@@ -0,0 +1,119 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package deploy
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"kraftkit.sh/log"
+)
+
+// flagRule describes how two flags of the deploy command relate to each other.
+type flagRule struct {
+	flag   string
+	other  string
+	reason string
+}
+
+// flagConflicts are the pairs of flags which cannot be used together.
+var flagConflicts = []flagRule{
+	{"update-if-exists", "rollout", "the instance with the given --name is replaced instead"},
+	{"subdomain", "fqdn", "both set the domain of the service group"},
+	{"subdomain", "domain", "both set the domain of the service group"},
+	{"quiet-build", "verbose-build", "the output of the build is either hidden or streamed"},
+	{"start-at", "detach", "the instance is started by this command, which must keep running until then"},
+	{"start-at", "rollout", "the new instance must be running to rollout over the old one"},
+	{"health-check", "detach", "the health check is performed while waiting for the instance"},
+	{"health-check", "no-start", "the instance must be started to check its health"},
+	{"health-check", "from-json", "batches of deployments are not waited for"},
+	{"from-json", "name", "each entry sets its own name"},
+	{"from-json", "rollout", "each entry is deployed as a new instance"},
+	{"from-json", "start-at", "each entry is deployed as a new instance"},
+	{"from-json", "timings", "the phases of a batch of deployments overlap"},
+}
+
+// flagRequirements are the pairs of flags where the first can only be used
+// together with the second.
+var flagRequirements = []flagRule{
+	{"update-if-exists", "name", "it determines the instance to replace"},
+	{"rollout", "service-group", "the instance to rollout over must be part of it"},
+	{"rollout-strategy", "rollout", "it only applies to rollouts"},
+	{"rollout-wait", "rollout", "it only applies to rollouts"},
+	{"rollout-start-period", "rollout", "it only applies to rollouts"},
+	{"no-create-group", "service-group", "it only applies to service groups given by name"},
+	{"scale-to-zero-cooldown", "scale-to-zero", "it only applies to instances which scale to zero"},
+	{"continue-on-error", "from-json", "it only applies to batches of deployments"},
+}
+
+// flagSet returns whether the flag with the provided name was set, treating
+// boolean flags explicitly set to false as unset.
+func flagSet(cmd *cobra.Command, name string) bool {
+	flag := cmd.Flag(name)
+	if flag == nil || !flag.Changed {
+		return false
+	}
+
+	return flag.Value.Type() != "bool" || flag.Value.String() == "true"
+}
+
+// checkFlagCompatibility fails on combinations of flags which contradict each
+// other, and warns about those which are likely not what the user intended.
+func checkFlagCompatibility(cmd *cobra.Command) error {
+	for _, rule := range flagConflicts {
+		if flagSet(cmd, rule.flag) && flagSet(cmd, rule.other) {
+			return fmt.Errorf("cannot use --%s with --%s, as %s", rule.flag, rule.other, rule.reason)
+		}
+	}
+
+	for _, rule := range flagRequirements {
+		if flagSet(cmd, rule.flag) && !flagSet(cmd, rule.other) {
+			return fmt.Errorf("cannot use --%s without --%s, as %s", rule.flag, rule.other, rule.reason)
+		}
+	}
+
+	// Replicas which scale to zero are provisioned, but each of them is stopped
+	// when idle: --replicas sets how many instances can serve traffic rather
+	// than how many are running at any time.
+	if flagSet(cmd, "replicas") && flagSet(cmd, "scale-to-zero") {
+		log.G(cmd.Context()).Warn("using --replicas with --scale-to-zero: idle replicas are scaled to zero, so fewer replicas than requested may be running at any time")
+	}
+
+	return nil
+}
+
+// checkOptions fails on values of the options which contradict each other.
+// Unlike checkFlagCompatibility, it also covers the values which were not set
+// via flags, e.g. via the environment or an entry of `--from-json`, or which
+// were explicitly set to be empty.
+func (opts *DeployOptions) checkOptions() error {
+	switch {
+	case opts.UpdateIfExists && opts.Name == "":
+		return errors.New("cannot use --update-if-exists without a --name, as it determines the instance to replace")
+
+	case opts.UpdateIfExists && opts.Rollout != "":
+		return errors.New("cannot use --update-if-exists with --rollout, as the instance with the given --name is replaced instead")
+
+	case opts.UpdateIfExists && len(opts.Metros) > 1:
+		return errors.New("cannot use --update-if-exists when deploying to multiple metros")
+
+	case opts.Rollout != "" && opts.ServiceGroupNameOrUUID == "":
+		return errors.New("cannot use --rollout without a --service-group, as the instance to rollout over must be part of it")
+
+	case opts.Rollout != "" && len(opts.Metros) > 1:
+		return errors.New("cannot use --rollout when deploying to multiple metros")
+
+	case opts.NoCreateGroup && opts.ServiceGroupNameOrUUID == "":
+		return errors.New("cannot use --no-create-group without a --service-group, as it only applies to service groups given by name")
+
+	case opts.SubDomain != "" && len(opts.FQDN) > 0:
+		return errors.New("cannot use --subdomain with --fqdn or --domain, as both set the domain of the service group")
+	}
+
+	return nil
+}
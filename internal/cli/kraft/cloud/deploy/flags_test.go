@@ -0,0 +1,80 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package deploy
+
+import "testing"
+
+func TestCheckOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts DeployOptions
+		err  bool
+	}{
+		{name: "no options"},
+		{
+			name: "update if exists",
+			opts: DeployOptions{UpdateIfExists: true, Name: "my-app"},
+		},
+		{
+			name: "update if exists without name",
+			opts: DeployOptions{UpdateIfExists: true},
+			err:  true,
+		},
+		{
+			name: "update if exists with rollout",
+			opts: DeployOptions{UpdateIfExists: true, Name: "my-app", Rollout: "old-app", ServiceGroupNameOrUUID: "my-sg"},
+			err:  true,
+		},
+		{
+			name: "update if exists in multiple metros",
+			opts: DeployOptions{UpdateIfExists: true, Name: "my-app", Metros: []string{"fra0", "sin0"}},
+			err:  true,
+		},
+		{
+			name: "rollout",
+			opts: DeployOptions{Rollout: "old-app", ServiceGroupNameOrUUID: "my-sg", Metros: []string{"fra0"}},
+		},
+		{
+			name: "rollout without service group",
+			opts: DeployOptions{Rollout: "old-app"},
+			err:  true,
+		},
+		{
+			name: "rollout in multiple metros",
+			opts: DeployOptions{Rollout: "old-app", ServiceGroupNameOrUUID: "my-sg", Metros: []string{"fra0", "sin0"}},
+			err:  true,
+		},
+		{
+			name: "no create group",
+			opts: DeployOptions{NoCreateGroup: true, ServiceGroupNameOrUUID: "my-sg"},
+		},
+		{
+			name: "no create group without service group",
+			opts: DeployOptions{NoCreateGroup: true},
+			err:  true,
+		},
+		{
+			name: "subdomain",
+			opts: DeployOptions{SubDomain: "my-app"},
+		},
+		{
+			name: "subdomain with fqdn",
+			opts: DeployOptions{SubDomain: "my-app", FQDN: []string{"app.example.com"}},
+			err:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.checkOptions()
+			if tt.err && err == nil {
+				t.Fatalf("expected error")
+			} else if !tt.err && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package deploy
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	kraftcloud "sdk.kraft.cloud"
+	kcinstances "sdk.kraft.cloud/instances"
+
+	"kraftkit.sh/config"
+	"kraftkit.sh/internal/cli/kraft/cloud/utils"
+	"kraftkit.sh/iostreams"
+	"kraftkit.sh/log"
+)
+
+// foreground tails the console output of each of the newly deployed instances
+// until it reaches the 'running' state or `--start-timeout` elapses.  Reaching
+// that state only means that the instance has booted, not that it is able to
+// serve requests; with `--health-check`, an instance is only ready once it also
+// passes the given health check.
+func (opts *DeployOptions) foreground(ctx context.Context, insts []kcinstances.GetResponseItem) error {
+	for _, inst := range insts {
+		instanceClient := kraftcloud.NewInstancesClient(
+			kraftcloud.WithToken(config.GetKraftCloudTokenAuthConfig(*opts.Auth)),
+			kraftcloud.WithDefaultMetro(opts.metroOf(inst)),
+			kraftcloud.WithHTTPClient(utils.NewRetryHTTPClient(ctx, opts.Retries)),
+		)
+
+		if err := opts.tailUntilReady(ctx, instanceClient, inst); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// tailUntilReady prints the console output of the provided instance as it is
+// produced until the instance reaches the 'running' state and passes the
+// health check given via `--health-check`, if any.
+func (opts *DeployOptions) tailUntilReady(ctx context.Context, client kcinstances.InstancesService, inst kcinstances.GetResponseItem) error {
+	if opts.healthCheck != nil && inst.FQDN == "" {
+		return fmt.Errorf("cannot perform the health check of instance %s as it has no FQDN", inst.Name)
+	}
+
+	ctx, cancel := utils.WithTimeout(ctx, opts.StartTimeout)
+	defer cancel()
+
+	var printed, state string
+	var lastProbe time.Time
+	var probeErr error
+
+	for {
+		resp, err := client.ConsoleByUUID(ctx, inst.UUID, -1, true)
+		if err == nil {
+			if output, err := base64.StdEncoding.DecodeString(resp.Output); err == nil {
+				fmt.Fprint(iostreams.G(ctx).Out, utils.UnseenOutput(printed, string(output)))
+				printed = string(output)
+			}
+		} else if ctx.Err() == nil {
+			log.G(ctx).
+				WithField("instance", inst.Name).
+				Debugf("could not retrieve console output: %v", err)
+		}
+
+		insts, err := client.GetByUUIDs(ctx, inst.UUID)
+		if err == nil && len(insts) == 1 {
+			state = insts[0].State
+		}
+
+		switch state {
+		case "running":
+			if opts.healthCheck == nil {
+				log.G(ctx).
+					WithField("instance", inst.Name).
+					Info("instance is ready")
+				return nil
+			}
+
+			if time.Since(lastProbe) < opts.healthCheck.interval {
+				break
+			}
+
+			lastProbe = time.Now()
+
+			if probeErr = opts.healthCheck.probe(ctx, inst.FQDN); probeErr == nil {
+				log.G(ctx).
+					WithField("instance", inst.Name).
+					Info("instance is ready: health check passed")
+				return nil
+			}
+
+			log.G(ctx).
+				WithField("instance", inst.Name).
+				Debugf("health check failed: %v", probeErr)
+
+		case "stopped":
+			return fmt.Errorf("instance %s stopped before becoming ready", inst.Name)
+		}
+
+		select {
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded && probeErr != nil {
+				return fmt.Errorf("instance %s did not pass its health check within %s: %w", inst.Name, opts.StartTimeout, probeErr)
+			} else if ctx.Err() == context.DeadlineExceeded {
+				return fmt.Errorf("instance %s did not become ready within %s (last state: %s)", inst.Name, opts.StartTimeout, state)
+			}
+			return nil
+		case <-time.After(rolloutPollInterval):
+		}
+	}
+}
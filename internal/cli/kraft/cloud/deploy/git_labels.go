@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/go-git/go-git/v5"
+
+	"kraftkit.sh/internal/cli/kraft/cloud/utils"
+	"kraftkit.sh/log"
+)
+
+// The labels set via `--label-from-git`.
+const (
+	gitLabelSHA    = "git.sha"
+	gitLabelBranch = "git.branch"
+	gitLabelDirty  = "git.dirty"
+)
+
+// gitLabelsDir returns the directory whose Git repository labels the
+// deployment of the provided arguments.  Repositories deployed from a Git URL
+// are instead labelled once cloned, see deployerGit.
+func (opts *DeployOptions) gitLabelsDir(args []string) string {
+	if len(args) > 0 {
+		if fi, err := os.Stat(args[0]); err == nil && fi.IsDir() {
+			return args[0]
+		}
+	}
+	if opts.Workdir != "" {
+		return opts.Workdir
+	}
+
+	return "."
+}
+
+// addGitLabels labels the deployment with the commit, branch and whether the
+// working tree is dirty of the Git repository containing the provided
+// directory.  Nothing is added if it is not in a Git repository or its state
+// cannot be read, and labels which were explicitly set via `--label` are kept.
+// The added environmental variables are returned.
+func (opts *DeployOptions) addGitLabels(ctx context.Context, dir string) ([]string, error) {
+	labels, err := gitLabels(dir)
+	if errors.Is(err, git.ErrRepositoryNotExists) {
+		log.G(ctx).
+			WithField("dir", dir).
+			Debug("not labelling deployment: not in a Git repository")
+		return nil, nil
+	} else if err != nil {
+		log.G(ctx).
+			WithError(err).
+			Warn("not labelling deployment: could not read Git metadata")
+		return nil, nil
+	}
+
+	explicit, err := utils.ParseLabels(opts.Labels)
+	if err != nil {
+		return nil, err
+	}
+
+	for key := range explicit {
+		delete(labels, key)
+	}
+
+	env := utils.LabelsToEnv(labels)
+	opts.Env = append(opts.Env, env...)
+
+	return env, nil
+}
+
+// gitLabels returns the labels which describe the state of the Git repository
+// containing the provided directory.  The branch is omitted when the HEAD is
+// detached.
+func gitLabels(dir string) (map[string]string, error) {
+	repo, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{
+		DetectDotGit: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve HEAD: %w", err)
+	}
+
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := worktree.Status()
+	if err != nil {
+		return nil, fmt.Errorf("could not get status of working tree: %w", err)
+	}
+
+	labels := map[string]string{
+		gitLabelSHA:   head.Hash().String(),
+		gitLabelDirty: fmt.Sprint(!status.IsClean()),
+	}
+
+	if head.Name().IsBranch() {
+		labels[gitLabelBranch] = head.Name().Short()
+	}
+
+	return labels, nil
+}
@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultHealthCheckInterval is the interval between two health checks when
+// none is given via `--health-check`.
+const defaultHealthCheckInterval = 10 * time.Second
+
+// healthCheck is the parsed value of `--health-check`.
+type healthCheck struct {
+	scheme   string
+	path     string
+	interval time.Duration
+}
+
+// parseHealthCheck parses a health check in the form PROTOCOL:PATH[:INTERVAL],
+// e.g. `http:/healthz:10s`, where PROTOCOL is either http or https.
+func parseHealthCheck(spec string) (*healthCheck, error) {
+	split := strings.Split(spec, ":")
+	if len(split) < 2 || len(split) > 3 {
+		return nil, fmt.Errorf("invalid health check '%s': expected PROTOCOL:PATH[:INTERVAL]", spec)
+	}
+
+	hc := &healthCheck{
+		scheme:   split[0],
+		path:     split[1],
+		interval: defaultHealthCheckInterval,
+	}
+
+	if hc.scheme != "http" && hc.scheme != "https" {
+		return nil, fmt.Errorf("invalid health check '%s': unsupported protocol '%s': expected http or https", spec, hc.scheme)
+	}
+
+	if !strings.HasPrefix(hc.path, "/") {
+		return nil, fmt.Errorf("invalid health check '%s': path must start with '/'", spec)
+	}
+
+	if len(split) == 3 {
+		interval, err := time.ParseDuration(split[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid health check '%s': %w", spec, err)
+		} else if interval < time.Second {
+			return nil, fmt.Errorf("invalid health check '%s': interval must be at least 1s", spec)
+		}
+
+		hc.interval = interval
+	}
+
+	return hc, nil
+}
+
+// probe performs the health check against the provided FQDN.  It succeeds if
+// the response has a status code below 400.  Each attempt is bounded by the
+// interval of the health check.
+func (hc *healthCheck) probe(ctx context.Context, fqdn string) error {
+	ctx, cancel := context.WithTimeout(ctx, hc.interval)
+	defer cancel()
+
+	url := hc.scheme + "://" + fqdn + hc.path
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package deploy
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseHealthCheck(t *testing.T) {
+	tests := []struct {
+		spec     string
+		expected *healthCheck
+	}{
+		{"http:/healthz", &healthCheck{scheme: "http", path: "/healthz", interval: defaultHealthCheckInterval}},
+		{"https:/:30s", &healthCheck{scheme: "https", path: "/", interval: 30 * time.Second}},
+		{"http:/healthz:1s", &healthCheck{scheme: "http", path: "/healthz", interval: time.Second}},
+		{"http:/healthz:500ms", nil},
+		{"http:/healthz:soon", nil},
+		{"tcp:/healthz", nil},
+		{"http:healthz", nil},
+		{"http", nil},
+		{"http:/a:1s:2s", nil},
+		{"", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			hc, err := parseHealthCheck(tt.spec)
+			if tt.expected == nil {
+				if err == nil {
+					t.Fatalf("expected error")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if *hc != *tt.expected {
+				t.Errorf("expected %+v, got %+v", *tt.expected, *hc)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package deploy
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	kcinstances "sdk.kraft.cloud/instances"
+
+	"kraftkit.sh/internal/cli/kraft/cloud/utils"
+	"kraftkit.sh/log"
+)
+
+// cleanupTimeout is the maximum time spent removing the resources created by
+// an interrupted or failed deployment.
+const cleanupTimeout = 30 * time.Second
+
+// createdResource is an instance, service group or volume created by the
+// current invocation.
+type createdResource struct {
+	metro string
+	uuid  string
+	name  string
+}
+
+// createdInstances keeps track of the instances, service groups and volumes
+// created by the current invocation such that they can be removed if it is
+// interrupted or fails before the deployment has completed.
+type createdInstances struct {
+	mu      sync.Mutex
+	items   []createdResource
+	groups  []createdResource
+	volumes []createdResource
+	settled bool
+}
+
+// add records the provided instance as created in the provided metro.
+func (created *createdInstances) add(metro string, inst *kcinstances.GetResponseItem) {
+	if created == nil || inst == nil {
+		return
+	}
+
+	created.mu.Lock()
+	defer created.mu.Unlock()
+
+	created.items = append(created.items, createdResource{
+		metro: metro,
+		uuid:  inst.UUID,
+		name:  inst.Name,
+	})
+}
+
+// addServiceGroup records the service group with the provided UUID and name as
+// created in the provided metro.
+func (created *createdInstances) addServiceGroup(metro, uuid, name string) {
+	if created == nil {
+		return
+	}
+
+	created.mu.Lock()
+	defer created.mu.Unlock()
+
+	created.groups = append(created.groups, createdResource{
+		metro: metro,
+		uuid:  uuid,
+		name:  name,
+	})
+}
+
+// addVolume records the volume with the provided UUID and name as created in
+// the provided metro.
+func (created *createdInstances) addVolume(metro, uuid, name string) {
+	if created == nil {
+		return
+	}
+
+	created.mu.Lock()
+	defer created.mu.Unlock()
+
+	created.volumes = append(created.volumes, createdResource{
+		metro: metro,
+		uuid:  uuid,
+		name:  name,
+	})
+}
+
+// settle marks the deployment as completed, after which the created instances,
+// service groups and volumes are kept even if the invocation is interrupted.
+func (created *createdInstances) settle() {
+	if created == nil {
+		return
+	}
+
+	created.mu.Lock()
+	defer created.mu.Unlock()
+
+	created.settled = true
+}
+
+// cleanup removes the instances created by an interrupted or failed
+// deployment, followed by the service groups and volumes it created, which
+// are only detached once the instances are removed.  The removal is
+// best-effort: failures are logged and otherwise ignored.  It returns whether
+// the deployment had not yet completed.
+func (opts *DeployOptions) cleanup(ctx context.Context) bool {
+	opts.created.mu.Lock()
+	defer opts.created.mu.Unlock()
+
+	if opts.created.settled {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, cleanupTimeout)
+	defer cancel()
+
+	for _, inst := range opts.created.items {
+		log.G(ctx).
+			WithField("metro", inst.metro).
+			WithField("uuid", inst.uuid).
+			Warnf("removing instance %s created by incomplete deployment", inst.name)
+
+		if _, err := opts.Client.Instances().WithMetro(inst.metro).DeleteByUUIDs(ctx, inst.uuid); err != nil {
+			log.G(ctx).
+				WithField("uuid", inst.uuid).
+				WithError(err).
+				Error("could not remove instance, please remove it manually")
+		}
+	}
+
+	for _, sg := range opts.created.groups {
+		log.G(ctx).
+			WithField("metro", sg.metro).
+			WithField("uuid", sg.uuid).
+			Warnf("removing service group %s created by incomplete deployment", sg.name)
+
+		// The service group may already have been removed together with its
+		// last instance.
+		services := opts.Client.Services().WithMetro(sg.metro)
+		if _, err := services.GetByUUID(ctx, sg.uuid); utils.IsNotFound(err) {
+			continue
+		}
+
+		if _, err := services.DeleteByUUID(ctx, sg.uuid); err != nil {
+			log.G(ctx).
+				WithField("uuid", sg.uuid).
+				WithError(err).
+				Error("could not remove service group, please remove it manually")
+		}
+	}
+
+	for _, vol := range opts.created.volumes {
+		log.G(ctx).
+			WithField("metro", vol.metro).
+			WithField("uuid", vol.uuid).
+			Warnf("removing volume %s created by incomplete deployment", vol.name)
+
+		if _, err := opts.Client.Volumes().WithMetro(vol.metro).DeleteByUUID(ctx, vol.uuid); err != nil && !utils.IsNotFound(err) {
+			log.G(ctx).
+				WithField("uuid", vol.uuid).
+				WithError(err).
+				Error("could not remove volume, please remove it manually")
+		}
+	}
+
+	return true
+}
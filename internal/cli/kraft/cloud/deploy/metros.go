@@ -0,0 +1,117 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package deploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	kcinstances "sdk.kraft.cloud/instances"
+	kcservices "sdk.kraft.cloud/services"
+
+	"kraftkit.sh/log"
+)
+
+// inMetro returns a copy of the options which targets only the provided metro.
+// The options altered by the preflight checks in that metro, if they were
+// performed, are carried over.
+func (opts *DeployOptions) inMetro(metro string) *DeployOptions {
+	copied := *opts
+	copied.Metro = metro
+	copied.Metros = []string{metro}
+	copied.preflighted = nil
+
+	if prepared, ok := opts.preflighted[metro]; ok {
+		copied.FQDN = prepared.FQDN
+		copied.Name = prepared.Name
+		copied.Ports = prepared.Ports
+		copied.ServiceGroupNameOrUUID = prepared.ServiceGroupNameOrUUID
+		copied.SubDomain = prepared.SubDomain
+		copied.conflicting = prepared.conflicting
+		copied.deployName = prepared.deployName
+		copied.replaceFirst = prepared.replaceFirst
+		copied.replacing = prepared.replacing
+	}
+
+	return &copied
+}
+
+// eachMetro calls fn once for each of the metros targeted by the deployment,
+// in parallel when targeting more than one.  A failure in one metro does not
+// abort the deployment to the others: the instances and service groups of
+// every successful metro are returned alongside the failures of the others.
+// The metro of each returned instance is recorded such that it can later be
+// retrieved via metroOf.
+func (opts *DeployOptions) eachMetro(ctx context.Context, fn func(context.Context, *DeployOptions) ([]kcinstances.GetResponseItem, []kcservices.GetResponseItem, error)) ([]kcinstances.GetResponseItem, []kcservices.GetResponseItem, error) {
+	if len(opts.Metros) <= 1 {
+		return fn(ctx, opts)
+	}
+
+	type result struct {
+		insts []kcinstances.GetResponseItem
+		sgs   []kcservices.GetResponseItem
+		err   error
+	}
+
+	results := make([]result, len(opts.Metros))
+
+	var wg sync.WaitGroup
+	wg.Add(len(opts.Metros))
+
+	for i, metro := range opts.Metros {
+		go func(i int, metro string) {
+			defer wg.Done()
+
+			// Interactive process trees cannot be rendered concurrently.
+			mopts := opts.inMetro(metro)
+			mopts.norender = true
+
+			log.G(ctx).WithField("metro", metro).Info("deploying")
+
+			insts, sgs, err := fn(ctx, mopts)
+			if err != nil {
+				err = fmt.Errorf("metro %s: %w", metro, err)
+			}
+
+			results[i] = result{insts, sgs, err}
+		}(i, metro)
+	}
+
+	wg.Wait()
+
+	var insts []kcinstances.GetResponseItem
+	var sgs []kcservices.GetResponseItem
+	var errs []error
+
+	opts.instanceMetros = map[string]string{}
+
+	for i, res := range results {
+		if res.err != nil {
+			errs = append(errs, res.err)
+			continue
+		}
+
+		for _, inst := range res.insts {
+			opts.instanceMetros[inst.UUID] = opts.Metros[i]
+		}
+
+		insts = append(insts, res.insts...)
+		sgs = append(sgs, res.sgs...)
+	}
+
+	return insts, sgs, errors.Join(errs...)
+}
+
+// metroOf returns the metro in which the provided instance was deployed.
+func (opts *DeployOptions) metroOf(inst kcinstances.GetResponseItem) string {
+	if metro, ok := opts.instanceMetros[inst.UUID]; ok {
+		return metro
+	}
+
+	return opts.Metro
+}
@@ -0,0 +1,98 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package deploy
+
+import (
+	"slices"
+	"testing"
+
+	kcinstances "sdk.kraft.cloud/instances"
+)
+
+func TestInMetro(t *testing.T) {
+	opts := &DeployOptions{
+		Metro:  "fra0",
+		Metros: []string{"fra0", "sin0"},
+		Name:   "my-app",
+		preflighted: map[string]*DeployOptions{
+			"sin0": {
+				Name:       "my-app-sin0",
+				SubDomain:  "my-app",
+				deployName: "my-app",
+			},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		metro      string
+		expectName string
+		deployName string
+	}{
+		{
+			name:       "preflighted metro",
+			metro:      "sin0",
+			expectName: "my-app-sin0",
+			deployName: "my-app",
+		},
+		{
+			name:       "metro without preflight",
+			metro:      "fra0",
+			expectName: "my-app",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			copied := opts.inMetro(tt.metro)
+
+			if copied.Metro != tt.metro {
+				t.Errorf("expected metro %q, got %q", tt.metro, copied.Metro)
+			}
+			if !slices.Equal(copied.Metros, []string{tt.metro}) {
+				t.Errorf("expected metros [%s], got %v", tt.metro, copied.Metros)
+			}
+			if copied.Name != tt.expectName {
+				t.Errorf("expected name %q, got %q", tt.expectName, copied.Name)
+			}
+			if copied.deployName != tt.deployName {
+				t.Errorf("expected deploy name %q, got %q", tt.deployName, copied.deployName)
+			}
+			if copied.preflighted != nil {
+				t.Errorf("expected the preflighted options to not be carried over")
+			}
+		})
+	}
+
+	if opts.Metro != "fra0" || len(opts.Metros) != 2 || opts.Name != "my-app" {
+		t.Errorf("expected the original options to be unchanged")
+	}
+}
+
+func TestMetroOf(t *testing.T) {
+	opts := &DeployOptions{
+		Metro: "fra0",
+		instanceMetros: map[string]string{
+			"77d0316a-fbbe-488d-8618-5bf7a612477a": "sin0",
+		},
+	}
+
+	tests := []struct {
+		uuid     string
+		expected string
+	}{
+		{"77d0316a-fbbe-488d-8618-5bf7a612477a", "sin0"},
+		{"0e6ee2e4-fe22-4f3a-b2e1-2e4b3ef7c3d2", "fra0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.uuid, func(t *testing.T) {
+			if actual := opts.metroOf(kcinstances.GetResponseItem{UUID: tt.uuid}); actual != tt.expected {
+				t.Errorf("expected metro %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,296 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	kcinstances "sdk.kraft.cloud/instances"
+	kcservices "sdk.kraft.cloud/services"
+
+	"kraftkit.sh/config"
+	svccreate "kraftkit.sh/internal/cli/kraft/cloud/service/create"
+	"kraftkit.sh/internal/cli/kraft/cloud/utils"
+	"kraftkit.sh/iostreams"
+	"kraftkit.sh/log"
+	"kraftkit.sh/tui/confirm"
+)
+
+// prepareReplace checks that the provided instance, which carries the name
+// given via `--name`, can be replaced by the new deployment when using
+// `--update-if-exists`, and records it to be removed.  Unless a service group
+// was explicitly provided, the new instance joins the service group of the old
+// instance so that it is reachable at the same FQDN.  Since names are unique,
+// an old instance which holds the requested name is removed right before the
+// new instance is created under it, see removeReplaced, such that repeated
+// deployments keep the same name.  An old instance which was deployed under a
+// generated name is only removed once the new instance has been created, see
+// replaceExisting.
+func (opts *DeployOptions) prepareReplace(ctx context.Context, old kcinstances.GetResponseItem) error {
+	if opts.ServiceGroupNameOrUUID != "" && old.ServiceGroup != nil &&
+		opts.ServiceGroupNameOrUUID != old.ServiceGroup.UUID &&
+		opts.ServiceGroupNameOrUUID != old.ServiceGroup.Name {
+		return fmt.Errorf("instance '%s' belongs to service group '%s' and cannot be updated into service group '%s'",
+			old.Name,
+			old.ServiceGroup.Name,
+			opts.ServiceGroupNameOrUUID,
+		)
+	}
+
+	opts.replacing = &old
+	opts.replaceFirst = old.Name == opts.Name
+
+	// Volumes can only be attached to a single instance, such that the new
+	// instance could not be created while the old one still exists.
+	if !opts.replaceFirst {
+		for _, vol := range opts.Volumes {
+			name, _, _ := strings.Cut(vol, ":")
+			for _, oldVol := range old.Volumes {
+				if name == oldVol.Name || name == oldVol.UUID {
+					return fmt.Errorf("instance '%s' cannot be updated as volume '%s' is attached to it: stop it first or deploy with --rollout", old.Name, name)
+				}
+			}
+		}
+	}
+
+	// The ports and domain are already those of the service group which is
+	// joined, and cannot be redefined for it.
+	if old.ServiceGroup != nil && opts.ServiceGroupNameOrUUID == "" {
+		opts.ServiceGroupNameOrUUID = old.ServiceGroup.UUID
+		opts.Ports = nil
+		opts.FQDN = nil
+		opts.SubDomain = ""
+	}
+
+	if opts.replaceFirst {
+		log.G(ctx).
+			WithField("uuid", old.UUID).
+			Infof("instance %s will be removed right before it is replaced under the same name", old.Name)
+	}
+
+	return nil
+}
+
+// removeReplaced removes the instance recorded by prepareReplace which holds
+// the name of the new deployment, right before the new instance is created
+// under it.  If the service group joined by the new instance is removed
+// together with the old instance, it is recreated with the same name, ports
+// and domain.
+func (opts *DeployOptions) removeReplaced(ctx context.Context) error {
+	if !opts.replaceFirst {
+		return nil
+	}
+
+	old := *opts.replacing
+	opts.replacing = nil
+	opts.replaceFirst = false
+
+	services := opts.Client.Services().WithMetro(opts.Metro)
+
+	var sg *kcservices.GetResponseItem
+	if old.ServiceGroup != nil {
+		var err error
+		sg, err = services.GetByUUID(ctx, old.ServiceGroup.UUID)
+		if err != nil {
+			return fmt.Errorf("could not get service group of instance '%s': %w", old.Name, err)
+		}
+	}
+
+	log.G(ctx).
+		WithField("uuid", old.UUID).
+		Infof("removing instance %s to replace it", old.Name)
+
+	if _, err := opts.Client.Instances().WithMetro(opts.Metro).DeleteByUUIDs(ctx, old.UUID); err != nil {
+		return fmt.Errorf("could not remove instance '%s': %w", old.Name, err)
+	}
+
+	if sg == nil || (opts.ServiceGroupNameOrUUID != sg.UUID && opts.ServiceGroupNameOrUUID != sg.Name) {
+		return nil
+	}
+
+	if _, err := services.GetByUUID(ctx, sg.UUID); err == nil {
+		return nil
+	} else if !utils.IsNotFound(err) {
+		return fmt.Errorf("could not get service group '%s': %w", sg.Name, err)
+	}
+
+	ports := make([]string, 0, len(sg.Services))
+	for _, service := range sg.Services {
+		handlers := make([]string, 0, len(service.Handlers))
+		for _, handler := range service.Handlers {
+			handlers = append(handlers, string(handler))
+		}
+
+		ports = append(ports, fmt.Sprintf("%d:%d/%s", service.Port, service.DestinationPort, strings.Join(handlers, "+")))
+	}
+
+	log.G(ctx).
+		WithField("name", sg.Name).
+		Info("recreating service group")
+
+	created, err := svccreate.Create(ctx, &svccreate.CreateOptions{
+		Auth:   opts.Auth,
+		Client: opts.Client.Services(),
+		FQDN:   sg.FQDN,
+		Metro:  opts.Metro,
+		Name:   sg.Name,
+		Token:  opts.Token,
+	}, ports...)
+	if err != nil {
+		return fmt.Errorf("could not recreate service group '%s': %w", sg.Name, err)
+	}
+
+	opts.ServiceGroupNameOrUUID = created.UUID
+
+	return nil
+}
+
+// replaceExisting removes the instance recorded by prepareReplace now that the
+// provided instance has been created in its place, following the selected
+// rollout strategy.  Failing to do so is only reported, since the new instance
+// has been deployed regardless.
+func (opts *DeployOptions) replaceExisting(ctx context.Context, inst kcinstances.GetResponseItem) {
+	if opts.replacing == nil {
+		return
+	}
+
+	old := *opts.replacing
+	opts.replacing = nil
+
+	log.G(ctx).
+		WithField("uuid", old.UUID).
+		Infof("replacing existing instance %s with %s", old.Name, inst.Name)
+
+	if err := opts.rolloutOver(ctx, opts.Client.Instances().WithMetro(opts.Metro), old, []kcinstances.GetResponseItem{inst}); err != nil {
+		log.G(ctx).
+			WithError(err).
+			Warnf("could not replace instance %s, remove it with: kraft cloud instance rm %s", old.Name, old.UUID)
+	}
+}
+
+// checkConflicting records the provided service group, which holds the
+// described subdomain or FQDN of the new deployment, to be removed when using
+// `--replace-on-conflict` such that the new deployment can take over its
+// domain, see removeConflicting.  Without the flag, an error naming the
+// instances holding the domain is returned instead.
+func (opts *DeployOptions) checkConflicting(ctx context.Context, domain string, sg *kcservices.GetResponseItem) error {
+	// A service group holding several of the domains is only confirmed once.
+	for _, conflicting := range opts.conflicting {
+		if conflicting.UUID == sg.UUID {
+			return nil
+		}
+	}
+
+	client := opts.Client.Instances().WithMetro(opts.Metro)
+
+	insts, err := utils.GetInstances(ctx, client, sg.Instances...)
+	if err != nil {
+		return fmt.Errorf("could not get instances of service group '%s': %w", sg.Name, err)
+	}
+
+	holder := domainHolderName(sg, insts)
+
+	if !opts.ReplaceOnConflict {
+		return fmt.Errorf("%s is already taken by %s (use --replace-on-conflict to remove it)", domain, holder)
+	}
+
+	if !config.G[config.KraftKit](ctx).NoPrompt && iostreams.G(ctx).IsStdinTTY() {
+		proceed, err := confirm.NewConfirmWithDefault(
+			fmt.Sprintf("The %s is taken by %s. Remove it?", domain, holder),
+			false,
+		)
+		if err != nil {
+			return err
+		}
+
+		if !proceed {
+			return fmt.Errorf("%s is already taken by %s", domain, holder)
+		}
+	}
+
+	opts.conflicting = append(opts.conflicting, sg)
+
+	return nil
+}
+
+// removeConflicting removes the instances of the service groups recorded by
+// checkConflicting, right before the new deployment is created in their place.
+// The service groups are removed too if they outlive their instances.
+func (opts *DeployOptions) removeConflicting(ctx context.Context) error {
+	if len(opts.conflicting) == 0 {
+		return nil
+	}
+
+	conflicting := opts.conflicting
+	opts.conflicting = nil
+
+	for _, sg := range conflicting {
+		if err := opts.removeServiceGroup(ctx, sg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// removeServiceGroup removes the provided service group holding a domain of the
+// new deployment together with its instances.
+func (opts *DeployOptions) removeServiceGroup(ctx context.Context, sg *kcservices.GetResponseItem) error {
+	client := opts.Client.Instances().WithMetro(opts.Metro)
+
+	insts, err := utils.GetInstances(ctx, client, sg.Instances...)
+	if err != nil {
+		return fmt.Errorf("could not get instances of service group '%s': %w", sg.Name, err)
+	}
+
+	uuids := make([]string, len(insts))
+	for i, inst := range insts {
+		uuids[i] = inst.UUID
+	}
+
+	holder := domainHolderName(sg, insts)
+
+	log.G(ctx).
+		WithField("service group", sg.Name).
+		Infof("removing %s to take over its domain", holder)
+
+	if len(uuids) > 0 {
+		if _, err := client.DeleteByUUIDs(ctx, uuids...); err != nil {
+			return fmt.Errorf("could not remove %s: %w", holder, err)
+		}
+	}
+
+	// A service group may be removed together with its last instance, but its
+	// domain is otherwise still held by it.
+	services := opts.Client.Services().WithMetro(opts.Metro)
+	if _, err := services.GetByUUID(ctx, sg.UUID); err == nil {
+		if _, err := services.DeleteByUUID(ctx, sg.UUID); err != nil {
+			return fmt.Errorf("could not remove service group '%s': %w", sg.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// domainHolderName describes the provided service group holding a domain by
+// its instances, or by its own name if it has none.
+func domainHolderName(sg *kcservices.GetResponseItem, insts []kcinstances.GetResponseItem) string {
+	names := make([]string, len(insts))
+	for i, inst := range insts {
+		names[i] = "'" + inst.Name + "'"
+	}
+
+	switch len(insts) {
+	case 0:
+		return fmt.Sprintf("service group '%s'", sg.Name)
+	case 1:
+		return "instance " + names[0]
+	default:
+		return "instances " + strings.Join(names, ", ")
+	}
+}
@@ -0,0 +1,46 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package deploy
+
+import (
+	"testing"
+
+	kcinstances "sdk.kraft.cloud/instances"
+	kcservices "sdk.kraft.cloud/services"
+)
+
+func TestDomainHolderName(t *testing.T) {
+	sg := &kcservices.GetResponseItem{Name: "my-sg"}
+
+	tests := []struct {
+		name     string
+		insts    []kcinstances.GetResponseItem
+		expected string
+	}{
+		{
+			name:     "no instances",
+			expected: "service group 'my-sg'",
+		},
+		{
+			name:     "one instance",
+			insts:    []kcinstances.GetResponseItem{{Name: "my-app"}},
+			expected: "instance 'my-app'",
+		},
+		{
+			name:     "multiple instances",
+			insts:    []kcinstances.GetResponseItem{{Name: "my-app"}, {Name: "other-app"}},
+			expected: "instances 'my-app', 'other-app'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := domainHolderName(sg, tt.insts); actual != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,315 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package deploy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+
+	kcinstances "sdk.kraft.cloud/instances"
+	kcservices "sdk.kraft.cloud/services"
+
+	"kraftkit.sh/internal/cli/kraft/cloud/utils"
+	"kraftkit.sh/log"
+)
+
+// deployDigestAnnotation is the annotation in which the digest of the
+// deployment which created an instance is stored.  Like any annotation, it is
+// visible in the environment of the guest, see utils.AnnotationEnvPrefix.
+const deployDigestAnnotation = "deploy_digest"
+
+// deployNameAnnotation is the annotation in which the name given via `--name`
+// is stored when using `--update-if-exists`, such that the deployment is still
+// found if its instance carries a generated name, as created by earlier
+// versions of this command while the instance it replaced held the name.
+const deployNameAnnotation = "deploy_name"
+
+// reusable returns whether an identical deployment which is already running
+// may be used instead of creating a new instance.  This is never the case when
+// using `--force`, or when the new instance is meant to replace or differ from
+// the running one, i.e. with `--rollout` or when it is not started.
+func (opts *DeployOptions) reusable() bool {
+	return !opts.Force && opts.Rollout == "" && !opts.NoStart
+}
+
+// configHash returns a hash of the options which configure the instance.  It
+// is computed before the preflight checks, which may alter the options, such
+// that it is the same for repeated invocations with the same flags.
+func (opts *DeployOptions) configHash() string {
+	h := sha256.New()
+
+	env := slices.Clone(opts.Env)
+	slices.Sort(env)
+
+	for _, opt := range []string{
+		strings.Join(env, "\x00"),
+		strings.Join(opts.Features, ","),
+		strings.Join(opts.FQDN, ","),
+		fmt.Sprint(opts.Memory),
+		opts.Name,
+		strings.Join(opts.Ports, ","),
+		fmt.Sprint(opts.Replicas),
+		fmt.Sprint(opts.ScaleToZero),
+		opts.ScaleToZeroCooldown.String(),
+		opts.ServiceGroupNameOrUUID,
+		opts.SubDomain,
+		strings.Join(opts.Volumes, ","),
+	} {
+		fmt.Fprintf(h, "%s\x00", opt)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// extendConfigHash returns the hash of the configuration of the instance
+// extended by the provided environmental variables, which were only added
+// after the configuration was hashed.
+func extendConfigHash(hash string, env []string) string {
+	if len(env) == 0 {
+		return hash
+	}
+
+	env = slices.Clone(env)
+	slices.Sort(env)
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s", hash, strings.Join(env, "\x00"))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// deployDigest returns the digest of the deployment of the provided image,
+// i.e. of the image itself, the configuration of the instance and the
+// arguments passed to it.  The image must be identified by its digest for
+// the deployment to be reused, see pinnedImage.
+func (opts *DeployOptions) deployDigest(image string, args []string) string {
+	h := sha256.New()
+
+	fmt.Fprintf(h, "%s\x00%s\x00%s", image, opts.instanceConfig, strings.Join(args, "\x00"))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// pinnedImage returns the provided image reference pinned to the digest which
+// it currently resolves to in the metro of the provided options, such that the
+// digest of the deployment changes whenever a new image is pushed under the
+// same tag.  An empty string is returned if the image cannot be resolved, in
+// which case the deployment must not be reused.
+func (opts *DeployOptions) pinnedImage(ctx context.Context, image string) string {
+	if strings.Contains(image, "@sha256:") {
+		return image
+	}
+
+	ref := normalizeImageRef(image)
+
+	images, err := opts.Client.Images().WithMetro(opts.Metro).List(ctx)
+	if err != nil {
+		log.G(ctx).
+			WithError(err).
+			Debugf("could not resolve the digest of image %s", image)
+		return ""
+	}
+
+	for _, img := range images {
+		for _, tag := range img.Tags {
+			if normalizeImageRef(tag) == ref {
+				return img.Digest
+			}
+		}
+	}
+
+	return ""
+}
+
+// normalizeImageRef returns the provided tagged image reference without the
+// registry and with an explicit tag, such that the references with which an
+// image is deployed compare equal to those listed by the metro.
+func normalizeImageRef(ref string) string {
+	ref = strings.TrimPrefix(ref, "index.")
+	ref = strings.TrimPrefix(ref, "unikraft.io/")
+
+	if !strings.Contains(ref, "/") {
+		ref = "official/" + ref
+	}
+
+	if !strings.Contains(ref[strings.LastIndex(ref, "/")+1:], ":") {
+		ref += ":latest"
+	}
+
+	return ref
+}
+
+// withDeployDigest returns the environmental variables of the instance
+// including the annotation which records the provided digest.
+func (opts *DeployOptions) withDeployDigest(digest string) []string {
+	annotations := map[string]string{
+		deployDigestAnnotation: digest,
+	}
+	if opts.deployName != "" {
+		annotations[deployNameAnnotation] = opts.deployName
+	}
+
+	return append(slices.Clone(opts.Env), utils.AnnotationsToEnv(annotations)...)
+}
+
+// reuseDeployed looks for an instance in the metro of the provided options
+// which was deployed with the provided digest and is still running.  If one
+// exists, it is returned together with its service group such that no new
+// instance is created.
+func (opts *DeployOptions) reuseDeployed(ctx context.Context, digest string) (*kcinstances.GetResponseItem, *kcservices.GetResponseItem, error) {
+	if !opts.reusable() {
+		return nil, nil, nil
+	}
+
+	inst, err := opts.findDeployed(ctx, digest)
+	if err != nil {
+		log.G(ctx).
+			WithError(err).
+			Warn("could not check for an identical deployment")
+	}
+
+	if inst == nil {
+		return nil, nil, nil
+	}
+
+	sg := &kcservices.GetResponseItem{}
+	if inst.ServiceGroup != nil {
+		sg, err = opts.Client.Services().WithMetro(opts.Metro).GetByUUID(ctx, inst.ServiceGroup.UUID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not get service group of instance '%s': %w", inst.Name, err)
+		}
+	}
+
+	entry := log.G(ctx).WithField("uuid", inst.UUID)
+	if inst.FQDN != "" {
+		entry = entry.WithField("fqdn", inst.FQDN)
+	}
+
+	entry.Infof("already deployed as %s: the image and configuration are unchanged (use --force to deploy anyway)", inst.Name)
+
+	return inst, sg, nil
+}
+
+// findDeployed returns the running instance in the metro of the provided
+// options which was deployed with the provided digest, or nil if none is.
+// Since the name and service group of the instance are part of the digest,
+// only the instance carrying the name given via `--name` or the instances of
+// the service group given via `--service-group` are looked at, when set.
+func (opts *DeployOptions) findDeployed(ctx context.Context, digest string) (*kcinstances.GetResponseItem, error) {
+	uuids, err := opts.reuseCandidates(ctx)
+	if err != nil || len(uuids) == 0 {
+		return nil, err
+	}
+
+	insts, err := opts.Client.Instances().WithMetro(opts.Metro).GetByUUIDs(ctx, uuids...)
+	if err != nil {
+		return nil, fmt.Errorf("getting details of %d instance(s): %w", len(uuids), err)
+	}
+
+	for _, inst := range insts {
+		if inst.State != "running" && inst.State != "standby" {
+			continue
+		}
+
+		if utils.InstanceAnnotations(inst)[deployDigestAnnotation] == digest {
+			return &inst, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// reuseCandidates returns the UUIDs of the instances in the metro of the
+// provided options which may have been created by an identical deployment.
+// Only when neither `--name` nor `--service-group` is set, which leaves the
+// instance with a generated name, are all instances of the metro returned.
+func (opts *DeployOptions) reuseCandidates(ctx context.Context) ([]string, error) {
+	switch {
+	case opts.Name != "":
+		insts, err := opts.Client.Instances().WithMetro(opts.Metro).GetByNames(ctx, opts.Name)
+		if utils.IsNotFound(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("could not get instance '%s': %w", opts.Name, err)
+		}
+
+		uuids := make([]string, 0, len(insts))
+		for _, inst := range insts {
+			uuids = append(uuids, inst.UUID)
+		}
+
+		return uuids, nil
+
+	case opts.ServiceGroupNameOrUUID != "":
+		services := opts.Client.Services().WithMetro(opts.Metro)
+
+		var sg *kcservices.GetResponseItem
+		var err error
+		if utils.IsUUID(opts.ServiceGroupNameOrUUID) {
+			sg, err = services.GetByUUID(ctx, opts.ServiceGroupNameOrUUID)
+		} else {
+			sg, err = services.GetByName(ctx, opts.ServiceGroupNameOrUUID)
+		}
+		if utils.IsNotFound(err) {
+			return nil, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("could not get service group '%s': %w", opts.ServiceGroupNameOrUUID, err)
+		}
+
+		return sg.Instances, nil
+	}
+
+	instList, err := opts.Client.Instances().WithMetro(opts.Metro).List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not list instances: %w", err)
+	}
+
+	uuids := make([]string, 0, len(instList))
+	for _, instItem := range instList {
+		uuids = append(uuids, instItem.UUID)
+	}
+
+	return uuids, nil
+}
+
+// findByDeployName returns the instances in the metro of the provided options
+// which were deployed with `--update-if-exists` under the provided name but
+// carry a generated name, see deployNameAnnotation.
+func (opts *DeployOptions) findByDeployName(ctx context.Context, name string) ([]kcinstances.GetResponseItem, error) {
+	client := opts.Client.Instances().WithMetro(opts.Metro)
+
+	instList, err := client.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not list instances: %w", err)
+	}
+
+	if len(instList) == 0 {
+		return nil, nil
+	}
+
+	uuids := make([]string, 0, len(instList))
+	for _, instItem := range instList {
+		uuids = append(uuids, instItem.UUID)
+	}
+
+	insts, err := client.GetByUUIDs(ctx, uuids...)
+	if err != nil {
+		return nil, fmt.Errorf("getting details of %d instance(s): %w", len(uuids), err)
+	}
+
+	var found []kcinstances.GetResponseItem
+	for _, inst := range insts {
+		if utils.InstanceAnnotations(inst)[deployNameAnnotation] == name {
+			found = append(found, inst)
+		}
+	}
+
+	return found, nil
+}
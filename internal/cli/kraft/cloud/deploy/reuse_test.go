@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package deploy
+
+import "testing"
+
+func TestNormalizeImageRef(t *testing.T) {
+	tests := []struct {
+		arg      string
+		expected string
+	}{
+		{"nginx", "official/nginx:latest"},
+		{"nginx:1.25", "official/nginx:1.25"},
+		{"me/app", "me/app:latest"},
+		{"me/app:v1", "me/app:v1"},
+		{"unikraft.io/me/app:v1", "me/app:v1"},
+		{"index.unikraft.io/me/app:v1", "me/app:v1"},
+		{"index.unikraft.io/nginx", "official/nginx:latest"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			if actual := normalizeImageRef(tt.arg); actual != tt.expected {
+				t.Errorf("normalizeImageRef(%q) = %q, expected %q", tt.arg, actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExtendConfigHash(t *testing.T) {
+	if actual := extendConfigHash("hash", nil); actual != "hash" {
+		t.Errorf("expected the hash to be unchanged without env, got %q", actual)
+	}
+
+	extended := extendConfigHash("hash", []string{"A=1", "B=2"})
+	if extended == "hash" {
+		t.Errorf("expected the hash to change with env")
+	}
+
+	if reordered := extendConfigHash("hash", []string{"B=2", "A=1"}); reordered != extended {
+		t.Errorf("expected the hash to not depend on the order of env, got %q and %q", extended, reordered)
+	}
+
+	if changed := extendConfigHash("hash", []string{"A=1", "B=3"}); changed == extended {
+		t.Errorf("expected the hash to change with the values of env")
+	}
+}
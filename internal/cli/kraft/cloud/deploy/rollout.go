@@ -0,0 +1,261 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	kraftcloud "sdk.kraft.cloud"
+	kcinstances "sdk.kraft.cloud/instances"
+
+	"kraftkit.sh/config"
+	"kraftkit.sh/internal/cli/kraft/cloud/utils"
+	"kraftkit.sh/log"
+	"kraftkit.sh/tui/processtree"
+)
+
+// RolloutStrategy describes how an existing instance is replaced by a newly
+// deployed instance when using `--rollout`.
+type RolloutStrategy string
+
+const (
+	// The 'stop-first' strategy drains and removes the old instance as soon as
+	// the new instance has been created, regardless of its state.
+	RolloutStrategyStopFirst = RolloutStrategy("stop-first")
+
+	// The 'wait-healthy' strategy only drains and removes the old instance once
+	// the new instance is healthy, see waitHealthy.
+	RolloutStrategyWaitHealthy = RolloutStrategy("wait-healthy")
+
+	// The 'blue-green' strategy keeps both instances running until the new
+	// instance is healthy, see waitHealthy, and then stops the old instance such
+	// that all traffic of the service group goes to the new one.  The old
+	// instance is kept such that it can be started again to roll back.
+	//
+	// TODO: Swap the domain between two service groups instead, such that the
+	// new instance receives no traffic until it is healthy, once the KraftCloud
+	// API can move instances or domains between service groups.
+	RolloutStrategyBlueGreen = RolloutStrategy("blue-green")
+)
+
+var _ fmt.Stringer = (*RolloutStrategy)(nil)
+
+// String implements fmt.Stringer
+func (strategy RolloutStrategy) String() string {
+	return string(strategy)
+}
+
+// RolloutStrategies returns the list of possible rollout strategies.
+func RolloutStrategies() []RolloutStrategy {
+	return []RolloutStrategy{
+		RolloutStrategyStopFirst,
+		RolloutStrategyWaitHealthy,
+		RolloutStrategyBlueGreen,
+	}
+}
+
+// rolloutPollInterval is the interval between successive checks of an
+// instance's state during a rollout.
+const rolloutPollInterval = 500 * time.Millisecond
+
+// waitForInstanceState polls the instance with the provided UUID until it
+// reaches one of the provided states or the timeout elapses.  The last
+// observed state of the instance is always returned.
+func waitForInstanceState(ctx context.Context, client kcinstances.InstancesService, uuid string, timeout time.Duration, states ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var state string
+
+	for {
+		insts, err := client.GetByUUIDs(ctx, uuid)
+		if err != nil && ctx.Err() == nil {
+			return state, fmt.Errorf("could not get instance %s: %w", uuid, err)
+		} else if err == nil && len(insts) == 1 {
+			state = insts[0].State
+			for _, want := range states {
+				if state == want {
+					return state, nil
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return state, fmt.Errorf("timed out after %s waiting for instance %s (last state: %s)", timeout, uuid, state)
+		case <-time.After(rolloutPollInterval):
+		}
+	}
+}
+
+// waitHealthy waits up to `--rollout-wait` for the provided newly deployed
+// instance to be healthy, i.e. to have been running without interruption for
+// `--rollout-start-period` and, with `--health-check`, to pass the health
+// check afterwards.  Since the new instance shares the service group of the
+// old instance, the health check may be answered by either of them, such that
+// the start period is what ensures that the new instance itself stays up.
+func (opts *DeployOptions) waitHealthy(ctx context.Context, client kcinstances.InstancesService, inst kcinstances.GetResponseItem) error {
+	if opts.healthCheck != nil && inst.FQDN == "" {
+		return fmt.Errorf("cannot perform the health check of instance %s as it has no FQDN", inst.Name)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.RolloutWait)
+	defer cancel()
+
+	var runningSince, lastProbe time.Time
+	var state string
+	var probeErr error
+
+	for {
+		insts, err := client.GetByUUIDs(ctx, inst.UUID)
+		if err != nil && ctx.Err() == nil {
+			return fmt.Errorf("could not get instance %s: %w", inst.Name, err)
+		} else if err == nil && len(insts) == 1 {
+			state = insts[0].State
+		}
+
+		switch {
+		case state == "running" && runningSince.IsZero():
+			runningSince = time.Now()
+		case state != "running" && !runningSince.IsZero():
+			return fmt.Errorf("instance %s stopped running during its start period (state: %s)", inst.Name, state)
+		case state == "stopped":
+			return fmt.Errorf("instance %s stopped before it was running", inst.Name)
+		}
+
+		if !runningSince.IsZero() && time.Since(runningSince) >= opts.RolloutStartPeriod {
+			if opts.healthCheck == nil {
+				return nil
+			}
+
+			if time.Since(lastProbe) >= opts.healthCheck.interval {
+				lastProbe = time.Now()
+
+				if probeErr = opts.healthCheck.probe(ctx, inst.FQDN); probeErr == nil {
+					return nil
+				}
+
+				log.G(ctx).
+					WithField("instance", inst.Name).
+					Debugf("health check failed: %v", probeErr)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if probeErr != nil {
+				return fmt.Errorf("instance %s did not pass its health check within %s: %w", inst.Name, opts.RolloutWait, probeErr)
+			}
+			return fmt.Errorf("timed out after %s waiting for instance %s (last state: %s)", opts.RolloutWait, inst.Name, state)
+		case <-time.After(rolloutPollInterval):
+		}
+	}
+}
+
+// rollout replaces the instance provided via `--rollout` with the newly
+// deployed instances according to the selected rollout strategy.
+func (opts *DeployOptions) rollout(ctx context.Context, insts []kcinstances.GetResponseItem) error {
+	instanceClient := kraftcloud.NewInstancesClient(
+		kraftcloud.WithToken(config.GetKraftCloudTokenAuthConfig(*opts.Auth)),
+		kraftcloud.WithDefaultMetro(opts.Metro),
+		kraftcloud.WithHTTPClient(utils.NewRetryHTTPClient(ctx, opts.Retries)),
+	)
+
+	var oldInsts []kcinstances.GetResponseItem
+	var err error
+
+	if utils.IsUUID(opts.Rollout) {
+		oldInsts, err = instanceClient.GetByUUIDs(ctx, opts.Rollout)
+	} else {
+		oldInsts, err = instanceClient.GetByNames(ctx, opts.Rollout)
+	}
+	if err != nil {
+		return fmt.Errorf("could not retrieve old instance: %w", err)
+	}
+
+	if len(oldInsts) != 1 {
+		return fmt.Errorf("expected 1 instance, got %d", len(oldInsts))
+	}
+
+	return opts.rolloutOver(ctx, instanceClient, oldInsts[0], insts)
+}
+
+// rolloutOver replaces the provided old instance with the provided newly
+// deployed instances according to the selected rollout strategy.
+func (opts *DeployOptions) rolloutOver(ctx context.Context, instanceClient kcinstances.InstancesService, oldInst kcinstances.GetResponseItem, insts []kcinstances.GetResponseItem) error {
+	var items []*processtree.ProcessTreeItem
+
+	if opts.RolloutStrategy != RolloutStrategyStopFirst {
+		for _, inst := range insts {
+			inst := inst
+			items = append(items, processtree.NewProcessTreeItem(
+				fmt.Sprintf("waiting for %s", inst.Name),
+				"",
+				func(ctx context.Context) error {
+					if err := opts.waitHealthy(ctx, instanceClient, inst); err != nil {
+						return fmt.Errorf("new instance did not become healthy, keeping the old instance: %w", err)
+					}
+
+					return nil
+				},
+			))
+		}
+	}
+
+	items = append(items, processtree.NewProcessTreeItem(
+		"draining",
+		"",
+		func(ctx context.Context) error {
+			if _, err := instanceClient.StopByUUIDs(ctx, int(opts.RolloutWait.Milliseconds()), oldInst.UUID); err != nil {
+				return fmt.Errorf("could not stop the old instance: %w", err)
+			}
+
+			log.G(ctx).Infof("waiting up to %s for the old instance to stop", opts.RolloutWait)
+
+			if _, err := waitForInstanceState(ctx, instanceClient, oldInst.UUID, opts.RolloutWait, "stopped"); err != nil {
+				log.G(ctx).
+					WithError(err).
+					Warn("old instance did not stop in time")
+			}
+
+			if opts.RolloutStrategy == RolloutStrategyBlueGreen {
+				log.G(ctx).Infof("keeping old instance %s, to roll back run: kraft cloud instance start %s", oldInst.Name, oldInst.Name)
+				return nil
+			}
+
+			if _, err := instanceClient.DeleteByUUIDs(ctx, oldInst.UUID); err != nil {
+				return fmt.Errorf("could not remove the old instance: %w", err)
+			}
+
+			return nil
+		},
+	))
+
+	paramodel, err := processtree.NewProcessTree(
+		ctx,
+		[]processtree.ProcessTreeOption{
+			processtree.IsParallel(false),
+			processtree.WithRenderer(
+				opts.norender || log.LoggerTypeFromString(config.G[config.KraftKit](ctx).Log.Type) != log.FANCY,
+			),
+			processtree.WithFailFast(true),
+			processtree.WithHideOnSuccess(false),
+			processtree.WithTimeout(opts.Timeout),
+		},
+		items...,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := paramodel.Start(); err != nil {
+		return fmt.Errorf("could not start the process tree: %w", err)
+	}
+
+	return nil
+}
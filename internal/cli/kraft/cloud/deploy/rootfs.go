@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/name"
+
+	"kraftkit.sh/internal/cli/kraft/pkg"
+	"kraftkit.sh/oci"
+	"kraftkit.sh/packmanager"
+)
+
+// isImageRootfs returns whether the provided `--rootfs` refers to an OCI
+// image rather than a local path.  To avoid mistaking a missing local path for
+// an image, the reference must not exist locally and must carry an explicit
+// tag or digest.
+func isImageRootfs(rootfs string) bool {
+	if _, err := os.Stat(rootfs); err == nil {
+		return false
+	}
+
+	if !strings.Contains(rootfs, "@") && !strings.Contains(path.Base(rootfs), ":") {
+		return false
+	}
+
+	_, err := name.ParseReference(rootfs)
+	return err == nil
+}
+
+// rootfs returns the path of the root filesystem to build and package the
+// project with.  When `--rootfs` refers to an OCI image, its filesystem is
+// pulled through the package manager into an initramfs once per deployment,
+// which is removed when the deployment completes.
+func (opts *DeployOptions) rootfs(ctx context.Context) (string, error) {
+	if opts.Rootfs == "" || !isImageRootfs(opts.Rootfs) {
+		return opts.Rootfs, nil
+	}
+
+	if opts.pulledRootfs != "" {
+		return opts.pulledRootfs, nil
+	}
+
+	pm, err := packmanager.G(ctx).From(oci.OCIFormat)
+	if err != nil {
+		return "", err
+	}
+
+	puller, ok := pm.(packmanager.RootfsPuller)
+	if !ok {
+		return "", fmt.Errorf("package manager '%s' cannot pull a rootfs from an image", pm.Format())
+	}
+
+	if err := opts.timings.Time(pkg.StepPackage, func() (err error) {
+		opts.pulledRootfs, err = puller.PullRootfs(ctx, opts.Rootfs, "x86_64")
+		return err
+	}); err != nil {
+		return "", fmt.Errorf("could not pull rootfs: %w", err)
+	}
+
+	return opts.pulledRootfs, nil
+}
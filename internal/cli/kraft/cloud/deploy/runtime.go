@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"kraftkit.sh/internal/cli/kraft/cloud/utils"
+	"kraftkit.sh/log"
+	"kraftkit.sh/packmanager"
+	"kraftkit.sh/unikraft"
+)
+
+// officialRuntimes is the namespace of the runtimes which are provided by
+// KraftCloud.
+const officialRuntimes = "index.unikraft.io/official/"
+
+// runtimeRef returns the fully qualified reference of the provided runtime,
+// e.g. `index.unikraft.io/official/nginx:latest` for `nginx:latest`.
+func runtimeRef(name string) string {
+	if strings.HasPrefix(name, "unikraft.io") {
+		return "index." + name
+	} else if strings.Contains(name, "/") && !strings.Contains(name, "unikraft.io") {
+		return "index.unikraft.io/" + name
+	} else if !strings.HasPrefix(name, "index.unikraft.io") {
+		return officialRuntimes + name
+	}
+
+	return name
+}
+
+// runtimeName returns the name of the provided runtime without its namespace
+// and tag, e.g. `nginx` for `index.unikraft.io/official/nginx:latest`.
+func runtimeName(ref string) string {
+	name, _, _ := strings.Cut(path.Base(ref), "@")
+	name, _, _ = strings.Cut(name, ":")
+
+	return name
+}
+
+// validateRuntime checks that the runtime provided via `--runtime` is known to
+// the package manager, suggesting the closest runtimes otherwise.  Only the
+// official runtimes can be enumerated, so runtimes in any other namespace are
+// not checked.  If the runtimes cannot be enumerated, e.g. when offline, the
+// runtime is assumed to be valid.
+func (opts *DeployOptions) validateRuntime(ctx context.Context) error {
+	if !strings.HasPrefix(runtimeRef(opts.Runtime), officialRuntimes) {
+		return nil
+	}
+
+	packs, err := packmanager.G(ctx).Catalog(ctx,
+		packmanager.WithRemote(true),
+		packmanager.WithTypes(unikraft.ComponentTypeApp),
+		packmanager.WithArchitecture("x86_64"),
+		packmanager.WithPlatform("kraftcloud"),
+	)
+	if err != nil {
+		log.G(ctx).
+			WithError(err).
+			Debug("could not validate runtime")
+		return nil
+	}
+
+	var known []string
+	for _, pack := range packs {
+		if name := runtimeName(pack.Name()); !slices.Contains(known, name) {
+			known = append(known, name)
+		}
+	}
+
+	if len(known) == 0 {
+		return nil
+	}
+
+	name := runtimeName(opts.Runtime)
+	if slices.Contains(known, name) {
+		return nil
+	}
+
+	if suggestions := utils.Suggest(name, known); len(suggestions) > 0 {
+		return fmt.Errorf("unknown runtime '%s', did you mean: %s?", opts.Runtime, strings.Join(suggestions, ", "))
+	}
+
+	slices.Sort(known)
+
+	return fmt.Errorf("unknown runtime '%s', available runtimes: %s", opts.Runtime, strings.Join(known, ", "))
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+
+	svccreate "kraftkit.sh/internal/cli/kraft/cloud/service/create"
+	"kraftkit.sh/internal/cli/kraft/cloud/utils"
+	"kraftkit.sh/log"
+)
+
+// missingServiceGroup returns whether the service group given by
+// `--service-group` does not exist yet, in which case it is created by
+// createMissingServiceGroup.  An error is returned if it could not be created,
+// e.g. when `--no-create-group` is set, or if its existence cannot be
+// determined.
+func (opts *DeployOptions) missingServiceGroup(ctx context.Context) (bool, error) {
+	if opts.ServiceGroupNameOrUUID == "" {
+		return false, nil
+	}
+
+	var err error
+	if utils.IsUUID(opts.ServiceGroupNameOrUUID) {
+		_, err = opts.Client.Services().WithMetro(opts.Metro).GetByUUID(ctx, opts.ServiceGroupNameOrUUID)
+	} else {
+		_, err = opts.Client.Services().WithMetro(opts.Metro).GetByName(ctx, opts.ServiceGroupNameOrUUID)
+	}
+	if err == nil {
+		return false, nil
+	} else if !utils.IsNotFound(err) {
+		return false, fmt.Errorf("could not get service group '%s': %w", opts.ServiceGroupNameOrUUID, err)
+	}
+
+	switch {
+	case opts.Rollout != "":
+		return false, fmt.Errorf("cannot use --rollout with service group '%s' as it does not exist", opts.ServiceGroupNameOrUUID)
+	case opts.NoCreateGroup:
+		return false, fmt.Errorf("service group '%s' does not exist and --no-create-group is set", opts.ServiceGroupNameOrUUID)
+	case utils.IsUUID(opts.ServiceGroupNameOrUUID):
+		return false, fmt.Errorf("service group '%s' does not exist and cannot be created by UUID", opts.ServiceGroupNameOrUUID)
+	case len(opts.Ports) == 0:
+		return false, fmt.Errorf("service group '%s' does not exist and cannot be created without a --port", opts.ServiceGroupNameOrUUID)
+	}
+
+	return true, nil
+}
+
+// createMissingServiceGroup creates the service group given by
+// `--service-group` if it does not exist yet, right before the instance which
+// joins it is created, such that no service group is left behind by a
+// deployment which fails earlier.  The new service group exposes the ports
+// and domain of the deployment, which are subsequently no longer passed on to
+// the instance, and is removed again if the deployment does not complete, see
+// cleanup.
+func (opts *DeployOptions) createMissingServiceGroup(ctx context.Context) error {
+	missing, err := opts.missingServiceGroup(ctx)
+	if err != nil || !missing {
+		return err
+	}
+
+	log.G(ctx).
+		WithField("name", opts.ServiceGroupNameOrUUID).
+		Info("creating service group")
+
+	sg, err := svccreate.Create(ctx, &svccreate.CreateOptions{
+		Auth:      opts.Auth,
+		Client:    opts.Client.Services(),
+		FQDN:      opts.fqdn(),
+		Metro:     opts.Metro,
+		Name:      opts.ServiceGroupNameOrUUID,
+		SubDomain: opts.SubDomain,
+		Token:     opts.Token,
+	}, opts.Ports...)
+	if err != nil {
+		return fmt.Errorf("could not create service group '%s': %w", opts.ServiceGroupNameOrUUID, err)
+	}
+
+	opts.created.addServiceGroup(opts.Metro, sg.UUID, opts.ServiceGroupNameOrUUID)
+
+	opts.Ports = nil
+	opts.FQDN = nil
+	opts.SubDomain = ""
+
+	return nil
+}
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	kraftcloud "sdk.kraft.cloud"
+	kcinstances "sdk.kraft.cloud/instances"
+
+	"kraftkit.sh/config"
+	"kraftkit.sh/internal/cli/kraft/cloud/utils"
+	"kraftkit.sh/log"
+)
+
+// parseStartAt parses the value of `--start-at`, which is either an RFC3339
+// timestamp, e.g. `2024-01-02T15:04:05Z`, or a duration relative to the
+// provided time, e.g. `10m` for ten minutes after it.
+func parseStartAt(s string, now time.Time) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid start time '%s': expected an RFC3339 timestamp or a duration", s)
+		}
+
+		t = now.Add(d)
+	}
+
+	if !t.After(now) {
+		return time.Time{}, fmt.Errorf("invalid start time '%s': must be in the future", s)
+	}
+
+	return t, nil
+}
+
+// startAt waits until the time given by `--start-at` and subsequently starts
+// the provided instances, which were created stopped.  KraftCloud cannot
+// schedule the start of an instance, so the instances are only started if the
+// command keeps running until then.
+func (opts *DeployOptions) startAt(ctx context.Context, insts []kcinstances.GetResponseItem) error {
+	log.G(ctx).
+		WithField("at", opts.startTime.Format(time.RFC3339)).
+		Infof("waiting to start %d instance(s)", len(insts))
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("interrupted before the scheduled start: %d instance(s) left stopped", len(insts))
+	case <-time.After(time.Until(opts.startTime)):
+	}
+
+	for _, inst := range insts {
+		client := kraftcloud.NewInstancesClient(
+			kraftcloud.WithToken(config.GetKraftCloudTokenAuthConfig(*opts.Auth)),
+			kraftcloud.WithDefaultMetro(opts.metroOf(inst)),
+			kraftcloud.WithHTTPClient(utils.NewRetryHTTPClient(ctx, opts.Retries)),
+		)
+
+		if _, err := client.StartByUUIDs(ctx, 0, inst.UUID); err != nil {
+			return fmt.Errorf("could not start instance %s: %w", inst.Name, err)
+		}
+
+		log.G(ctx).
+			WithField("instance", inst.Name).
+			Info("started")
+	}
+
+	return nil
+}
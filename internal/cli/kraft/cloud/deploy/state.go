@@ -0,0 +1,266 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package deploy
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"kraftkit.sh/initrd"
+	"kraftkit.sh/internal/cli/kraft/build"
+	"kraftkit.sh/log"
+	"kraftkit.sh/unikraft/target"
+)
+
+// The phases of a deployment which are recorded in the state file such that
+// they can be skipped when the deployment is re-run.  Packaging and pushing
+// the image are performed in one step.
+const (
+	phaseBuilt  = "built"
+	phasePushed = "pushed"
+)
+
+// stateFile is the path, relative to the working directory, of the file which
+// records the completed phases of the last deployment of the project.
+var stateFile = filepath.Join(".kraft", "deploy-state.json")
+
+// stateIgnoredDirs are the directories of the project which are not part of
+// the content hash, since they are written to by the deployment itself.  The
+// paths excluded by the ignore file of the project, see initrd.FindIgnoreFile,
+// are not part of it either.
+var stateIgnoredDirs = []string{".git", ".kraft", ".unikraft"}
+
+// deployState is the content of the stateFile.
+type deployState struct {
+	// Hash of the content of the project and of the options which affect the
+	// resulting image.  The phases are only valid for this hash.
+	Hash   string   `json:"hash"`
+	Phases []string `json:"phases"`
+
+	// Image and Digest are the reference and digest of the pushed image.
+	Image  string `json:"image,omitempty"`
+	Digest string `json:"digest,omitempty"`
+}
+
+// done returns whether the provided phase was completed.
+func (state *deployState) done(phase string) bool {
+	return state != nil && slices.Contains(state.Phases, phase)
+}
+
+// loadState returns the state of the last deployment of the project in the
+// working directory, or an empty state if the project has changed since, or
+// the cache is disabled via `--no-cache` or `--force-pull`.
+func (opts *DeployOptions) loadState(ctx context.Context) *deployState {
+	if opts.state != nil {
+		return opts.state
+	}
+
+	hash, err := opts.contentHash()
+	if err != nil {
+		log.G(ctx).WithError(err).Debug("could not hash project")
+		return nil
+	}
+
+	opts.state = &deployState{Hash: hash}
+
+	if opts.NoCache || opts.ForcePull || opts.PullPolicy == build.PullPolicyAlways {
+		return opts.state
+	}
+
+	data, err := os.ReadFile(filepath.Join(opts.Workdir, stateFile))
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			log.G(ctx).WithError(err).Debug("could not read deploy state")
+		}
+		return opts.state
+	}
+
+	var state deployState
+	if err := json.Unmarshal(data, &state); err != nil {
+		log.G(ctx).WithError(err).Debug("could not parse deploy state")
+		return opts.state
+	}
+
+	if state.Hash == hash {
+		opts.state = &state
+	}
+
+	return opts.state
+}
+
+// builtKernelExists returns whether the kernel built for KraftCloud by a
+// previous deployment still exists, such that the build can be skipped.
+func (opts *DeployOptions) builtKernelExists() bool {
+	if opts.Project == nil {
+		return false
+	}
+
+	for _, targ := range target.Filter(opts.Project.Targets(), "x86_64", "kraftcloud", "") {
+		kernel := targ.Kernel()
+		if opts.KernelDbg {
+			kernel = targ.KernelDbg()
+		}
+		if !filepath.IsAbs(kernel) {
+			kernel = filepath.Join(opts.Workdir, kernel)
+		}
+
+		if fi, err := os.Stat(kernel); err == nil && fi.Mode().IsRegular() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// pushedImageExists returns whether the image pushed by a previous deployment
+// is still available in each of the targeted metros, such that packaging and
+// pushing it can be skipped.
+func (opts *DeployOptions) pushedImageExists(ctx context.Context, state *deployState) bool {
+	if state == nil || state.Image == "" || state.Digest == "" {
+		return false
+	}
+
+	for _, metro := range opts.Metros {
+		images, err := opts.Client.Images().WithMetro(metro).List(ctx)
+		if err != nil {
+			log.G(ctx).
+				WithError(err).
+				WithField("metro", metro).
+				Debug("could not list images")
+			return false
+		}
+
+		found := false
+		for _, image := range images {
+			split := strings.Split(image.Digest, "@sha256:")
+			if strings.HasPrefix(split[len(split)-1], state.Digest) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			log.G(ctx).
+				WithField("image", state.Image).
+				WithField("metro", metro).
+				Debug("previously pushed image is not available anymore")
+			return false
+		}
+	}
+
+	return true
+}
+
+// recordPhase marks the provided phase as completed in the stateFile.
+func (opts *DeployOptions) recordPhase(ctx context.Context, phase string) {
+	state := opts.loadState(ctx)
+	if state == nil || state.done(phase) {
+		return
+	}
+
+	state.Phases = append(state.Phases, phase)
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err == nil {
+		path := filepath.Join(opts.Workdir, stateFile)
+		if err = os.MkdirAll(filepath.Dir(path), 0o755); err == nil {
+			err = os.WriteFile(path, data, 0o644)
+		}
+	}
+	if err != nil {
+		log.G(ctx).WithError(err).Debug("could not record deploy state")
+	}
+}
+
+// contentHash returns a hash of the files of the project in the working
+// directory and of the options which affect the resulting image.
+func (opts *DeployOptions) contentHash() (string, error) {
+	h := sha256.New()
+
+	for _, opt := range []string{
+		opts.Auth.User,
+		opts.DotConfig,
+		fmt.Sprint(opts.KernelDbg),
+		opts.Kraftfile,
+		opts.Name,
+		opts.Rootfs,
+		opts.Runtime,
+	} {
+		fmt.Fprintf(h, "%s\x00", opt)
+	}
+
+	ignored, err := initrd.IgnoreFunc(initrd.FindIgnoreFile(opts.Workdir), opts.Workdir)
+	if err != nil {
+		return "", err
+	}
+
+	err = filepath.WalkDir(opts.Workdir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(opts.Workdir, path)
+		if err != nil {
+			return err
+		} else if rel == "." {
+			return nil
+		}
+
+		if ignored(filepath.ToSlash(rel), d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		switch {
+		case d.IsDir():
+			if slices.Contains(stateIgnoredDirs, rel) {
+				return filepath.SkipDir
+			}
+			return nil
+
+		case d.Type()&fs.ModeSymlink != 0:
+			// Symbolic links are hashed by their target, which is not followed.
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(h, "%s\x00->%s\x00", filepath.ToSlash(rel), link)
+			return nil
+
+		case !d.Type().IsRegular():
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+
+		defer f.Close()
+
+		fmt.Fprintf(h, "%s\x00", filepath.ToSlash(rel))
+		_, err = io.Copy(h, f)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
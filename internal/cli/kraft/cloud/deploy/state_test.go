@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package deploy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDeployStateDone(t *testing.T) {
+	tests := []struct {
+		name     string
+		state    *deployState
+		phase    string
+		expected bool
+	}{
+		{name: "nil state", phase: phaseBuilt},
+		{name: "no phases", state: &deployState{}, phase: phaseBuilt},
+		{name: "done", state: &deployState{Phases: []string{phaseBuilt}}, phase: phaseBuilt, expected: true},
+		{name: "other phase done", state: &deployState{Phases: []string{phaseBuilt}}, phase: phasePushed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := tt.state.done(tt.phase); actual != tt.expected {
+				t.Errorf("expected %t, got %t", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestContentHash(t *testing.T) {
+	write := func(t *testing.T, dir, name, content string) {
+		t.Helper()
+
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		change  func(t *testing.T, opts *DeployOptions)
+		changed bool
+	}{
+		{
+			name:   "unchanged",
+			change: func(*testing.T, *DeployOptions) {},
+		},
+		{
+			name: "modified file",
+			change: func(t *testing.T, opts *DeployOptions) {
+				write(t, opts.Workdir, "main.c", "int main() { return 1; }")
+			},
+			changed: true,
+		},
+		{
+			name: "new file",
+			change: func(t *testing.T, opts *DeployOptions) {
+				write(t, opts.Workdir, "lib/util.c", "")
+			},
+			changed: true,
+		},
+		{
+			name: "changed option",
+			change: func(_ *testing.T, opts *DeployOptions) {
+				opts.Runtime = "base:latest"
+			},
+			changed: true,
+		},
+		{
+			name: "file in ignored directory",
+			change: func(t *testing.T, opts *DeployOptions) {
+				write(t, opts.Workdir, ".unikraft/build/kernel", "")
+			},
+		},
+		{
+			name: "file excluded by ignore file",
+			change: func(t *testing.T, opts *DeployOptions) {
+				write(t, opts.Workdir, "app.log", "")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &DeployOptions{Workdir: t.TempDir()}
+			write(t, opts.Workdir, "main.c", "int main() { return 0; }")
+			write(t, opts.Workdir, ".kraftignore", "*.log\n")
+
+			before, err := opts.contentHash()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			tt.change(t, opts)
+
+			after, err := opts.contentHash()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if changed := before != after; changed != tt.changed {
+				t.Errorf("expected changed to be %t, got %t", tt.changed, changed)
+			}
+		})
+	}
+}
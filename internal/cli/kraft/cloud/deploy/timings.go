@@ -0,0 +1,113 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"kraftkit.sh/internal/cli/kraft/build"
+	"kraftkit.sh/internal/cli/kraft/pkg"
+	"kraftkit.sh/internal/tableprinter"
+	"kraftkit.sh/iostreams"
+)
+
+// timingProvision is the phase of a deployment during which its instances are
+// created in KraftCloud, which follows the phases of the build and packaging.
+const timingProvision = "provision"
+
+// timingPhases are the phases of a deployment whose duration is reported via
+// `--timings`, in the order in which they are performed.
+var timingPhases = []string{
+	build.StepFetch,
+	build.StepConfigure,
+	build.StepBuild,
+	pkg.StepPackage,
+	pkg.StepPush,
+	timingProvision,
+}
+
+// phaseTiming is the duration of a phase of the deployment as written to the
+// file provided via `--output-file`.
+type phaseTiming struct {
+	Phase   string  `json:"phase"`
+	Seconds float64 `json:"seconds"`
+}
+
+// phaseTimings returns the duration of each of the phases which were performed
+// during the deployment, or nil if `--timings` is not set.  Phases which were
+// skipped, e.g. the build of an unchanged project, are omitted.
+func (opts *DeployOptions) phaseTimings() []phaseTiming {
+	if opts.timings == nil {
+		return nil
+	}
+
+	var timings []phaseTiming
+	for _, phase := range timingPhases {
+		if d, ok := opts.timings[phase]; ok {
+			timings = append(timings, phaseTiming{
+				Phase:   phase,
+				Seconds: d.Seconds(),
+			})
+		}
+	}
+
+	return timings
+}
+
+// printTimings prints a table of the duration of each of the phases of the
+// deployment and the share of the total time it took.  When the output is
+// meant to be consumed by a program, the timings are printed to stderr such
+// that the output remains parseable, as JSON if the output is.
+func (opts *DeployOptions) printTimings(ctx context.Context, total time.Duration) error {
+	format := "table"
+	var out io.Writer = iostreams.G(ctx).Out
+	if opts.Output != "" && opts.Output != "table" {
+		out = iostreams.G(ctx).ErrOut
+		if opts.Output == "json" || opts.Output == "jsonl" {
+			format = opts.Output
+		}
+	}
+
+	cs := iostreams.G(ctx).ColorScheme()
+	table, err := tableprinter.NewTablePrinter(ctx,
+		tableprinter.WithMaxWidth(iostreams.G(ctx).TerminalWidth()),
+		tableprinter.WithOutputFormatFromString(format),
+	)
+	if err != nil {
+		return err
+	}
+
+	table.AddField("PHASE", cs.Bold)
+	table.AddField("DURATION", cs.Bold)
+	table.AddField("SHARE", cs.Bold)
+	table.EndRow()
+
+	for _, phase := range timingPhases {
+		d, ok := opts.timings[phase]
+		if !ok {
+			continue
+		}
+
+		table.AddField(phase, nil)
+		table.AddField(d.Round(time.Millisecond).String(), nil)
+		if total > 0 {
+			table.AddField(fmt.Sprintf("%.0f%%", 100*float64(d)/float64(total)), nil)
+		} else {
+			table.AddField("", nil)
+		}
+		table.EndRow()
+	}
+
+	table.AddField("total", nil)
+	table.AddField(total.Round(time.Millisecond).String(), nil)
+	table.AddField("100%", nil)
+	table.EndRow()
+
+	return table.Render(out)
+}
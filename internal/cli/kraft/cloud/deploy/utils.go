@@ -7,6 +7,20 @@ package deploy
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/compose-spec/compose-go/dotenv"
+	"golang.org/x/sync/errgroup"
+
+	kcinstances "sdk.kraft.cloud/instances"
+	kcservices "sdk.kraft.cloud/services"
 
 	"kraftkit.sh/unikraft/app"
 )
@@ -34,3 +48,221 @@ func (opts *DeployOptions) initProject(ctx context.Context) error {
 
 	return nil
 }
+
+// mergeEnvFile parses the dotenv file provided via `--env-file` and merges its
+// variables into the list of environmental variables.  Variables which have
+// been explicitly set via `--env` take precedence over those in the file.
+func (opts *DeployOptions) mergeEnvFile() error {
+	vars, err := dotenv.Read(opts.EnvFile)
+	if err != nil {
+		return fmt.Errorf("could not read env file '%s': %w", opts.EnvFile, err)
+	}
+
+	explicit := make(map[string]struct{}, len(opts.Env))
+	for _, env := range opts.Env {
+		key, _, _ := strings.Cut(env, "=")
+		explicit[key] = struct{}{}
+	}
+
+	keys := make([]string, 0, len(vars))
+	for key := range vars {
+		if _, ok := explicit[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		opts.Env = append(opts.Env, fmt.Sprintf("%s=%s", key, vars[key]))
+	}
+
+	return nil
+}
+
+// domainRegex matches a fully qualified domain name, without its trailing dot.
+var domainRegex = regexp.MustCompile(`^([a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?\.)+[a-z]{2,63}$`)
+
+// maxDomains is the number of domains a service group can currently carry.
+const maxDomains = 1
+
+// parseDomains validates the provided FQDNs and returns them without their
+// trailing dot, in lowercase and without duplicates.
+func parseDomains(fqdns []string) ([]string, error) {
+	var parsed []string
+
+	for _, fqdn := range fqdns {
+		fqdn = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(fqdn), "."))
+		if !domainRegex.MatchString(fqdn) || len(fqdn) > 253 {
+			return nil, fmt.Errorf("invalid fqdn '%s': expected a domain name such as my-app.example.com", fqdn)
+		}
+
+		if !slices.Contains(parsed, fqdn) {
+			parsed = append(parsed, fqdn)
+		}
+	}
+
+	// KraftCloud creates service groups with a single domain and cannot add
+	// domains to existing ones, such that the others could not be attached.
+	if len(parsed) > maxDomains {
+		return nil, fmt.Errorf("cannot attach %d domains (%s): KraftCloud service groups currently accept a single domain", len(parsed), strings.Join(parsed, ", "))
+	}
+
+	return parsed, nil
+}
+
+// fqdn returns the FQDN with which the service group of the new deployment is
+// created, if any.
+func (opts *DeployOptions) fqdn() string {
+	if len(opts.FQDN) == 0 {
+		return ""
+	}
+
+	return opts.FQDN[0]
+}
+
+// domain is a domain requested for the new deployment, via either `--subdomain`
+// or `--fqdn`.
+type domain struct {
+	name      string
+	subdomain bool
+}
+
+// String implements fmt.Stringer.
+func (d domain) String() string {
+	if d.subdomain {
+		return fmt.Sprintf("subdomain '%s'", d.name)
+	}
+
+	return fmt.Sprintf("fqdn '%s'", d.name)
+}
+
+// domains returns the domains requested for the new deployment which must not
+// be taken by another service group.  The FQDNs are not included when joining
+// the service group provided via `--service-group`, since they may be its own.
+func (opts *DeployOptions) domains() []domain {
+	var domains []domain
+
+	if subdomain := strings.TrimSuffix(opts.SubDomain, "."); subdomain != "" {
+		domains = append(domains, domain{name: subdomain, subdomain: true})
+	}
+
+	if opts.ServiceGroupNameOrUUID == "" {
+		for _, fqdn := range opts.FQDN {
+			domains = append(domains, domain{name: strings.TrimSuffix(fqdn, ".")})
+		}
+	}
+
+	return domains
+}
+
+// serviceGroupLookups is the maximum number of concurrent requests made to
+// retrieve the details of the service groups, see domainHolders.
+const serviceGroupLookups = 8
+
+// domainHolders returns the existing service groups in the metro which already
+// use one of the domains of the new deployment, see domains, keyed by the name
+// of the domain they hold.
+func (opts *DeployOptions) domainHolders(ctx context.Context) (map[string]*kcservices.GetResponseItem, error) {
+	domains := opts.domains()
+	holders := map[string]*kcservices.GetResponseItem{}
+
+	if len(domains) == 0 {
+		return holders, nil
+	}
+
+	sgs, err := opts.Client.Services().WithMetro(opts.Metro).List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not list service groups: %w", err)
+	}
+
+	// The listed service groups lack their domain and there is no request which
+	// retrieves the details of several at once, so retrieve them concurrently.
+	details := make([]*kcservices.GetResponseItem, len(sgs))
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(serviceGroupLookups)
+
+	for i, sgItem := range sgs {
+		i, uuid := i, sgItem.UUID
+		g.Go(func() error {
+			sg, err := opts.Client.Services().WithMetro(opts.Metro).GetByUUID(gctx, uuid)
+			if err != nil {
+				return fmt.Errorf("getting details of service group %s: %w", uuid, err)
+			}
+
+			details[i] = sg
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	for _, sg := range details {
+		sgFQDN := strings.TrimSuffix(sg.FQDN, ".")
+		if sgFQDN == "" {
+			continue
+		}
+
+		label, _, _ := strings.Cut(sgFQDN, ".")
+
+		for _, domain := range domains {
+			if (domain.subdomain && label == domain.name) || (!domain.subdomain && strings.EqualFold(sgFQDN, domain.name)) {
+				holders[domain.name] = sg
+			}
+		}
+	}
+
+	return holders, nil
+}
+
+// deployResult is the structure written to the file provided via
+// `--output-file`.
+type deployResult struct {
+	Instances     []kcinstances.GetResponseItem `json:"instances"`
+	ServiceGroups []kcservices.GetResponseItem  `json:"service_groups"`
+	FQDNs         []string                      `json:"fqdns"`
+	Timings       []phaseTiming                 `json:"timings,omitempty"`
+}
+
+// writeOutputFile atomically writes the result of the deployment as JSON to
+// the provided path, including the duration of its phases if recorded.
+func writeOutputFile(path string, insts []kcinstances.GetResponseItem, sgs []kcservices.GetResponseItem, timings []phaseTiming) error {
+	result := deployResult{
+		Instances:     insts,
+		ServiceGroups: sgs,
+		FQDNs:         []string{},
+		Timings:       timings,
+	}
+
+	for _, inst := range insts {
+		if len(inst.FQDN) > 0 {
+			result.FQDNs = append(result.FQDNs, inst.FQDN)
+		}
+	}
+
+	b, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("serializing result to JSON: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(b, '\n')); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
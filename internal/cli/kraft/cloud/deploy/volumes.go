@@ -0,0 +1,161 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"kraftkit.sh/internal/cli/kraft/cloud/utils"
+	kraftutils "kraftkit.sh/internal/cli/kraft/utils"
+	"kraftkit.sh/log"
+)
+
+// parseVolumes validates the volumes provided via `--volume` in the form
+// NAME:DEST[:OPTIONS] where OPTIONS is a comma-separated list of `ro` and
+// `create=SIZE`.  Volumes which should be created are recorded and the
+// `create` option is removed such that the remaining specification can be
+// passed on when creating the instance.
+func (opts *DeployOptions) parseVolumes() error {
+	opts.createVolumes = map[string]int{}
+
+	for i, vol := range opts.Volumes {
+		split := strings.Split(vol, ":")
+		if len(split) < 2 || len(split) > 3 {
+			return fmt.Errorf("invalid volume '%s': expected NAME:DEST[:OPTIONS]", vol)
+		}
+
+		if len(split) == 2 {
+			continue
+		}
+
+		var keep []string
+		for _, option := range strings.Split(split[2], ",") {
+			key, value, _ := strings.Cut(option, "=")
+			switch key {
+			case "ro":
+				keep = append(keep, key)
+
+			case "create":
+				if utils.IsUUID(split[0]) {
+					return fmt.Errorf("invalid volume '%s': cannot create a volume by UUID", vol)
+				}
+
+				sizeMB, err := kraftutils.ParseSizeMB(value)
+				if err != nil {
+					return fmt.Errorf("invalid volume '%s': %w", vol, err)
+				}
+
+				if existing, ok := opts.createVolumes[split[0]]; ok && existing != sizeMB {
+					return fmt.Errorf("invalid volume '%s': conflicting sizes for volume %s", vol, split[0])
+				}
+
+				opts.createVolumes[split[0]] = sizeMB
+
+			default:
+				return fmt.Errorf("invalid volume '%s': unknown option '%s'", vol, option)
+			}
+		}
+
+		opts.Volumes[i] = strings.Join(split[:2], ":")
+		if len(keep) > 0 {
+			opts.Volumes[i] += ":" + strings.Join(keep, ",")
+		}
+	}
+
+	return nil
+}
+
+// existingVolumes returns the UUIDs of the volumes in the metro, keyed by their
+// name.
+func (opts *DeployOptions) existingVolumes(ctx context.Context) (map[string]string, error) {
+	vols, err := opts.Client.Volumes().WithMetro(opts.Metro).List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not list volumes: %w", err)
+	}
+
+	existing := make(map[string]string, len(vols))
+	for _, vol := range vols {
+		existing[vol.Name] = vol.UUID
+	}
+
+	return existing, nil
+}
+
+// checkExistingVolumes checks that each of the volumes which were requested to
+// be created via the `create=SIZE` option and which already exist matches the
+// requested size, as only then is it re-used instead of being created.
+func (opts *DeployOptions) checkExistingVolumes(ctx context.Context) error {
+	if len(opts.createVolumes) == 0 {
+		return nil
+	}
+
+	existing, err := opts.existingVolumes(ctx)
+	if err != nil {
+		return err
+	}
+
+	for name, sizeMB := range opts.createVolumes {
+		uuid, ok := existing[name]
+		if !ok {
+			continue
+		}
+
+		vol, err := opts.Client.Volumes().WithMetro(opts.Metro).GetByUUID(ctx, uuid)
+		if err != nil {
+			return fmt.Errorf("getting details of volume %s: %w", name, err)
+		}
+
+		if vol.SizeMB != sizeMB {
+			return fmt.Errorf("volume %s already exists with a size of %dMiB instead of %dMiB", name, vol.SizeMB, sizeMB)
+		}
+	}
+
+	return nil
+}
+
+// createMissingVolumes creates the volumes which were requested to be created
+// via the `create=SIZE` option and which do not yet exist.  It is called right
+// before the instance is created, such that no volume is created if the
+// deployment fails beforehand, e.g. while building.  Existing volumes were
+// checked to match the requested size beforehand, see checkExistingVolumes.
+// The created volumes are removed again if the deployment does not complete,
+// see cleanup.
+func (opts *DeployOptions) createMissingVolumes(ctx context.Context) error {
+	if len(opts.createVolumes) == 0 {
+		return nil
+	}
+
+	existing, err := opts.existingVolumes(ctx)
+	if err != nil {
+		return err
+	}
+
+	for name, sizeMB := range opts.createVolumes {
+		if _, ok := existing[name]; ok {
+			log.G(ctx).
+				WithField("volume", name).
+				Debug("re-using existing volume")
+
+			continue
+		}
+
+		log.G(ctx).
+			WithField("volume", name).
+			WithField("size", fmt.Sprintf("%dMiB", sizeMB)).
+			Info("creating volume")
+
+		vol, err := opts.Client.Volumes().WithMetro(opts.Metro).Create(ctx, name, sizeMB)
+		if err != nil {
+			return fmt.Errorf("could not create volume %s: %w", name, err)
+		}
+
+		opts.created.addVolume(opts.Metro, vol.UUID, name)
+	}
+
+	return nil
+}
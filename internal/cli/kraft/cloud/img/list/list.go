@@ -28,8 +28,9 @@ import (
 )
 
 type ListOptions struct {
-	All    bool   `long:"all" usage:"Also show available official images"`
-	Output string `long:"output" short:"o" usage:"Set output format. Options: table,yaml,json,list" default:"table"`
+	All     bool     `long:"all" usage:"Also show available official images"`
+	Columns []string `long:"columns" usage:"Only show the given comma-separated columns, in the given order"`
+	Output  string   `long:"output" short:"o" usage:"Set output format. Options: table,yaml,json,jsonl,list,go-template=TEMPLATE" default:"table"`
 
 	metro string
 	token string
@@ -66,6 +67,10 @@ func NewCmd() *cobra.Command {
 }
 
 func (opts *ListOptions) Pre(cmd *cobra.Command, _ []string) error {
+	if err := utils.ValidateOutput(opts.Output); err != nil {
+		return err
+	}
+
 	err := utils.PopulateMetroToken(cmd, &opts.metro, &opts.token)
 	if err != nil {
 		return fmt.Errorf("could not populate metro and token: %w", err)
@@ -75,6 +80,8 @@ func (opts *ListOptions) Pre(cmd *cobra.Command, _ []string) error {
 }
 
 func (opts *ListOptions) Run(ctx context.Context, args []string) error {
+	ctx = tableprinter.WithColumnsInContext(ctx, opts.Columns)
+
 	auth, err := config.GetKraftCloudAuthConfig(ctx, opts.token)
 	if err != nil {
 		return fmt.Errorf("could not retrieve credentials: %w", err)
@@ -82,6 +89,7 @@ func (opts *ListOptions) Run(ctx context.Context, args []string) error {
 
 	client := kraftcloud.NewImagesClient(
 		kraftcloud.WithToken(config.GetKraftCloudTokenAuthConfig(*auth)),
+		kraftcloud.WithHTTPClient(utils.NewHTTPClient(ctx)),
 	)
 
 	images, err := client.WithMetro(opts.metro).List(ctx)
@@ -10,8 +10,8 @@ import (
 	"fmt"
 	"os"
 	"slices"
-	"strconv"
 	"strings"
+	"time"
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/spf13/cobra"
@@ -23,6 +23,7 @@ import (
 	"kraftkit.sh/cmdfactory"
 	"kraftkit.sh/config"
 	"kraftkit.sh/internal/cli/kraft/cloud/utils"
+	kraftutils "kraftkit.sh/internal/cli/kraft/utils"
 	"kraftkit.sh/log"
 )
 
@@ -30,23 +31,51 @@ type CreateOptions struct {
 	Auth                   *config.AuthConfig    `noattribute:"true"`
 	Client                 kraftcloud.KraftCloud `noattribute:"true"`
 	Env                    []string              `local:"true" long:"env" short:"e" usage:"Environmental variables"`
+	EnvExpand              bool                  `local:"true" long:"env-expand" usage:"Expand ${VAR} and ${VAR:-default} in the values of --env from the caller's environment"`
 	Features               []string              `local:"true" long:"feature" short:"f" usage:"List of features to enable"`
 	FQDN                   string                `local:"true" long:"fqdn" short:"d" usage:"The Fully Qualified Domain Name to use for the service"`
 	Image                  string                `noattribute:"true"`
-	Memory                 int                   `local:"true" long:"memory" short:"M" usage:"Specify the amount of memory to allocate (MiB)"`
+	Memory                 int                   `noattribute:"true"`
 	Metro                  string                `noattribute:"true"`
 	Name                   string                `local:"true" long:"name" short:"n" usage:"Specify the name of the instance"`
-	Output                 string                `local:"true" long:"output" short:"o" usage:"Set output format. Options: table,yaml,json,list" default:"table"`
+	Output                 string                `local:"true" long:"output" short:"o" usage:"Set output format. Options: table,yaml,json,list,go-template=TEMPLATE" default:"table"`
 	Ports                  []string              `local:"true" long:"port" short:"p" usage:"Specify the port mapping between external to internal"`
 	Replicas               int                   `local:"true" long:"replicas" short:"R" usage:"Number of replicas of the instance" default:"0"`
 	ServiceGroupNameOrUUID string                `local:"true" long:"service-group" short:"g" usage:"Attach this instance to an existing service group"`
 	Start                  bool                  `local:"true" long:"start" short:"S" usage:"Immediately start the instance after creation"`
 	ScaleToZero            bool                  `local:"true" long:"scale-to-zero" short:"0" usage:"Scale the instance to zero after deployment"`
+	ScaleToZeroCooldown    time.Duration         `local:"true" long:"scale-to-zero-cooldown" usage:"Idle time after which the instance is scaled to zero (requires --scale-to-zero)"`
 	SubDomain              string                `local:"true" long:"subdomain" short:"s" usage:"Set the subdomain to use when creating the service"`
 	Token                  string                `noattribute:"true"`
 	Volumes                []string              `local:"true" long:"volumes" short:"v" usage:"List of volumes to attach instance to"`
 }
 
+// MaxScaleToZeroCooldown is the longest accepted time an idle instance waits
+// before it is scaled to zero.
+const MaxScaleToZeroCooldown = time.Hour
+
+// ValidateScaleToZeroCooldown checks that a scale-to-zero cooldown is only set
+// when scale-to-zero is enabled and that it is within the accepted range.
+func ValidateScaleToZeroCooldown(scaleToZero bool, cooldown time.Duration) error {
+	if cooldown == 0 {
+		return nil
+	}
+
+	if !scaleToZero {
+		return fmt.Errorf("cannot use --scale-to-zero-cooldown without --scale-to-zero")
+	}
+
+	if cooldown < time.Millisecond {
+		return fmt.Errorf("scale-to-zero cooldown must be at least 1ms")
+	}
+
+	if cooldown > MaxScaleToZeroCooldown {
+		return fmt.Errorf("scale-to-zero cooldown must not exceed %s", MaxScaleToZeroCooldown)
+	}
+
+	return nil
+}
+
 // Create a KraftCloud instance.
 func Create(ctx context.Context, opts *CreateOptions, args ...string) (*kcinstances.GetResponseItem, *kcservices.GetResponseItem, error) {
 	var err error
@@ -64,9 +93,14 @@ func Create(ctx context.Context, opts *CreateOptions, args ...string) (*kcinstan
 	if opts.Client == nil {
 		opts.Client = kraftcloud.NewClient(
 			kraftcloud.WithToken(config.GetKraftCloudTokenAuthConfig(*opts.Auth)),
+			kraftcloud.WithHTTPClient(utils.NewHTTPClient(ctx)),
 		)
 	}
 
+	if err := ValidateScaleToZeroCooldown(opts.ScaleToZero, opts.ScaleToZeroCooldown); err != nil {
+		return nil, nil, err
+	}
+
 	var features []kcinstances.Feature
 
 	if opts.ScaleToZero {
@@ -97,6 +131,12 @@ func Create(ctx context.Context, opts *CreateOptions, args ...string) (*kcinstan
 	if opts.Replicas > 0 {
 		req.MemoryMB = &opts.Replicas
 	}
+	if opts.ScaleToZeroCooldown > 0 {
+		cooldownMs := int(opts.ScaleToZeroCooldown.Milliseconds())
+		req.ScaleToZero = &kcinstances.CreateRequestScaleToZero{
+			CooldownTimeMs: &cooldownMs,
+		}
+	}
 
 	for _, vol := range opts.Volumes {
 		split := strings.Split(vol, ":")
@@ -152,21 +192,16 @@ func Create(ctx context.Context, opts *CreateOptions, args ...string) (*kcinstan
 	var services []kcservices.CreateRequestService
 
 	if len(opts.Ports) == 1 && strings.HasPrefix(opts.Ports[0], "443:") && strings.Count(opts.Ports[0], "/") == 0 {
-		split := strings.Split(opts.Ports[0], ":")
-		if len(split) != 2 {
-			return nil, nil, fmt.Errorf("malformed port expected format EXTERNAL:INTERNAL[/HANDLER[,HANDLER...]]")
-		}
-
-		destPort, err := strconv.Atoi(split[1])
+		service, err := utils.ParsePort(opts.Ports[0])
 		if err != nil {
-			return nil, nil, fmt.Errorf("invalid external port: %w", err)
+			return nil, nil, err
 		}
 
 		port443 := 443
 		services = []kcservices.CreateRequestService{
 			{
 				Port:            443,
-				DestinationPort: &destPort,
+				DestinationPort: service.DestinationPort,
 				Handlers: []kcservices.Handler{
 					kcservices.HandlerHTTP,
 					kcservices.HandlerTLS,
@@ -184,53 +219,12 @@ func Create(ctx context.Context, opts *CreateOptions, args ...string) (*kcinstan
 
 	} else {
 		for _, port := range opts.Ports {
-			var service kcservices.CreateRequestService
-
-			if strings.ContainsRune(port, '/') {
-				split := strings.Split(port, "/")
-				if len(split) != 2 {
-					return nil, nil, fmt.Errorf("malformed port expected format EXTERNAL:INTERNAL[/HANDLER[,HANDLER...]]")
-				}
-
-				for _, handler := range strings.Split(split[1], "+") {
-					h := kcservices.Handler(handler)
-					if !slices.Contains(kcservices.Handlers(), h) {
-						return nil, nil, fmt.Errorf("unknown handler: %s (choice of %v)", handler, kcservices.Handlers())
-					}
-
-					service.Handlers = append(service.Handlers, h)
-				}
-
-				port = split[0]
+			service, err := utils.ParsePort(port)
+			if err != nil {
+				return nil, nil, err
 			}
 
-			if strings.ContainsRune(port, ':') {
-				ports := strings.Split(port, ":")
-				if len(ports) != 2 {
-					return nil, nil, fmt.Errorf("invalid --port value expected --port EXTERNAL:INTERNAL")
-				}
-
-				service.Port, err = strconv.Atoi(ports[0])
-				if err != nil {
-					return nil, nil, fmt.Errorf("invalid internal port: %w", err)
-				}
-
-				dstPort, err := strconv.Atoi(ports[1])
-				if err != nil {
-					return nil, nil, fmt.Errorf("invalid external port: %w", err)
-				}
-				service.DestinationPort = &dstPort
-			} else {
-				port, err := strconv.Atoi(port)
-				if err != nil {
-					return nil, nil, fmt.Errorf("could not parse port number: %w", err)
-				}
-
-				service.Port = port
-				service.DestinationPort = &port
-			}
-
-			services = append(services, service)
+			services = append(services, *service)
 		}
 	}
 
@@ -346,15 +340,34 @@ func NewCmd() *cobra.Command {
 		"Alias for --fqdn|-d",
 	)
 
+	cmd.Flags().StringP(
+		"memory",
+		"M",
+		"",
+		"Specify the amount of memory to allocate, "+kraftutils.SizeUsage,
+	)
+
 	return cmd
 }
 
 func (opts *CreateOptions) Pre(cmd *cobra.Command, _ []string) error {
+	if err := utils.ValidateOutput(opts.Output); err != nil {
+		return err
+	}
+
 	err := utils.PopulateMetroToken(cmd, &opts.Metro, &opts.Token)
 	if err != nil {
 		return fmt.Errorf("could not populate metro and token: %w", err)
 	}
 
+	if err := ValidateScaleToZeroCooldown(opts.ScaleToZero, opts.ScaleToZeroCooldown); err != nil {
+		return err
+	}
+
+	if err := utils.PopulateSizeMB(cmd, "memory", &opts.Memory); err != nil {
+		return err
+	}
+
 	domain := cmd.Flag("domain").Value.String()
 	if domain != "" && opts.FQDN != "" {
 		return fmt.Errorf("cannot use --domain and --fqdn together")
@@ -362,6 +375,13 @@ func (opts *CreateOptions) Pre(cmd *cobra.Command, _ []string) error {
 		opts.FQDN = domain
 	}
 
+	if opts.EnvExpand {
+		opts.Env, err = utils.ExpandEnv(opts.Env)
+		if err != nil {
+			return err
+		}
+	}
+
 	log.G(cmd.Context()).WithField("metro", opts.Metro).Debug("using")
 	return nil
 }
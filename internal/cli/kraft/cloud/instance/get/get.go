@@ -21,7 +21,7 @@ import (
 )
 
 type GetOptions struct {
-	Output string `long:"output" short:"o" usage:"Set output format. Options: table,yaml,json,list" default:"table"`
+	Output string `long:"output" short:"o" usage:"Set output format. Options: table,yaml,json,list,go-template=TEMPLATE" default:"table"`
 
 	metro string
 	token string
@@ -41,7 +41,7 @@ func NewCmd() *cobra.Command {
 		Short:   "Retrieve the state of an instance",
 		Use:     "get [FLAGS] UUID|NAME",
 		Args:    cobra.ExactArgs(1),
-		Aliases: []string{"status", "info"},
+		Aliases: []string{"info"},
 		Example: heredoc.Doc(`
 			# Retrieve information about a kraftcloud instance by UUID
 			$ kraft cloud instance get fd1684ea-7970-4994-92d6-61dcc7905f2b
@@ -64,6 +64,10 @@ func NewCmd() *cobra.Command {
 }
 
 func (opts *GetOptions) Pre(cmd *cobra.Command, _ []string) error {
+	if err := utils.ValidateOutput(opts.Output); err != nil {
+		return err
+	}
+
 	err := utils.PopulateMetroToken(cmd, &opts.metro, &opts.token)
 	if err != nil {
 		return fmt.Errorf("could not populate metro and token: %w", err)
@@ -80,6 +84,7 @@ func (opts *GetOptions) Run(ctx context.Context, args []string) error {
 
 	client := kraftcloud.NewInstancesClient(
 		kraftcloud.WithToken(config.GetKraftCloudTokenAuthConfig(*auth)),
+		kraftcloud.WithHTTPClient(utils.NewHTTPClient(ctx)),
 	)
 
 	var instances []kcinstances.GetResponseItem
@@ -0,0 +1,134 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package inspect
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+
+	kraftcloud "sdk.kraft.cloud"
+	kcservices "sdk.kraft.cloud/services"
+
+	"kraftkit.sh/cmdfactory"
+	"kraftkit.sh/config"
+	"kraftkit.sh/internal/cli/kraft/cloud/utils"
+)
+
+type InspectOptions struct {
+	Output string `long:"output" short:"o" usage:"Set output format. Options: wide,table,yaml,json,jsonl,list,go-template=TEMPLATE" default:"wide"`
+
+	metro string
+	token string
+}
+
+// Inspect one or more KraftCloud instances.
+func Inspect(ctx context.Context, opts *InspectOptions, args ...string) error {
+	if opts == nil {
+		opts = &InspectOptions{}
+	}
+
+	return opts.Run(ctx, args)
+}
+
+func NewCmd() *cobra.Command {
+	cmd, err := cmdfactory.New(&InspectOptions{}, cobra.Command{
+		Short: "Display detailed information about instances",
+		Use:   "inspect [FLAGS] UUID|NAME [UUID|NAME...]",
+		Args:  cobra.MinimumNArgs(1),
+		Example: heredoc.Doc(`
+			# Display detailed information about an instance
+			$ kraft cloud instance inspect my-instance-431342
+
+			# Retrieve the complete state of multiple instances as a JSON array
+			$ kraft cloud instance inspect my-instance-431342 fd1684ea-7970-4994-92d6-61dcc7905f2b -o json
+		`),
+		Long: heredoc.Doc(`
+			Display detailed information about instances.
+
+			Unlike 'kraft cloud instance get', all details of the instances are shown
+			by default, including their UUIDs, metro, private addresses, environment,
+			annotations, attached volumes, service group and the ports and domains of
+			the latter.  The JSON and YAML output formats contain the complete state of
+			the instances as returned by KraftCloud and are always an array, regardless
+			of the number of instances.
+		`),
+		Annotations: map[string]string{
+			cmdfactory.AnnotationHelpGroup: "kraftcloud-instance",
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+func (opts *InspectOptions) Pre(cmd *cobra.Command, _ []string) error {
+	if err := utils.ValidateOutput(opts.Output); err != nil {
+		return err
+	}
+
+	err := utils.PopulateMetroToken(cmd, &opts.metro, &opts.token)
+	if err != nil {
+		return fmt.Errorf("could not populate metro and token: %w", err)
+	}
+
+	return nil
+}
+
+func (opts *InspectOptions) Run(ctx context.Context, args []string) error {
+	auth, err := config.GetKraftCloudAuthConfig(ctx, opts.token)
+	if err != nil {
+		return fmt.Errorf("could not retrieve credentials: %w", err)
+	}
+
+	client := kraftcloud.NewInstancesClient(
+		kraftcloud.WithToken(config.GetKraftCloudTokenAuthConfig(*auth)),
+		kraftcloud.WithHTTPClient(utils.NewHTTPClient(ctx)),
+	)
+
+	instances, err := utils.GetInstances(ctx, client.WithMetro(opts.metro), args...)
+	if err != nil {
+		return utils.ClassifyError(err)
+	}
+
+	services := kraftcloud.NewServicesClient(
+		kraftcloud.WithToken(config.GetKraftCloudTokenAuthConfig(*auth)),
+		kraftcloud.WithHTTPClient(utils.NewHTTPClient(ctx)),
+	)
+
+	// Retrieve the service group of each instance once, such that its ports and
+	// domains are shown alongside the instance.
+	serviceGroups := make([]*kcservices.GetResponseItem, len(instances))
+	byUUID := map[string]*kcservices.GetResponseItem{}
+	for i, instance := range instances {
+		if instance.ServiceGroup == nil {
+			continue
+		}
+
+		sg, ok := byUUID[instance.ServiceGroup.UUID]
+		if !ok {
+			sg, err = services.WithMetro(opts.metro).GetByUUID(ctx, instance.ServiceGroup.UUID)
+			if err != nil {
+				return utils.ClassifyError(fmt.Errorf("could not get service group of instance '%s': %w", instance.Name, err))
+			}
+
+			byUUID[instance.ServiceGroup.UUID] = sg
+		}
+
+		serviceGroups[i] = sg
+	}
+
+	metros := make([]string, len(instances))
+	for i := range metros {
+		metros[i] = opts.metro
+	}
+
+	return utils.PrintInspectedInstances(ctx, opts.Output, metros, serviceGroups, instances...)
+}
@@ -15,10 +15,13 @@ import (
 
 	"kraftkit.sh/internal/cli/kraft/cloud/instance/create"
 	"kraftkit.sh/internal/cli/kraft/cloud/instance/get"
+	"kraftkit.sh/internal/cli/kraft/cloud/instance/inspect"
 	"kraftkit.sh/internal/cli/kraft/cloud/instance/list"
 	"kraftkit.sh/internal/cli/kraft/cloud/instance/logs"
 	"kraftkit.sh/internal/cli/kraft/cloud/instance/remove"
+	"kraftkit.sh/internal/cli/kraft/cloud/instance/scale"
 	"kraftkit.sh/internal/cli/kraft/cloud/instance/start"
+	"kraftkit.sh/internal/cli/kraft/cloud/instance/status"
 	"kraftkit.sh/internal/cli/kraft/cloud/instance/stop"
 )
 
@@ -43,10 +46,24 @@ func NewCmd() *cobra.Command {
 	cmd.AddCommand(list.NewCmd())
 	cmd.AddCommand(logs.NewCmd())
 	cmd.AddCommand(remove.NewCmd())
+	cmd.AddCommand(scale.NewCmd())
 	cmd.AddCommand(start.NewCmd())
+	cmd.AddCommand(status.NewCmd())
 	cmd.AddCommand(get.NewCmd())
+	cmd.AddCommand(inspect.NewCmd())
 	cmd.AddCommand(stop.NewCmd())
 
+	// TODO: Add a `rename OLD NEW` subcommand, which accepts the UUID or name of
+	// the instance and checks that the new name is free like deploy does, once
+	// the KraftCloud API supports updating instances.  Recreating the instance
+	// under the new name instead would change its UUID and lose its state.
+	//
+	// TODO: Add an `attach` subcommand, which puts the terminal in raw mode and
+	// restores it on exit, with --no-tty for scripted use, once the KraftCloud
+	// API offers a bidirectional console stream.  The console can currently
+	// only be read as a snapshot of its most recent output, which `logs
+	// --follow` already polls.
+
 	return cmd
 }
 
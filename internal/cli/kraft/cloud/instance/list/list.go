@@ -16,13 +16,21 @@ import (
 	"kraftkit.sh/cmdfactory"
 	"kraftkit.sh/config"
 	"kraftkit.sh/internal/cli/kraft/cloud/utils"
+	"kraftkit.sh/internal/tableprinter"
+	"kraftkit.sh/iostreams"
 )
 
 type ListOptions struct {
-	Output string `long:"output" short:"o" usage:"Set output format. Options: table,yaml,json,list" default:"table"`
+	Columns []string `long:"columns" usage:"Only show the given comma-separated columns, in the given order"`
+	Output  string   `long:"output" short:"o" usage:"Set output format. Options: table,wide,yaml,json,jsonl,list,go-template=TEMPLATE" default:"table"`
+	Labels  []string `long:"label" split:"false" usage:"Only list instances with the given label in the form KEY=VALUE (can be used multiple times)"`
+	Quiet   bool     `long:"quiet" short:"q" usage:"Only display instance UUIDs, one per line"`
+	Sort    string   `long:"sort" usage:"Sort the instances by a field in the form FIELD[:asc|desc] (name, fqdn, state, created, image, memory, boot-time)"`
 
-	metro string
-	token string
+	labels    map[string]string
+	metro     string
+	token     string
+	sortOrder *tableprinter.SortOrder
 }
 
 func NewCmd() *cobra.Command {
@@ -34,6 +42,18 @@ func NewCmd() *cobra.Command {
 		Example: heredoc.Doc(`
 			# List all instances in your account.
 			$ kraft cloud instance list
+
+			# List all instances labelled as part of the staging environment.
+			$ kraft cloud instance list --label env=staging
+
+			# List all instances, the most recently created first.
+			$ kraft cloud instance list --sort created:desc
+
+			# List all instances with every available field, untruncated.
+			$ kraft cloud instance list -o wide
+
+			# Stop all instances labelled as part of the staging environment.
+			$ kraft cloud instance list --label env=staging -q | kraft cloud instance stop -
 		`),
 		Long: heredoc.Doc(`
 			List all instances in your account.
@@ -50,15 +70,27 @@ func NewCmd() *cobra.Command {
 }
 
 func (opts *ListOptions) Pre(cmd *cobra.Command, _ []string) error {
+	if err := utils.ValidateOutput(opts.Output); err != nil {
+		return err
+	}
+
 	err := utils.PopulateMetroToken(cmd, &opts.metro, &opts.token)
 	if err != nil {
 		return fmt.Errorf("could not populate metro and token: %w", err)
 	}
 
-	return nil
+	opts.labels, err = utils.ParseLabels(opts.Labels)
+	if err != nil {
+		return err
+	}
+
+	opts.sortOrder, err = tableprinter.ParseSortOrder(opts.Sort, utils.InstanceSortFields)
+	return err
 }
 
 func (opts *ListOptions) Run(ctx context.Context, args []string) error {
+	ctx = tableprinter.WithColumnsInContext(ctx, opts.Columns)
+
 	auth, err := config.GetKraftCloudAuthConfig(ctx, opts.token)
 	if err != nil {
 		return fmt.Errorf("could not retrieve credentials: %w", err)
@@ -66,6 +98,7 @@ func (opts *ListOptions) Run(ctx context.Context, args []string) error {
 
 	client := kraftcloud.NewInstancesClient(
 		kraftcloud.WithToken(config.GetKraftCloudTokenAuthConfig(*auth)),
+		kraftcloud.WithHTTPClient(utils.NewHTTPClient(ctx)),
 	)
 
 	instListResp, err := client.WithMetro(opts.metro).List(ctx)
@@ -85,5 +118,27 @@ func (opts *ListOptions) Run(ctx context.Context, args []string) error {
 		return fmt.Errorf("getting details of %d instance(s): %w", len(instListResp), err)
 	}
 
+	instances = utils.FilterInstancesByLabels(instances, opts.labels)
+	tableprinter.Sort(instances, opts.sortOrder, utils.InstanceSortFields)
+
+	if opts.Quiet {
+		for _, instance := range instances {
+			fmt.Fprintln(iostreams.G(ctx).Out, instance.UUID)
+		}
+
+		return nil
+	}
+
+	// The wide output format shows every available field, including the metro
+	// which is otherwise implied by --metro.
+	if opts.Output == string(tableprinter.OutputFormatWide) {
+		metros := make([]string, len(instances))
+		for i := range instances {
+			metros[i] = opts.metro
+		}
+
+		return utils.PrintMetroInstances(ctx, opts.Output, metros, instances...)
+	}
+
 	return utils.PrintInstances(ctx, opts.Output, instances...)
 }
@@ -8,6 +8,7 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"time"
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/spf13/cobra"
@@ -18,14 +19,22 @@ import (
 	"kraftkit.sh/cmdfactory"
 	"kraftkit.sh/config"
 	"kraftkit.sh/internal/cli/kraft/cloud/utils"
+	kraftutils "kraftkit.sh/internal/cli/kraft/utils"
 	"kraftkit.sh/iostreams"
+	"kraftkit.sh/log"
 )
 
 type LogOptions struct {
-	Tail int `local:"true" long:"tail" short:"n" usage:"Lines of recent logs to display" default:"-1"`
+	Follow   bool          `local:"true" long:"follow" short:"f" usage:"Follow the console output, reconnecting if the connection drops"`
+	Interval time.Duration `local:"true" long:"interval" usage:"Interval between polls of the console output when following (ms/s/m/h)" default:"1000000000"`
+	Since    string        `local:"true" long:"since" usage:"Only show output produced after the given RFC3339 timestamp or relative duration (e.g. 10m)"`
+	Tail     int           `local:"true" long:"tail" short:"n" usage:"Lines of recent logs to display" default:"-1"`
+	Until    string        `local:"true" long:"until" usage:"Only show output produced before the given RFC3339 timestamp or relative duration (e.g. 10m)"`
 
 	metro string
+	since time.Time
 	token string
+	until time.Time
 }
 
 // Log retrieves the console output from a KraftCloud instance.
@@ -49,9 +58,28 @@ func NewCmd() *cobra.Command {
 
 			# Get console output of a kraftcloud instance by name
 			$ kraft cloud instance logs my-instance-431342
+
+			# Follow the console output of a kraftcloud instance, e.g. across restarts
+			$ kraft cloud instance logs --follow my-instance-431342
+
+			# Follow the console output of a kraftcloud instance produced from now on
+			$ kraft cloud instance logs --follow --since 0s my-instance-431342
+
+			# Follow the console output of a kraftcloud instance until a given time
+			$ kraft cloud instance logs --follow --until 2024-01-02T15:04:05Z my-instance-431342
 		`),
 		Long: heredoc.Doc(`
 			Get console output of an instance.
+
+			With --follow, the console output is polled until interrupted.  If
+			the connection drops, e.g. due to a network failure, it is retried
+			with an exponential backoff.  When the instance restarts, its new
+			console output is shown as it appears.
+
+			The console output is not timestamped.  With --since and --until,
+			output which was buffered before the command started is therefore only
+			shown if the instance was created within the given times.  With
+			--follow, --until stops following once the given time is reached.
 		`),
 		Annotations: map[string]string{
 			cmdfactory.AnnotationHelpGroup: "kraftcloud-instance",
@@ -70,7 +98,13 @@ func (opts *LogOptions) Pre(cmd *cobra.Command, _ []string) error {
 		return fmt.Errorf("could not populate metro and token: %w", err)
 	}
 
-	return nil
+	if opts.Interval < time.Millisecond {
+		return fmt.Errorf("poll interval must be at least 1ms")
+	}
+
+	opts.since, opts.until, err = kraftutils.ParseTimeRange(opts.Since, opts.Until, time.Now())
+
+	return err
 }
 
 func (opts *LogOptions) Run(ctx context.Context, args []string) error {
@@ -81,25 +115,124 @@ func (opts *LogOptions) Run(ctx context.Context, args []string) error {
 
 	client := kraftcloud.NewInstancesClient(
 		kraftcloud.WithToken(config.GetKraftCloudTokenAuthConfig(*auth)),
+		kraftcloud.WithHTTPClient(utils.NewHTTPClient(ctx)),
 	)
 
+	buffered, err := opts.console(ctx, client, args[0])
+	if err != nil {
+		return err
+	}
+
+	output := buffered
+	if !opts.since.IsZero() || !opts.until.IsZero() {
+		created, err := opts.createdAt(ctx, client, args[0])
+		if err != nil {
+			return err
+		}
+
+		if !kraftutils.InTimeRange(created, opts.since, opts.until) {
+			output = ""
+		}
+	}
+
+	if !opts.Follow {
+		fmt.Fprintf(iostreams.G(ctx).Out, "%s\n", output)
+		return nil
+	}
+
+	fmt.Fprint(iostreams.G(ctx).Out, output)
+
+	if !opts.until.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, opts.until)
+		defer cancel()
+	}
+
+	return opts.follow(ctx, client, args[0], buffered)
+}
+
+// follow polls the console output of the instance until the context is
+// cancelled, printing any output which was not yet printed.  Failures to
+// retrieve the console output are retried with an exponential backoff.
+func (opts *LogOptions) follow(ctx context.Context, client kcinstances.InstancesService, instance, printed string) error {
+	attempt := 0
+	delay := opts.Interval
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+
+		output, err := opts.console(ctx, client, instance)
+		if ctx.Err() != nil {
+			return nil
+		} else if err != nil {
+			delay = utils.Backoff(attempt)
+			attempt++
+
+			log.G(ctx).
+				WithError(err).
+				Warnf("lost connection to instance, reconnecting in %s", delay.Round(time.Millisecond))
+			continue
+		}
+
+		if attempt > 0 {
+			log.G(ctx).Info("reconnected to instance")
+		}
+
+		attempt = 0
+		delay = opts.Interval
+
+		fmt.Fprint(iostreams.G(ctx).Out, utils.UnseenOutput(printed, output))
+		printed = output
+	}
+}
+
+// console returns the decoded console output of the instance.
+func (opts *LogOptions) console(ctx context.Context, client kcinstances.InstancesService, instance string) (string, error) {
 	var resp *kcinstances.ConsoleResponseItem
+	var err error
 
-	if utils.IsUUID(args[0]) {
-		resp, err = client.WithMetro(opts.metro).ConsoleByUUID(ctx, args[0], opts.Tail, true)
+	if utils.IsUUID(instance) {
+		resp, err = client.WithMetro(opts.metro).ConsoleByUUID(ctx, instance, opts.Tail, true)
 	} else {
-		resp, err = client.WithMetro(opts.metro).ConsoleByName(ctx, args[0], opts.Tail, true)
+		resp, err = client.WithMetro(opts.metro).ConsoleByName(ctx, instance, opts.Tail, true)
 	}
 	if err != nil {
-		return fmt.Errorf("could not retrieve logs: %w", err)
+		return "", fmt.Errorf("could not retrieve logs: %w", err)
 	}
 
 	output, err := base64.StdEncoding.DecodeString(resp.Output)
 	if err != nil {
-		return fmt.Errorf("decoding base64 console output: %w", err)
+		return "", fmt.Errorf("decoding base64 console output: %w", err)
 	}
 
-	fmt.Fprintf(iostreams.G(ctx).Out, "%s\n", output)
+	return string(output), nil
+}
+
+// createdAt returns the time at which the instance was created.
+func (opts *LogOptions) createdAt(ctx context.Context, client kcinstances.InstancesService, instance string) (time.Time, error) {
+	var instances []kcinstances.GetResponseItem
+	var err error
+
+	if utils.IsUUID(instance) {
+		instances, err = client.WithMetro(opts.metro).GetByUUIDs(ctx, instance)
+	} else {
+		instances, err = client.WithMetro(opts.metro).GetByNames(ctx, instance)
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("could not get instance %s: %w", instance, err)
+	}
+	if len(instances) != 1 {
+		return time.Time{}, fmt.Errorf("could not get instance %s", instance)
+	}
+
+	created, err := time.Parse(time.RFC3339, instances[0].CreatedAt)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("could not parse creation time of instance %s: %w", instance, err)
+	}
 
-	return nil
+	return created, nil
 }
@@ -8,37 +8,54 @@ package remove
 import (
 	"context"
 	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/spf13/cobra"
 
 	kraftcloud "sdk.kraft.cloud"
+	kcinstances "sdk.kraft.cloud/instances"
 
 	"kraftkit.sh/cmdfactory"
 	"kraftkit.sh/config"
 	"kraftkit.sh/internal/cli/kraft/cloud/utils"
+	"kraftkit.sh/iostreams"
 	"kraftkit.sh/log"
+	"kraftkit.sh/tui/confirm"
 )
 
 type RemoveOptions struct {
-	Output string `long:"output" short:"o" usage:"Set output format. Options: table,yaml,json,list" default:"table"`
-	All    bool   `long:"all" usage:"Remove all instances"`
+	Output    string        `long:"output" short:"o" usage:"Set output format. Options: table,yaml,json,list,go-template=TEMPLATE" default:"table"`
+	All       bool          `long:"all" usage:"Remove all instances"`
+	Strict    bool          `long:"strict" usage:"Fail if a name pattern does not match any instance"`
+	DryRun    bool          `long:"dry-run" usage:"Print the instances which would be removed without removing them"`
+	FailFast  bool          `local:"true" long:"fail-fast" usage:"Stop at the first instance which cannot be removed; use --fail-fast=false to attempt all of them"`
+	Yes       bool          `long:"yes" short:"y" usage:"Do not ask for confirmation before removing all instances"`
+	Parallel  int           `long:"parallel" short:"p" usage:"Number of instances to remove concurrently when using --all" default:"8"`
+	Retries   int           `long:"retries" usage:"Number of times to retry API calls failing with a transient error (env: KRAFTCLOUD_RETRIES)" default:"3"`
+	Timeout   time.Duration `local:"true" long:"timeout" usage:"Maximum time for removing all instance(s) (ms/s/m/h)"`
+	OlderThan time.Duration `long:"older-than" usage:"Only remove instances created longer ago than the given duration (ms/s/m/h)"`
+	Labels    []string      `long:"label" split:"false" usage:"Only remove instances with the given label in the form KEY=VALUE (can be used multiple times)"`
 
-	metro string
-	token string
+	labels map[string]string
+	metro  string
+	token  string
 }
 
 // Remove a KraftCloud instance.
 func Remove(ctx context.Context, opts *RemoveOptions, args ...string) error {
 	if opts == nil {
-		opts = &RemoveOptions{}
+		opts = &RemoveOptions{FailFast: true}
 	}
 
 	return opts.Run(ctx, args)
 }
 
 func NewCmd() *cobra.Command {
-	cmd, err := cmdfactory.New(&RemoveOptions{}, cobra.Command{
+	cmd, err := cmdfactory.New(&RemoveOptions{FailFast: true}, cobra.Command{
 		Short:   "Remove an instance",
 		Use:     "remove [FLAGS] [UUID|NAME [UUID|NAME]...]",
 		Aliases: []string{"del", "delete", "rm"},
@@ -53,11 +70,47 @@ func NewCmd() *cobra.Command {
 			# Remove multiple KraftCloud instances
 			$ kraft cloud instance remove my-instance-431342 my-instance-other-2313
 
+			# Remove all KraftCloud instances whose name matches a pattern
+			$ kraft cloud instance remove 'ci-pr-123-*'
+
 			# Remove all KraftCloud instances
 			$ kraft cloud instance remove --all
+
+			# Remove all KraftCloud instances without asking for confirmation
+			$ kraft cloud instance remove --all --yes
+
+			# Remove all KraftCloud instances without confirmation, 32 at a time
+			$ kraft cloud instance remove --all --yes --parallel 32
+
+			# Remove all KraftCloud instances, giving up after 5 minutes
+			$ kraft cloud instance remove --all --yes --timeout 5m
+
+			# Show which KraftCloud instances would be removed
+			$ kraft cloud instance remove --all --dry-run
+
+			# Remove all KraftCloud instances created more than a day ago
+			$ kraft cloud instance remove --all --older-than 24h
+
+			# Remove all KraftCloud instances labelled as part of the staging environment
+			$ kraft cloud instance remove --all --label env=staging
+
+			# Remove the KraftCloud instances whose UUIDs or names are read from stdin,
+			# taking the first field of each line
+			$ kraft cloud instance list -o list | grep ci- | kraft cloud instance remove -
+
+			# Attempt to remove all of the given KraftCloud instances, reporting failures at the end
+			$ kraft cloud instance remove --fail-fast=false my-instance-431342 my-instance-other-2313
 		`),
 		Long: heredoc.Doc(`
 			Remove a KraftCloud instance.
+
+			Names may contain shell-style glob patterns (*, ? and [...]) in which
+			case every instance whose name matches the pattern is removed.
+
+			By default, removing the instances given as arguments stops at the first
+			instance which cannot be removed.  With --fail-fast=false, all instances
+			are attempted instead and the number which succeeded and failed is
+			reported at the end, alongside the failing instances.
 		`),
 		Annotations: map[string]string{
 			cmdfactory.AnnotationHelpGroup: "kraftcloud-instance",
@@ -71,19 +124,44 @@ func NewCmd() *cobra.Command {
 }
 
 func (opts *RemoveOptions) Pre(cmd *cobra.Command, args []string) error {
+	if err := utils.ValidateOutput(opts.Output); err != nil {
+		return err
+	}
+
 	if !opts.All && len(args) == 0 {
 		return fmt.Errorf("either specify an instance name or UUID, or use the --all flag")
 	}
 
+	if opts.OlderThan < 0 {
+		return fmt.Errorf("--older-than cannot be negative")
+	}
+
+	if opts.All && slices.Contains(args, utils.StdinArg) {
+		return fmt.Errorf("cannot read instances from stdin and use the --all flag")
+	}
+
+	if slices.Contains(args, utils.StdinArg) && cmd.Flag("token").Value.String() == "-" {
+		return fmt.Errorf("cannot read both the instances and the token from stdin")
+	}
+
 	err := utils.PopulateMetroToken(cmd, &opts.metro, &opts.token)
 	if err != nil {
 		return fmt.Errorf("could not populate metro and token: %w", err)
 	}
 
-	return nil
+	opts.labels, err = utils.ParseLabels(opts.Labels)
+	if err != nil {
+		return err
+	}
+
+	return utils.PopulateRetries(cmd, &opts.Retries)
 }
 
 func (opts *RemoveOptions) Run(ctx context.Context, args []string) error {
+	return utils.ClassifyError(opts.run(ctx, args))
+}
+
+func (opts *RemoveOptions) run(ctx context.Context, args []string) error {
 	auth, err := config.GetKraftCloudAuthConfig(ctx, opts.token)
 	if err != nil {
 		return fmt.Errorf("could not retrieve credentials: %w", err)
@@ -91,66 +169,281 @@ func (opts *RemoveOptions) Run(ctx context.Context, args []string) error {
 
 	client := kraftcloud.NewInstancesClient(
 		kraftcloud.WithToken(config.GetKraftCloudTokenAuthConfig(*auth)),
+		kraftcloud.WithHTTPClient(utils.NewRetryHTTPClient(ctx, opts.Retries)),
 	)
 
 	if opts.All {
-		instListResp, err := client.WithMetro(opts.metro).List(ctx)
+		// Bound the requests which list the instances separately from their
+		// removal, such that the time spent at the prompt does not count.
+		listCtx, cancelList := utils.WithTimeout(ctx, opts.Timeout)
+		defer cancelList()
+
+		instListResp, err := client.WithMetro(opts.metro).List(listCtx)
 		if err != nil {
 			return fmt.Errorf("could not list instances: %w", err)
 		}
 
-		log.G(ctx).Infof("Removing %d instance(s)", len(instListResp))
-
 		uuids := make([]string, 0, len(instListResp))
 		for _, instItem := range instListResp {
 			uuids = append(uuids, instItem.UUID)
 		}
 
-		if _, err := client.WithMetro(opts.metro).DeleteByUUIDs(ctx, uuids...); err != nil {
-			return fmt.Errorf("removing %d instance(s): %w", len(instListResp), err)
+		if opts.OlderThan > 0 || len(opts.labels) > 0 {
+			uuids, err = opts.filter(listCtx, client, uuids)
+			if err != nil {
+				return err
+			}
+		}
+
+		if opts.DryRun {
+			return opts.printDryRun(ctx, client, uuids)
+		}
+
+		if len(uuids) == 0 {
+			return nil
 		}
+
+		if !opts.Yes && !config.G[config.KraftKit](ctx).NoPrompt && iostreams.G(ctx).IsStdinTTY() {
+			proceed, err := confirm.NewConfirmWithDefault(
+				fmt.Sprintf("This will remove %d instance(s) in metro %s. Continue?", len(uuids), opts.metro),
+				false,
+			)
+			if err != nil {
+				return err
+			}
+
+			if !proceed {
+				return nil
+			}
+		}
+
+		log.G(ctx).Infof("Removing %d instance(s)", len(uuids))
+
+		removeCtx, cancel := utils.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+
+		return opts.removeInParallel(removeCtx, client, uuids)
+	}
+
+	args, err = utils.ExpandStdinArgs(iostreams.G(ctx).In, args)
+	if err != nil {
+		return err
+	}
+
+	args, err = opts.expandPatterns(ctx, client, args)
+	if err != nil {
+		return err
+	}
+
+	if (opts.OlderThan > 0 || len(opts.labels) > 0) && len(args) > 0 {
+		args, err = opts.filter(ctx, client, args)
+		if err != nil {
+			return err
+		}
+	}
+	if len(args) == 0 {
 		return nil
 	}
 
+	if opts.DryRun {
+		return opts.printDryRun(ctx, client, args)
+	}
+
 	log.G(ctx).Infof("Removing %d instance(s)", len(args))
 
-	allUUIDs := true
-	allNames := true
-	for _, arg := range args {
-		if utils.IsUUID(arg) {
-			allNames = false
-		} else {
-			allUUIDs = false
-		}
-		if !(allUUIDs || allNames) {
-			break
-		}
+	// Bound the requests which remove the instances, such that the removal of
+	// many instances cannot hang indefinitely.
+	removeCtx, cancel := utils.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
+	if !opts.FailFast {
+		removed, err := utils.ForEachContinue(removeCtx, args, func(ctx context.Context, arg string) error {
+			return opts.removeOne(ctx, client, arg)
+		})
+
+		return utils.TimeoutError(removeCtx, err, opts.Timeout, "removed", len(removed), len(args))
+	}
+
+	uuids, names, err := utils.ClassifyArgs(args)
+	if err != nil {
+		return err
 	}
 
 	switch {
-	case allUUIDs:
-		if _, err := client.WithMetro(opts.metro).DeleteByUUIDs(ctx, args...); err != nil {
-			return fmt.Errorf("removing %d instance(s): %w", len(args), err)
+	case len(names) == 0:
+		if _, err := client.WithMetro(opts.metro).DeleteByUUIDs(removeCtx, args...); err != nil {
+			err = fmt.Errorf("removing %d instance(s): %w", len(args), err)
+			return utils.TimeoutError(removeCtx, err, opts.Timeout, "removed", 0, len(args))
 		}
-	case allNames:
-		if _, err := client.WithMetro(opts.metro).DeleteByNames(ctx, args...); err != nil {
-			return fmt.Errorf("removing %d instance(s): %w", len(args), err)
+	case len(uuids) == 0:
+		if _, err := client.WithMetro(opts.metro).DeleteByNames(removeCtx, args...); err != nil {
+			err = fmt.Errorf("removing %d instance(s): %w", len(args), err)
+			return utils.TimeoutError(removeCtx, err, opts.Timeout, "removed", 0, len(args))
 		}
 	default:
-		for _, arg := range args {
-			log.G(ctx).Infof("Removing instance %s", arg)
-
-			if utils.IsUUID(arg) {
-				_, err = client.WithMetro(opts.metro).DeleteByUUIDs(ctx, arg)
-			} else {
-				_, err = client.WithMetro(opts.metro).DeleteByNames(ctx, arg)
+		for i, arg := range args {
+			if err := opts.removeOne(removeCtx, client, arg); err != nil {
+				return utils.TimeoutError(removeCtx, err, opts.Timeout, "removed", i, len(args))
 			}
+		}
+	}
 
+	return nil
+}
+
+// removeOne removes the instance identified either by the provided UUID or
+// name.
+func (opts *RemoveOptions) removeOne(ctx context.Context, client kcinstances.InstancesService, arg string) error {
+	log.G(ctx).Infof("Removing instance %s", arg)
+
+	var err error
+	if utils.IsUUID(arg) {
+		_, err = client.WithMetro(opts.metro).DeleteByUUIDs(ctx, arg)
+	} else {
+		_, err = client.WithMetro(opts.metro).DeleteByNames(ctx, arg)
+	}
+	if err != nil {
+		return fmt.Errorf("could not remove instance %s: %w", arg, err)
+	}
+
+	return nil
+}
+
+// removeInParallel removes each of the provided instances individually using
+// a pool of at most opts.Parallel workers, showing the progress made.  A
+// failure to remove one instance does not prevent the remaining instances from
+// being removed; all failures are instead collected and returned together.
+func (opts *RemoveOptions) removeInParallel(ctx context.Context, client kcinstances.InstancesService, uuids []string) error {
+	removed, err := utils.ForEachWithProgress(ctx, "Removing", opts.Parallel, uuids, func(ctx context.Context, uuid string) error {
+		if _, err := client.WithMetro(opts.metro).DeleteByUUIDs(ctx, uuid); err != nil {
+			return fmt.Errorf("could not remove instance %s: %w", uuid, err)
+		}
+
+		return nil
+	})
+
+	log.G(ctx).Infof("Removed %d of %d instance(s)", removed, len(uuids))
+
+	return utils.TimeoutError(ctx, err, opts.Timeout, "removed", removed, len(uuids))
+}
+
+// printDryRun prints the instances which would otherwise be removed.
+func (opts *RemoveOptions) printDryRun(ctx context.Context, client kcinstances.InstancesService, instances []string) error {
+	if len(instances) == 0 {
+		return nil
+	}
+
+	items, err := utils.GetInstances(ctx, client.WithMetro(opts.metro), instances...)
+	if err != nil {
+		return err
+	}
+
+	log.G(ctx).Infof("Would remove %d instance(s)", len(items))
+
+	return utils.PrintInstances(ctx, opts.Output, items...)
+}
+
+// filter returns the UUIDs of the provided instances which carry all labels
+// given with --label and, if set, were created longer ago than opts.OlderThan.
+// Instances whose creation time is unknown are never selected by the latter.
+func (opts *RemoveOptions) filter(ctx context.Context, client kcinstances.InstancesService, instances []string) ([]string, error) {
+	if len(instances) == 0 {
+		return nil, nil
+	}
+
+	items, err := utils.GetInstances(ctx, client.WithMetro(opts.metro), instances...)
+	if err != nil {
+		return nil, err
+	}
+
+	items = utils.FilterInstancesByLabels(items, opts.labels)
+
+	cutoff := time.Now().Add(-opts.OlderThan)
+	uuids := make([]string, 0, len(items))
+
+	for _, item := range items {
+		if opts.OlderThan == 0 {
+			uuids = append(uuids, item.UUID)
+			continue
+		}
+
+		createdAt, err := time.Parse(time.RFC3339, item.CreatedAt)
+		if err != nil {
+			log.G(ctx).
+				WithField("instance", item.Name).
+				Warn("skipping instance with unknown creation time")
+			continue
+		}
+
+		if createdAt.Before(cutoff) {
+			uuids = append(uuids, item.UUID)
+		}
+	}
+
+	log.G(ctx).Debugf("%d of %d instance(s) match the filters", len(uuids), len(instances))
+
+	return uuids, nil
+}
+
+// isPattern returns whether the provided argument should be treated as a glob
+// pattern as opposed to an exact name.
+func isPattern(arg string) bool {
+	return strings.ContainsAny(arg, "*?[")
+}
+
+// expandPatterns replaces each name argument which is a glob pattern with the
+// names of all instances matching it.  UUIDs and exact names are returned
+// unchanged.
+func (opts *RemoveOptions) expandPatterns(ctx context.Context, client kcinstances.InstancesService, args []string) ([]string, error) {
+	var instances []kcinstances.ListResponseItem
+	var listed bool
+
+	expanded := make([]string, 0, len(args))
+	seen := make(map[string]struct{}, len(args))
+
+	add := func(arg string) {
+		if _, ok := seen[arg]; ok {
+			return
+		}
+		seen[arg] = struct{}{}
+		expanded = append(expanded, arg)
+	}
+
+	for _, arg := range args {
+		if utils.IsUUID(arg) || !isPattern(arg) {
+			add(arg)
+			continue
+		}
+
+		if _, err := path.Match(arg, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern '%s': %w", arg, err)
+		}
+
+		if !listed {
+			var err error
+			instances, err = client.WithMetro(opts.metro).List(ctx)
 			if err != nil {
-				return fmt.Errorf("could not remove instance %s: %w", arg, err)
+				return nil, fmt.Errorf("could not list instances: %w", err)
 			}
+			listed = true
+		}
+
+		matched := 0
+		for _, instance := range instances {
+			if ok, _ := path.Match(arg, instance.Name); ok {
+				add(instance.Name)
+				matched++
+			}
+		}
+
+		if matched == 0 {
+			if opts.Strict {
+				return nil, fmt.Errorf("pattern '%s' did not match any instance", arg)
+			}
+
+			log.G(ctx).Warnf("pattern '%s' did not match any instance", arg)
 		}
 	}
 
-	return nil
+	return expanded, nil
 }
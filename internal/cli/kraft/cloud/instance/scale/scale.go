@@ -0,0 +1,259 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package scale
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+
+	kraftcloud "sdk.kraft.cloud"
+	kcinstances "sdk.kraft.cloud/instances"
+
+	"kraftkit.sh/cmdfactory"
+	"kraftkit.sh/config"
+	"kraftkit.sh/internal/cli/kraft/cloud/instance/create"
+	"kraftkit.sh/internal/cli/kraft/cloud/utils"
+	"kraftkit.sh/iostreams"
+	"kraftkit.sh/log"
+)
+
+type ScaleOptions struct {
+	Auth         *config.AuthConfig    `noattribute:"true"`
+	Client       kraftcloud.KraftCloud `noattribute:"true"`
+	DrainTimeout time.Duration         `local:"true" long:"drain-timeout" short:"d" usage:"Timeout for removed replicas to stop (ms/s/m/h)"`
+	Replicas     int                   `local:"true" long:"replicas" short:"R" usage:"Number of replicas to scale the service group of the instance to"`
+	Metro        string                `noattribute:"true"`
+	Token        string                `noattribute:"true"`
+}
+
+// Scale the service group of a KraftCloud instance to a number of replicas.
+func Scale(ctx context.Context, opts *ScaleOptions, args ...string) error {
+	if opts == nil {
+		opts = &ScaleOptions{}
+	}
+
+	return opts.Run(ctx, args)
+}
+
+func NewCmd() *cobra.Command {
+	cmd, err := cmdfactory.New(&ScaleOptions{}, cobra.Command{
+		Short: "Scale the replicas of an instance",
+		Use:   "scale [FLAGS] UUID|NAME --replicas N",
+		Args:  cobra.ExactArgs(1),
+		Example: heredoc.Doc(`
+			# Run 4 replicas of the instance my-instance-431342
+			$ kraft cloud instance scale my-instance-431342 --replicas 4
+
+			# Scale back down to the instance itself
+			$ kraft cloud instance scale my-instance-431342 --replicas 1
+		`),
+		Long: heredoc.Doc(`
+			Scale the replicas of an instance.
+
+			The replicas of an instance are the instances of its service group.  When
+			scaling up, new replicas are created and started from the image, memory,
+			arguments and environment of the provided instance.  When scaling down,
+			replicas which are not running are removed first, followed by the most
+			recently created ones.  The provided instance itself is never removed.
+		`),
+		Annotations: map[string]string{
+			cmdfactory.AnnotationHelpGroup: "kraftcloud-instance",
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+func (opts *ScaleOptions) Pre(cmd *cobra.Command, _ []string) error {
+	if !cmd.Flags().Changed("replicas") {
+		return fmt.Errorf("the number of replicas must be set via --replicas")
+	} else if opts.Replicas < 1 {
+		return fmt.Errorf("the number of replicas must be at least 1")
+	}
+
+	if opts.DrainTimeout < 0 {
+		return fmt.Errorf("drain timeout cannot be negative")
+	}
+
+	err := utils.PopulateMetroToken(cmd, &opts.Metro, &opts.Token)
+	if err != nil {
+		return fmt.Errorf("could not populate metro and token: %w", err)
+	}
+
+	return nil
+}
+
+func (opts *ScaleOptions) Run(ctx context.Context, args []string) error {
+	var err error
+
+	if opts.Auth == nil {
+		opts.Auth, err = config.GetKraftCloudAuthConfig(ctx, opts.Token)
+		if err != nil {
+			return fmt.Errorf("could not retrieve credentials: %w", err)
+		}
+	}
+	if opts.Client == nil {
+		opts.Client = kraftcloud.NewClient(
+			kraftcloud.WithToken(config.GetKraftCloudTokenAuthConfig(*opts.Auth)),
+			kraftcloud.WithHTTPClient(utils.NewHTTPClient(ctx)),
+		)
+	}
+
+	client := opts.Client.Instances().WithMetro(opts.Metro)
+
+	instances, err := utils.GetInstances(ctx, client, args[0])
+	if err != nil {
+		return utils.ClassifyError(err)
+	} else if len(instances) == 0 {
+		return fmt.Errorf("could not find instance '%s'", args[0])
+	}
+
+	inst := instances[0]
+	if inst.ServiceGroup == nil || inst.ServiceGroup.UUID == "" {
+		return fmt.Errorf("instance '%s' is not part of a service group and cannot be scaled", inst.Name)
+	}
+
+	replicas, err := opts.replicas(ctx, inst.ServiceGroup.UUID)
+	if err != nil {
+		return utils.ClassifyError(err)
+	}
+
+	current := len(replicas)
+	delta := opts.Replicas - current
+
+	switch {
+	case delta > 0:
+		err = opts.scaleUp(ctx, inst, delta)
+	case delta < 0:
+		err = opts.scaleDown(ctx, inst, replicas, -delta)
+	default:
+		log.G(ctx).
+			WithField("service", inst.ServiceGroup.Name).
+			Infof("already running %d replica(s)", current)
+	}
+	if err != nil {
+		return utils.ClassifyError(err)
+	}
+
+	fmt.Fprintf(iostreams.G(ctx).Out, "%s: %d -> %d replica(s) (%+d)\n", inst.ServiceGroup.Name, current, opts.Replicas, delta)
+
+	return nil
+}
+
+// replicas returns the instances of the provided service group.
+func (opts *ScaleOptions) replicas(ctx context.Context, sgUUID string) ([]kcinstances.GetResponseItem, error) {
+	sg, err := opts.Client.Services().WithMetro(opts.Metro).GetByUUID(ctx, sgUUID)
+	if err != nil {
+		return nil, fmt.Errorf("could not get service group %s: %w", sgUUID, err)
+	}
+
+	if len(sg.Instances) == 0 {
+		return nil, nil
+	}
+
+	replicas, err := opts.Client.Instances().WithMetro(opts.Metro).GetByUUIDs(ctx, sg.Instances...)
+	if err != nil {
+		return nil, fmt.Errorf("getting details of %d instance(s): %w", len(sg.Instances), err)
+	}
+
+	return replicas, nil
+}
+
+// scaleUp creates and starts the provided number of replicas of the provided
+// instance in its service group.
+func (opts *ScaleOptions) scaleUp(ctx context.Context, inst kcinstances.GetResponseItem, n int) error {
+	// Volumes can only be attached to a single instance.
+	if len(inst.Volumes) > 0 {
+		return fmt.Errorf("instance '%s' has volumes attached and cannot be replicated", inst.Name)
+	}
+
+	keys := make([]string, 0, len(inst.Env))
+	for key := range inst.Env {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	env := make([]string, 0, len(keys))
+	for _, key := range keys {
+		env = append(env, key+"="+inst.Env[key])
+	}
+
+	for i := 0; i < n; i++ {
+		replica, _, err := create.Create(ctx, &create.CreateOptions{
+			Auth:                   opts.Auth,
+			Client:                 opts.Client,
+			Env:                    env,
+			Image:                  inst.Image,
+			Memory:                 inst.MemoryMB,
+			Metro:                  opts.Metro,
+			ServiceGroupNameOrUUID: inst.ServiceGroup.UUID,
+			Start:                  true,
+		}, inst.Args...)
+		if err != nil {
+			return fmt.Errorf("could not create replica %d of %d: %w", i+1, n, err)
+		}
+
+		log.G(ctx).
+			WithField("uuid", replica.UUID).
+			Infof("created replica %s", replica.Name)
+	}
+
+	return nil
+}
+
+// scaleDown stops and removes the provided number of replicas, other than the
+// provided instance itself.  Replicas which are not running are removed first,
+// followed by the most recently created ones.
+func (opts *ScaleOptions) scaleDown(ctx context.Context, inst kcinstances.GetResponseItem, replicas []kcinstances.GetResponseItem, n int) error {
+	replicas = slices.DeleteFunc(replicas, func(replica kcinstances.GetResponseItem) bool {
+		return replica.UUID == inst.UUID
+	})
+
+	slices.SortStableFunc(replicas, func(a, b kcinstances.GetResponseItem) int {
+		if aRunning, bRunning := a.State == "running", b.State == "running"; aRunning != bRunning {
+			if aRunning {
+				return 1
+			}
+			return -1
+		}
+
+		return -utils.InstanceSortFields["created"](a, b)
+	})
+
+	if n > len(replicas) {
+		n = len(replicas)
+	}
+
+	client := opts.Client.Instances().WithMetro(opts.Metro)
+
+	for _, replica := range replicas[:n] {
+		if replica.State == "running" {
+			if _, err := client.StopByUUIDs(ctx, int(opts.DrainTimeout.Milliseconds()), replica.UUID); err != nil {
+				return fmt.Errorf("could not stop replica '%s': %w", replica.Name, err)
+			}
+		}
+
+		if _, err := client.DeleteByUUIDs(ctx, replica.UUID); err != nil {
+			return fmt.Errorf("could not remove replica '%s': %w", replica.Name, err)
+		}
+
+		log.G(ctx).
+			WithField("uuid", replica.UUID).
+			Infof("removed replica %s", replica.Name)
+	}
+
+	return nil
+}
@@ -7,12 +7,14 @@ package start
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/spf13/cobra"
 
 	kraftcloud "sdk.kraft.cloud"
+	kcinstances "sdk.kraft.cloud/instances"
 
 	"kraftkit.sh/cmdfactory"
 	"kraftkit.sh/config"
@@ -21,8 +23,11 @@ import (
 )
 
 type StartOptions struct {
-	WaitTimeout time.Duration `local:"true" long:"wait-timeout" short:"w" usage:"Timeout to wait for the instance to start (ms/s/m/h)"`
-	Output      string        `long:"output" short:"o" usage:"Set output format. Options: table,yaml,json,list" default:"table"`
+	All          bool          `long:"all" usage:"Start all stopped instances"`
+	Wait         bool          `local:"true" long:"wait" usage:"Wait until the instance(s) are running"`
+	WaitTimeout  time.Duration `local:"true" long:"wait-timeout" short:"w" usage:"Timeout to wait for the instance to start (ms/s/m/h)" default:"60000000000"`
+	WaitInterval time.Duration `local:"true" long:"wait-interval" usage:"Interval between instance state checks while waiting (ms/s/m/h)" default:"500000000"`
+	Output       string        `long:"output" short:"o" usage:"Set output format. Options: table,yaml,json,list" default:"table"`
 
 	metro string
 	token string
@@ -41,7 +46,7 @@ func NewCmd() *cobra.Command {
 	cmd, err := cmdfactory.New(&StartOptions{}, cobra.Command{
 		Short:   "Start an instance",
 		Use:     "start [FLAGS] [UUID|NAME [UUID|NAME]...]",
-		Args:    cobra.MinimumNArgs(1),
+		Args:    cobra.ArbitraryArgs,
 		Aliases: []string{"str"},
 		Example: heredoc.Doc(`
 			# Start a KraftCloud instance by UUID
@@ -52,6 +57,12 @@ func NewCmd() *cobra.Command {
 
 			# Start multiple KraftCloud instances
 			$ kraft cloud instance start my-instance-431342 my-instance-other-2313
+
+			# Start all stopped KraftCloud instances
+			$ kraft cloud instance start --all
+
+			# Start a KraftCloud instance and wait up to 2 minutes for it to run
+			$ kraft cloud instance start --wait --wait-timeout 2m my-instance-431342
 		`),
 		Long: heredoc.Doc(`
 			Start an instance on KraftCloud from a stopped instance.
@@ -67,7 +78,15 @@ func NewCmd() *cobra.Command {
 	return cmd
 }
 
-func (opts *StartOptions) Pre(cmd *cobra.Command, _ []string) error {
+func (opts *StartOptions) Pre(cmd *cobra.Command, args []string) error {
+	if !opts.All && len(args) == 0 {
+		return fmt.Errorf("either specify an instance UUID or NAME, or the --all flag")
+	}
+
+	if opts.All && len(args) > 0 {
+		return fmt.Errorf("cannot specify instances and use the --all flag")
+	}
+
 	err := utils.PopulateMetroToken(cmd, &opts.metro, &opts.token)
 	if err != nil {
 		return fmt.Errorf("could not populate metro and token: %w", err)
@@ -77,6 +96,10 @@ func (opts *StartOptions) Pre(cmd *cobra.Command, _ []string) error {
 }
 
 func (opts *StartOptions) Run(ctx context.Context, args []string) error {
+	return utils.ClassifyError(opts.run(ctx, args))
+}
+
+func (opts *StartOptions) run(ctx context.Context, args []string) error {
 	auth, err := config.GetKraftCloudAuthConfig(ctx, opts.token)
 	if err != nil {
 		return fmt.Errorf("could not retrieve credentials: %w", err)
@@ -84,26 +107,159 @@ func (opts *StartOptions) Run(ctx context.Context, args []string) error {
 
 	client := kraftcloud.NewInstancesClient(
 		kraftcloud.WithToken(config.GetKraftCloudTokenAuthConfig(*auth)),
+		kraftcloud.WithHTTPClient(utils.NewHTTPClient(ctx)),
 	)
 
 	if opts.WaitTimeout < time.Millisecond {
 		return fmt.Errorf("wait timeout must be greater than 1ms")
 	}
 
+	if opts.All {
+		args, err = opts.stopped(ctx, client)
+		if err != nil {
+			return err
+		}
+
+		if len(args) == 0 {
+			log.G(ctx).Info("No stopped instances to start")
+			return nil
+		}
+	}
+
+	log.G(ctx).Infof("Starting %d instance(s)", len(args))
+
+	uuids, names, err := utils.ClassifyArgs(args)
+	if err != nil {
+		return err
+	}
+
 	timeout := int(opts.WaitTimeout.Milliseconds())
-	for _, arg := range args {
-		log.G(ctx).Infof("Starting %s", arg)
 
-		if utils.IsUUID(arg) {
-			_, err = client.WithMetro(opts.metro).StartByUUIDs(ctx, timeout, arg)
-		} else {
-			_, err = client.WithMetro(opts.metro).StartByNames(ctx, timeout, arg)
+	switch {
+	case len(names) == 0:
+		if _, err := client.WithMetro(opts.metro).StartByUUIDs(ctx, timeout, args...); err != nil {
+			return fmt.Errorf("starting %d instance(s): %w", len(args), err)
 		}
-		if err != nil {
-			log.G(ctx).WithError(err).Error("could not start instance")
-			continue
+	case len(uuids) == 0:
+		if _, err := client.WithMetro(opts.metro).StartByNames(ctx, timeout, args...); err != nil {
+			return fmt.Errorf("starting %d instance(s): %w", len(args), err)
 		}
+	default:
+		for _, arg := range args {
+			log.G(ctx).Infof("Starting %s", arg)
+
+			if utils.IsUUID(arg) {
+				_, err = client.WithMetro(opts.metro).StartByUUIDs(ctx, timeout, arg)
+			} else {
+				_, err = client.WithMetro(opts.metro).StartByNames(ctx, timeout, arg)
+			}
+			if err != nil {
+				log.G(ctx).WithError(err).Error("could not start instance")
+				continue
+			}
+		}
+	}
+
+	if opts.Wait {
+		return opts.waitUntilRunning(ctx, client, args)
 	}
 
 	return nil
 }
+
+// stopped returns the UUIDs of all instances in the metro which are stopped.
+func (opts *StartOptions) stopped(ctx context.Context, client kcinstances.InstancesService) ([]string, error) {
+	instListResp, err := client.WithMetro(opts.metro).List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not list instances: %w", err)
+	}
+
+	if len(instListResp) == 0 {
+		return nil, nil
+	}
+
+	uuids := make([]string, 0, len(instListResp))
+	for _, instItem := range instListResp {
+		uuids = append(uuids, instItem.UUID)
+	}
+
+	items, err := utils.GetInstances(ctx, client.WithMetro(opts.metro), uuids...)
+	if err != nil {
+		return nil, err
+	}
+
+	var stopped []string
+	for _, item := range items {
+		if item.State == "stopped" {
+			stopped = append(stopped, item.UUID)
+		}
+	}
+
+	return stopped, nil
+}
+
+// waitUntilRunning polls the state of the provided instances, identified
+// either by UUID or by name, until all of them have reached the running state
+// or opts.WaitTimeout elapses.
+func (opts *StartOptions) waitUntilRunning(ctx context.Context, client kcinstances.InstancesService, instances []string) error {
+	if opts.WaitInterval < time.Millisecond {
+		return fmt.Errorf("wait interval must be at least 1ms")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.WaitTimeout)
+	defer cancel()
+
+	pending := instances
+
+	for {
+		var err error
+		pending, err = opts.notRunning(ctx, client, pending)
+		if err != nil && ctx.Err() == nil {
+			return err
+		} else if err == nil && len(pending) == 0 {
+			log.G(ctx).Infof("Started %d instance(s)", len(instances))
+			return nil
+		}
+
+		log.G(ctx).
+			WithField("pending", len(pending)).
+			Debug("waiting for instances to run")
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out after %s waiting for %d instance(s) to run: %s",
+				opts.WaitTimeout,
+				len(pending),
+				strings.Join(pending, ", "),
+			)
+		case <-time.After(opts.WaitInterval):
+		}
+	}
+}
+
+// notRunning returns the subset of the provided instances which have not yet
+// reached the running state.  On error, the provided instances are returned
+// as-is.
+func (opts *StartOptions) notRunning(ctx context.Context, client kcinstances.InstancesService, instances []string) ([]string, error) {
+	items, err := utils.GetInstances(ctx, client.WithMetro(opts.metro), instances...)
+	if err != nil {
+		return instances, err
+	}
+
+	var pending []string
+	for _, instance := range instances {
+		running := false
+		for _, item := range items {
+			if (instance == item.UUID || instance == item.Name) && item.State == "running" {
+				running = true
+				break
+			}
+		}
+
+		if !running {
+			pending = append(pending, instance)
+		}
+	}
+
+	return pending, nil
+}
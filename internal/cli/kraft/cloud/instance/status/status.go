@@ -0,0 +1,244 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package status
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+
+	kraftcloud "sdk.kraft.cloud"
+	kcinstances "sdk.kraft.cloud/instances"
+
+	"kraftkit.sh/cmdfactory"
+	"kraftkit.sh/config"
+	"kraftkit.sh/internal/cli/kraft/cloud/utils"
+	"kraftkit.sh/iostreams"
+	"kraftkit.sh/log"
+)
+
+type StatusOptions struct {
+	Output       string        `local:"true" long:"output" short:"o" usage:"Print the whole instance instead of only its state. Options: table,yaml,json,list,go-template=TEMPLATE"`
+	WaitFor      string        `local:"true" long:"wait-for" usage:"Wait until the instance reaches the given state, e.g. running"`
+	WaitTimeout  time.Duration `local:"true" long:"wait-timeout" usage:"Maximum time to wait for the instance to reach the state given by --wait-for (ms/s/m/h)" default:"60000000000"`
+	WaitInterval time.Duration `local:"true" long:"wait-interval" usage:"Interval between instance state checks while waiting (ms/s/m/h)" default:"500000000"`
+
+	metro string
+	token string
+}
+
+// The exit codes with which the command terminates for each of the states of
+// an instance, or when it does not reach the state given by `--wait-for` in
+// time.  They do not overlap with the exit codes of errors, see
+// utils.ClassifyError.
+const (
+	ExitCodeRunning  = 0
+	ExitCodeStarting = 10
+	ExitCodeDraining = 11
+	ExitCodeStopping = 12
+	ExitCodeStopped  = 13
+	ExitCodeStandby  = 14
+	ExitCodeUnknown  = 15
+	ExitCodeTimeout  = 16
+)
+
+// stateExitCodes maps the known states of an instance to their exit codes.
+var stateExitCodes = map[string]int{
+	"running":  ExitCodeRunning,
+	"starting": ExitCodeStarting,
+	"draining": ExitCodeDraining,
+	"stopping": ExitCodeStopping,
+	"stopped":  ExitCodeStopped,
+	"standby":  ExitCodeStandby,
+}
+
+// Status prints the state of a KraftCloud instance.
+func Status(ctx context.Context, opts *StatusOptions, args ...string) error {
+	if opts == nil {
+		opts = &StatusOptions{}
+	}
+
+	return opts.Run(ctx, args)
+}
+
+func NewCmd() *cobra.Command {
+	cmd, err := cmdfactory.New(&StatusOptions{}, cobra.Command{
+		Short: "Print the state of an instance",
+		Use:   "status [FLAGS] UUID|NAME",
+		Args:  cobra.ExactArgs(1),
+		Example: heredoc.Doc(`
+			# Print the state of an instance
+			$ kraft cloud instance status my-instance-431342
+
+			# Wait up to 2 minutes for an instance to be running
+			$ kraft cloud instance status my-instance-431342 --wait-for running --wait-timeout 2m
+
+			# Print the whole instance as JSON once it is running
+			$ kraft cloud instance status my-instance-431342 --wait-for running -o json
+		`),
+		Long: heredoc.Doc(`
+			Print the state of an instance.
+
+			Only the state of the instance is printed, and the command exits with a
+			code which corresponds to it, such that it can be used in scripts:
+
+			  0   running
+			  10  starting
+			  11  draining
+			  12  stopping
+			  13  stopped
+			  14  standby
+			  15  any other state
+			  16  the state given by --wait-for was not reached in time
+
+			Failures to retrieve the state exit with the codes described by
+			'kraft cloud --help', e.g. 3 if the instance does not exist.
+
+			With --output, the whole instance is printed in the given format
+			instead, as by 'kraft cloud instance get', while the exit code still
+			corresponds to its state.
+		`),
+		Annotations: map[string]string{
+			cmdfactory.AnnotationHelpGroup: "kraftcloud-instance",
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+func (opts *StatusOptions) Pre(cmd *cobra.Command, _ []string) error {
+	if opts.Output != "" {
+		if err := utils.ValidateOutput(opts.Output); err != nil {
+			return err
+		}
+	}
+
+	if opts.WaitFor != "" {
+		if _, ok := stateExitCodes[opts.WaitFor]; !ok {
+			return fmt.Errorf("unknown state '%s'", opts.WaitFor)
+		}
+
+		if opts.WaitTimeout < time.Millisecond {
+			return fmt.Errorf("wait timeout must be at least 1ms")
+		}
+
+		if opts.WaitInterval < time.Millisecond {
+			return fmt.Errorf("wait interval must be at least 1ms")
+		}
+	}
+
+	err := utils.PopulateMetroToken(cmd, &opts.metro, &opts.token)
+	if err != nil {
+		return fmt.Errorf("could not populate metro and token: %w", err)
+	}
+
+	return nil
+}
+
+func (opts *StatusOptions) Run(ctx context.Context, args []string) error {
+	auth, err := config.GetKraftCloudAuthConfig(ctx, opts.token)
+	if err != nil {
+		return utils.ClassifyError(fmt.Errorf("could not retrieve credentials: %w", err))
+	}
+
+	client := kraftcloud.NewInstancesClient(
+		kraftcloud.WithToken(config.GetKraftCloudTokenAuthConfig(*auth)),
+		kraftcloud.WithHTTPClient(utils.NewHTTPClient(ctx)),
+	).WithMetro(opts.metro)
+
+	instance, err := opts.instance(ctx, client, args[0])
+	if err != nil {
+		return utils.ClassifyError(err)
+	}
+
+	var waitErr error
+	if opts.WaitFor != "" && string(instance.State) != opts.WaitFor {
+		instance, waitErr = opts.waitFor(ctx, client, instance)
+	}
+
+	// A failure to retrieve the state while waiting is reported with its own
+	// exit code, as when the state is retrieved for the first time, while the
+	// last known state is still printed on timeout.
+	var exitErr *cmdfactory.ExitError
+	if waitErr != nil && (!errors.As(waitErr, &exitErr) || exitErr.Code != ExitCodeTimeout) {
+		return waitErr
+	}
+
+	if opts.Output != "" {
+		if err := utils.PrintInstances(ctx, opts.Output, *instance); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintln(iostreams.G(ctx).Out, instance.State)
+	}
+
+	if waitErr != nil {
+		return waitErr
+	}
+
+	code, ok := stateExitCodes[string(instance.State)]
+	if !ok {
+		code = ExitCodeUnknown
+	}
+
+	if code != ExitCodeRunning {
+		return &cmdfactory.ExitError{Code: code, Err: cmdfactory.ErrSilent}
+	}
+
+	return nil
+}
+
+// instance returns the instance with the provided UUID or name.
+func (opts *StatusOptions) instance(ctx context.Context, client kcinstances.InstancesService, instance string) (*kcinstances.GetResponseItem, error) {
+	instances, err := utils.GetInstances(ctx, client, instance)
+	if err != nil {
+		return nil, err
+	} else if len(instances) == 0 {
+		return nil, fmt.Errorf("instance '%s' not found", instance)
+	}
+
+	return &instances[0], nil
+}
+
+// waitFor polls the provided instance until it reaches the state given by
+// opts.WaitFor, returning it as last retrieved.  If opts.WaitTimeout elapses
+// first, the returned error exits with ExitCodeTimeout.
+func (opts *StatusOptions) waitFor(ctx context.Context, client kcinstances.InstancesService, instance *kcinstances.GetResponseItem) (*kcinstances.GetResponseItem, error) {
+	ctx, cancel := context.WithTimeout(ctx, opts.WaitTimeout)
+	defer cancel()
+
+	for {
+		current, err := opts.instance(ctx, client, instance.UUID)
+		if err != nil && ctx.Err() == nil {
+			return instance, utils.ClassifyError(err)
+		} else if err == nil {
+			instance = current
+			if string(instance.State) == opts.WaitFor {
+				return instance, nil
+			}
+		}
+
+		log.G(ctx).
+			WithField("state", instance.State).
+			Debugf("waiting for instance to be %s", opts.WaitFor)
+
+		select {
+		case <-ctx.Done():
+			return instance, &cmdfactory.ExitError{
+				Code: ExitCodeTimeout,
+				Err:  fmt.Errorf("timed out after %s waiting for instance '%s' to be %s", opts.WaitTimeout, instance.Name, opts.WaitFor),
+			}
+		case <-time.After(opts.WaitInterval):
+		}
+	}
+}
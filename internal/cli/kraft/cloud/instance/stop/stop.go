@@ -7,39 +7,55 @@ package stop
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/spf13/cobra"
 
 	kraftcloud "sdk.kraft.cloud"
+	kcinstances "sdk.kraft.cloud/instances"
 
 	"kraftkit.sh/cmdfactory"
 	"kraftkit.sh/config"
 	"kraftkit.sh/internal/cli/kraft/cloud/utils"
+	"kraftkit.sh/iostreams"
 	"kraftkit.sh/log"
 )
 
 type StopOptions struct {
 	DrainTimeout time.Duration `local:"true" long:"drain-timeout" short:"d" usage:"Timeout for the instance to stop (ms/s/m/h)"`
-	Output       string        `long:"output" short:"o" usage:"Set output format. Options: table,yaml,json,list" default:"table"`
+	Output       string        `long:"output" short:"o" usage:"Set output format. Options: table,yaml,json,list,go-template=TEMPLATE" default:"table"`
 	All          bool          `long:"all" usage:"Stop all instances"`
+	DryRun       bool          `long:"dry-run" usage:"Print the instances which would be stopped without stopping them"`
+	FailFast     bool          `local:"true" long:"fail-fast" usage:"Stop at the first instance which cannot be stopped; use --fail-fast=false to attempt all of them"`
+	Labels       []string      `long:"label" split:"false" usage:"Only stop instances with the given label in the form KEY=VALUE (can be used multiple times)"`
+	Parallel     int           `long:"parallel" short:"p" usage:"Number of instances to stop concurrently when using --all" default:"8"`
+	Retries      int           `long:"retries" usage:"Number of times to retry API calls failing with a transient error (env: KRAFTCLOUD_RETRIES)" default:"3"`
+	Timeout      time.Duration `local:"true" long:"timeout" usage:"Maximum time for stopping all instance(s), excluding --wait (ms/s/m/h)"`
+	Wait         bool          `local:"true" long:"wait" short:"w" usage:"Wait until the instance(s) have stopped"`
+	WaitTimeout  time.Duration `local:"true" long:"wait-timeout" usage:"Maximum time to wait for the instance(s) to stop (ms/s/m/h)" default:"60000000000"`
+	WaitInterval time.Duration `local:"true" long:"wait-interval" usage:"Interval between instance state checks while waiting (ms/s/m/h)" default:"500000000"`
 	Metro        string        `noattribute:"true"`
 	Token        string        `noattribute:"true"`
+
+	labels map[string]string
 }
 
 // Stop a KraftCloud instance.
 func Stop(ctx context.Context, opts *StopOptions, args ...string) error {
 	if opts == nil {
-		opts = &StopOptions{}
+		opts = &StopOptions{FailFast: true}
 	}
 
 	return opts.Run(ctx, args)
 }
 
 func NewCmd() *cobra.Command {
-	cmd, err := cmdfactory.New(&StopOptions{}, cobra.Command{
+	cmd, err := cmdfactory.New(&StopOptions{FailFast: true}, cobra.Command{
 		Short:   "Stop an instance",
 		Use:     "stop [FLAGS] [UUID|NAME [UUID|NAME]...]",
 		Args:    cobra.ArbitraryArgs,
@@ -56,9 +72,36 @@ func NewCmd() *cobra.Command {
 
 			# Stop all KraftCloud instances
 			$ kraft cloud instance stop --all
+
+			# Stop all KraftCloud instances, 32 at a time
+			$ kraft cloud instance stop --all --parallel 32
+
+			# Stop all KraftCloud instances, giving up after 5 minutes
+			$ kraft cloud instance stop --all --timeout 5m
+
+			# Show which KraftCloud instances would be stopped
+			$ kraft cloud instance stop --all --dry-run
+
+			# Stop all KraftCloud instances labelled as part of the staging environment
+			$ kraft cloud instance stop --all --label env=staging
+
+			# Stop a KraftCloud instance and wait up to 2 minutes for it to stop
+			$ kraft cloud instance stop --wait --wait-timeout 2m my-instance-431342
+
+			# Stop the KraftCloud instances whose UUIDs or names are read from stdin,
+			# taking the first field of each line
+			$ kraft cloud instance list -o list | grep my-app | kraft cloud instance stop -
+
+			# Attempt to stop all of the given KraftCloud instances, reporting failures at the end
+			$ kraft cloud instance stop --fail-fast=false my-instance-431342 my-instance-other-2313
 		`),
 		Long: heredoc.Doc(`
 			Stop a KraftCloud instance.
+
+			By default, stopping the instances given as arguments stops at the first
+			instance which cannot be stopped.  With --fail-fast=false, all instances
+			are attempted instead and the number which succeeded and failed is
+			reported at the end, alongside the failing instances.
 		`),
 		Annotations: map[string]string{
 			cmdfactory.AnnotationHelpGroup: "kraftcloud-instance",
@@ -72,19 +115,40 @@ func NewCmd() *cobra.Command {
 }
 
 func (opts *StopOptions) Pre(cmd *cobra.Command, args []string) error {
+	if err := utils.ValidateOutput(opts.Output); err != nil {
+		return err
+	}
+
 	if !opts.All && len(args) == 0 {
 		return fmt.Errorf("either specify an instance UUID or --all flag")
 	}
 
+	if opts.All && slices.Contains(args, utils.StdinArg) {
+		return fmt.Errorf("cannot read instances from stdin and use the --all flag")
+	}
+
+	if slices.Contains(args, utils.StdinArg) && cmd.Flag("token").Value.String() == "-" {
+		return fmt.Errorf("cannot read both the instances and the token from stdin")
+	}
+
 	err := utils.PopulateMetroToken(cmd, &opts.Metro, &opts.Token)
 	if err != nil {
 		return fmt.Errorf("could not populate metro and token: %w", err)
 	}
 
-	return nil
+	opts.labels, err = utils.ParseLabels(opts.Labels)
+	if err != nil {
+		return err
+	}
+
+	return utils.PopulateRetries(cmd, &opts.Retries)
 }
 
 func (opts *StopOptions) Run(ctx context.Context, args []string) error {
+	return utils.ClassifyError(opts.run(ctx, args))
+}
+
+func (opts *StopOptions) run(ctx context.Context, args []string) error {
 	auth, err := config.GetKraftCloudAuthConfig(ctx, opts.Token)
 	if err != nil {
 		return fmt.Errorf("could not retrieve credentials: %w", err)
@@ -92,73 +156,259 @@ func (opts *StopOptions) Run(ctx context.Context, args []string) error {
 
 	client := kraftcloud.NewInstancesClient(
 		kraftcloud.WithToken(config.GetKraftCloudTokenAuthConfig(*auth)),
+		kraftcloud.WithHTTPClient(utils.NewRetryHTTPClient(ctx, opts.Retries)),
 	)
 
+	args, err = utils.ExpandStdinArgs(iostreams.G(ctx).In, args)
+	if err != nil {
+		return err
+	}
+
 	if opts.DrainTimeout < time.Millisecond {
 		return fmt.Errorf("drain timeout must be at least 1ms")
 	}
 
 	timeout := int(opts.DrainTimeout / time.Millisecond)
 
+	// Bound the requests which list and stop the instances, but not waiting for
+	// them to have stopped, which is bounded by --wait-timeout instead.
+	stopCtx, cancel := utils.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+
 	if opts.All {
-		instListResp, err := client.WithMetro(opts.Metro).List(ctx)
+		instListResp, err := client.WithMetro(opts.Metro).List(stopCtx)
 		if err != nil {
 			return fmt.Errorf("could not list instances: %w", err)
 		}
 
-		log.G(ctx).Infof("Stopping %d instance(s)", len(instListResp))
-
 		uuids := make([]string, 0, len(instListResp))
 		for _, instItem := range instListResp {
 			uuids = append(uuids, instItem.UUID)
 		}
 
-		if _, err = client.WithMetro(opts.Metro).StopByUUIDs(ctx, timeout, uuids...); err != nil {
-			log.G(ctx).Error("could not stop instance: %w", err)
+		if len(opts.labels) > 0 {
+			uuids, err = opts.filterByLabels(stopCtx, client, uuids)
+			if err != nil {
+				return err
+			}
+		}
+
+		if opts.DryRun {
+			return opts.printDryRun(ctx, client, uuids)
+		}
+
+		log.G(ctx).Infof("Stopping %d instance(s)", len(uuids))
+
+		if err := opts.stopInParallel(stopCtx, client, timeout, uuids); err != nil {
+			return err
+		}
+
+		if opts.Wait {
+			return opts.waitUntilStopped(ctx, client, uuids)
 		}
 
 		return nil
 	}
 
-	log.G(ctx).Infof("Stopping %d instance(s)", len(args))
+	if len(opts.labels) > 0 {
+		args, err = opts.filterByLabels(ctx, client, args)
+		if err != nil {
+			return err
+		}
 
-	allUUIDs := true
-	allNames := true
-	for _, arg := range args {
-		if utils.IsUUID(arg) {
-			allNames = false
-		} else {
-			allUUIDs = false
+		if len(args) == 0 {
+			log.G(ctx).Info("No instances match the provided labels")
+			return nil
 		}
-		if !(allUUIDs || allNames) {
-			break
+	}
+
+	if opts.DryRun {
+		return opts.printDryRun(ctx, client, args)
+	}
+
+	log.G(ctx).Infof("Stopping %d instance(s)", len(args))
+
+	if !opts.FailFast {
+		stopped, err := utils.ForEachContinue(stopCtx, args, func(ctx context.Context, arg string) error {
+			return opts.stopOne(ctx, client, timeout, arg)
+		})
+		err = utils.TimeoutError(stopCtx, err, opts.Timeout, "stopped", len(stopped), len(args))
+
+		if opts.Wait && len(stopped) > 0 {
+			err = errors.Join(err, opts.waitUntilStopped(ctx, client, stopped))
 		}
+
+		return err
+	}
+
+	uuids, names, err := utils.ClassifyArgs(args)
+	if err != nil {
+		return err
 	}
 
 	switch {
-	case allUUIDs:
-		if _, err := client.WithMetro(opts.Metro).StopByUUIDs(ctx, timeout, args...); err != nil {
-			return fmt.Errorf("stopping %d instance(s): %w", len(args), err)
+	case len(names) == 0:
+		if _, err := client.WithMetro(opts.Metro).StopByUUIDs(stopCtx, timeout, args...); err != nil {
+			err = fmt.Errorf("stopping %d instance(s): %w", len(args), err)
+			return utils.TimeoutError(stopCtx, err, opts.Timeout, "stopped", 0, len(args))
 		}
-	case allNames:
-		if _, err := client.WithMetro(opts.Metro).StopByNames(ctx, timeout, args...); err != nil {
-			return fmt.Errorf("stopping %d instance(s): %w", len(args), err)
+	case len(uuids) == 0:
+		if _, err := client.WithMetro(opts.Metro).StopByNames(stopCtx, timeout, args...); err != nil {
+			err = fmt.Errorf("stopping %d instance(s): %w", len(args), err)
+			return utils.TimeoutError(stopCtx, err, opts.Timeout, "stopped", 0, len(args))
 		}
 	default:
-		for _, arg := range args {
-			log.G(ctx).Infof("Stopping instance %s", arg)
+		for i, arg := range args {
+			if err := opts.stopOne(stopCtx, client, timeout, arg); err != nil {
+				return utils.TimeoutError(stopCtx, err, opts.Timeout, "stopped", i, len(args))
+			}
+		}
+	}
+
+	if opts.Wait {
+		return opts.waitUntilStopped(ctx, client, args)
+	}
+
+	return nil
+}
+
+// stopOne stops the instance identified either by the provided UUID or name.
+func (opts *StopOptions) stopOne(ctx context.Context, client kcinstances.InstancesService, timeout int, arg string) error {
+	log.G(ctx).Infof("Stopping instance %s", arg)
+
+	var err error
+	if utils.IsUUID(arg) {
+		_, err = client.WithMetro(opts.Metro).StopByUUIDs(ctx, timeout, arg)
+	} else {
+		_, err = client.WithMetro(opts.Metro).StopByNames(ctx, timeout, arg)
+	}
+	if err != nil {
+		return fmt.Errorf("could not stop instance %s: %w", arg, err)
+	}
+
+	return nil
+}
+
+// filterByLabels returns the UUIDs of the provided instances which carry all
+// labels given with --label.
+func (opts *StopOptions) filterByLabels(ctx context.Context, client kcinstances.InstancesService, instances []string) ([]string, error) {
+	if len(instances) == 0 {
+		return nil, nil
+	}
+
+	items, err := utils.GetInstances(ctx, client.WithMetro(opts.Metro), instances...)
+	if err != nil {
+		return nil, err
+	}
+
+	items = utils.FilterInstancesByLabels(items, opts.labels)
+
+	uuids := make([]string, 0, len(items))
+	for _, item := range items {
+		uuids = append(uuids, item.UUID)
+	}
+
+	log.G(ctx).Debugf("%d of %d instance(s) match the provided labels", len(uuids), len(instances))
+
+	return uuids, nil
+}
+
+// printDryRun prints the instances which would otherwise be stopped.
+func (opts *StopOptions) printDryRun(ctx context.Context, client kcinstances.InstancesService, instances []string) error {
+	if len(instances) == 0 {
+		return nil
+	}
+
+	items, err := utils.GetInstances(ctx, client.WithMetro(opts.Metro), instances...)
+	if err != nil {
+		return err
+	}
+
+	log.G(ctx).Infof("Would stop %d instance(s)", len(items))
 
-			if utils.IsUUID(arg) {
-				_, err = client.WithMetro(opts.Metro).StopByUUIDs(ctx, timeout, arg)
-			} else {
-				_, err = client.WithMetro(opts.Metro).StopByNames(ctx, timeout, arg)
+	return utils.PrintInstances(ctx, opts.Output, items...)
+}
+
+// stopInParallel stops each of the provided instances individually using a
+// pool of at most opts.Parallel workers, showing the progress made.  A failure
+// to stop one instance does not prevent the remaining instances from being
+// stopped; all failures are instead collected and returned together.
+func (opts *StopOptions) stopInParallel(ctx context.Context, client kcinstances.InstancesService, timeout int, uuids []string) error {
+	stopped, err := utils.ForEachWithProgress(ctx, "Stopping", opts.Parallel, uuids, func(ctx context.Context, uuid string) error {
+		if _, err := client.WithMetro(opts.Metro).StopByUUIDs(ctx, timeout, uuid); err != nil {
+			return fmt.Errorf("could not stop instance %s: %w", uuid, err)
+		}
+
+		return nil
+	})
+
+	log.G(ctx).Infof("Stopped %d of %d instance(s)", stopped, len(uuids))
+
+	return utils.TimeoutError(ctx, err, opts.Timeout, "stopped", stopped, len(uuids))
+}
+
+// waitUntilStopped polls the state of the provided instances, identified
+// either by UUID or by name, until all of them have reached the stopped state
+// or opts.WaitTimeout elapses.
+func (opts *StopOptions) waitUntilStopped(ctx context.Context, client kcinstances.InstancesService, instances []string) error {
+	if opts.WaitInterval < time.Millisecond {
+		return fmt.Errorf("wait interval must be at least 1ms")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.WaitTimeout)
+	defer cancel()
+
+	pending := instances
+
+	for {
+		var err error
+		pending, err = opts.notStopped(ctx, client, pending)
+		if err != nil && ctx.Err() == nil {
+			return err
+		} else if err == nil && len(pending) == 0 {
+			log.G(ctx).Infof("Stopped %d instance(s)", len(instances))
+			return nil
+		}
+
+		log.G(ctx).
+			WithField("pending", len(pending)).
+			Debug("waiting for instances to stop")
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out after %s waiting for %d instance(s) to stop: %s",
+				opts.WaitTimeout,
+				len(pending),
+				strings.Join(pending, ", "),
+			)
+		case <-time.After(opts.WaitInterval):
+		}
+	}
+}
+
+// notStopped returns the subset of the provided instances which have not yet
+// reached the stopped state.  Instances which can no longer be found are
+// considered stopped.  On error, the provided instances are returned as-is.
+func (opts *StopOptions) notStopped(ctx context.Context, client kcinstances.InstancesService, instances []string) ([]string, error) {
+	items, err := utils.GetInstances(ctx, client.WithMetro(opts.Metro), instances...)
+	if err != nil {
+		return instances, err
+	}
+
+	var pending []string
+	for _, instance := range instances {
+		for _, item := range items {
+			if instance != item.UUID && instance != item.Name {
+				continue
 			}
 
-			if err != nil {
-				return fmt.Errorf("could not stop instance %s: %w", arg, err)
+			if item.State != "stopped" {
+				pending = append(pending, instance)
 			}
+
+			break
 		}
 	}
 
-	return nil
+	return pending, nil
 }
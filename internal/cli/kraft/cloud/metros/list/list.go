@@ -16,14 +16,16 @@ import (
 	kraftcloud "sdk.kraft.cloud"
 
 	"kraftkit.sh/cmdfactory"
+	"kraftkit.sh/internal/cli/kraft/cloud/utils"
 	"kraftkit.sh/internal/tableprinter"
 	"kraftkit.sh/iostreams"
 	"kraftkit.sh/log"
 )
 
 type ListOptions struct {
-	Status bool   `long:"status" short:"s" usage:"Also display the status of the metros"`
-	Output string `long:"output" short:"o" usage:"Set output format. Options: table,yaml,json,list" default:"table"`
+	Columns []string `long:"columns" usage:"Only show the given comma-separated columns, in the given order"`
+	Status  bool     `long:"status" short:"s" usage:"Also display the status of the metros"`
+	Output  string   `long:"output" short:"o" usage:"Set output format. Options: table,yaml,json,jsonl,list,go-template=TEMPLATE" default:"table"`
 }
 
 func NewCmd() *cobra.Command {
@@ -57,11 +59,19 @@ func NewCmd() *cobra.Command {
 }
 
 func (opts *ListOptions) Pre(cmd *cobra.Command, _ []string) error {
-	return nil
+	if err := utils.ValidateOutput(opts.Output); err != nil {
+		return err
+	}
+
+	return utils.PopulateRPCTimeout(cmd)
 }
 
 func (opts *ListOptions) Run(ctx context.Context, args []string) error {
-	client := kraftcloud.NewMetrosClient()
+	ctx = tableprinter.WithColumnsInContext(ctx, opts.Columns)
+
+	client := kraftcloud.NewMetrosClient(
+		kraftcloud.WithHTTPClient(utils.NewHTTPClient(ctx)),
+	)
 
 	metros, err := client.List(ctx, opts.Status)
 	if err != nil {
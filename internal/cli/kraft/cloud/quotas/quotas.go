@@ -21,7 +21,7 @@ import (
 
 type QuotasOptions struct {
 	Limits bool   `long:"limits" short:"l" usage:"Show usage limits"`
-	Output string `local:"true" long:"output" short:"o" usage:"Set output format. Options: table,yaml,json,list" default:"table"`
+	Output string `local:"true" long:"output" short:"o" usage:"Set output format. Options: table,yaml,json,list,go-template=TEMPLATE" default:"table"`
 
 	metro string
 	token string
@@ -55,6 +55,10 @@ func NewCmd() *cobra.Command {
 }
 
 func (opts *QuotasOptions) Pre(cmd *cobra.Command, _ []string) error {
+	if err := utils.ValidateOutput(opts.Output); err != nil {
+		return err
+	}
+
 	err := utils.PopulateMetroToken(cmd, &opts.metro, &opts.token)
 	if err != nil {
 		return fmt.Errorf("could not populate metro and token: %w", err)
@@ -71,6 +75,7 @@ func (opts *QuotasOptions) Run(ctx context.Context, _ []string) error {
 
 	client := kraftcloud.NewUsersClient(
 		kraftcloud.WithToken(config.GetKraftCloudTokenAuthConfig(*auth)),
+		kraftcloud.WithHTTPClient(utils.NewHTTPClient(ctx)),
 	)
 
 	quotas, err := client.WithMetro(opts.metro).Quotas(ctx)
@@ -29,7 +29,7 @@ type GetOptions struct {
 	Auth   *config.AuthConfig    `noattribute:"true"`
 	Client kraftcloud.KraftCloud `noattribute:"true"`
 	Metro  string                `noattribute:"true"`
-	Output string                `long:"output" short:"o" usage:"Output format" default:"list"`
+	Output string                `long:"output" short:"o" usage:"Set output format. Options: table,json,list,go-template=TEMPLATE" default:"table"`
 	Policy string                `long:"policy" short:"p" usage:"Get a policy instead of a configuration"`
 	Token  string                `noattribute:"true"`
 }
@@ -66,6 +66,10 @@ func NewCmd() *cobra.Command {
 }
 
 func (opts *GetOptions) Pre(cmd *cobra.Command, args []string) error {
+	if err := utils.ValidateOutput(opts.Output); err != nil {
+		return err
+	}
+
 	if len(args) == 0 {
 		return fmt.Errorf("specify a service group NAME or UUID")
 	}
@@ -91,6 +95,7 @@ func (opts *GetOptions) Run(ctx context.Context, args []string) error {
 	if opts.Client == nil {
 		opts.Client = kraftcloud.NewClient(
 			kraftcloud.WithToken(config.GetKraftCloudTokenAuthConfig(*opts.Auth)),
+			kraftcloud.WithHTTPClient(utils.NewHTTPClient(ctx)),
 		)
 	}
 
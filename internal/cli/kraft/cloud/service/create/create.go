@@ -30,7 +30,7 @@ type CreateOptions struct {
 	SubDomain string                     `local:"true" long:"subdomain" short:"s" usage:"Set the subdomain to use when creating the service"`
 	Metro     string                     `noattribute:"true"`
 	Name      string                     `local:"true" long:"name" short:"n" usage:"Specify the name of the service"`
-	Output    string                     `local:"true" long:"output" short:"o" usage:"Set output format. Options: table,yaml,json,list" default:"table"`
+	Output    string                     `local:"true" long:"output" short:"o" usage:"Set output format. Options: table,yaml,json,list,go-template=TEMPLATE" default:"table"`
 	Token     string                     `noattribute:"true"`
 }
 
@@ -55,6 +55,7 @@ func Create(ctx context.Context, opts *CreateOptions, args ...string) (*kcservic
 	if opts.Client == nil {
 		opts.Client = kraftcloud.NewServicesClient(
 			kraftcloud.WithToken(config.GetKraftCloudTokenAuthConfig(*opts.Auth)),
+			kraftcloud.WithHTTPClient(utils.NewHTTPClient(ctx)),
 		)
 	}
 
@@ -196,6 +197,10 @@ func NewCmd() *cobra.Command {
 }
 
 func (opts *CreateOptions) Pre(cmd *cobra.Command, _ []string) error {
+	if err := utils.ValidateOutput(opts.Output); err != nil {
+		return err
+	}
+
 	err := utils.PopulateMetroToken(cmd, &opts.Metro, &opts.Token)
 	if err != nil {
 		return fmt.Errorf("could not populate metro and token: %w", err)
@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package utils
+
+import (
+	"sort"
+	"strings"
+
+	kcinstances "sdk.kraft.cloud/instances"
+)
+
+// AnnotationEnvPrefix is the prefix of the environmental variables in which
+// the annotations of an instance are stored.  Unlike labels, annotations are
+// free-form metadata, e.g. the commit or pipeline which produced a
+// deployment, and are not used to select instances.
+//
+// As KraftCloud has no dedicated field for arbitrary instance metadata, the
+// annotations are part of the environment of the guest and are thus visible
+// to the application.  They must therefore never carry secrets.
+const AnnotationEnvPrefix = "KRAFTKIT_ANNOTATION_"
+
+// ParseAnnotations parses the provided list of annotations in the form
+// KEY=VALUE.  Keys are subject to the same restrictions as those of labels.
+func ParseAnnotations(annotations []string) (map[string]string, error) {
+	return parseKeyValues("annotation", annotations)
+}
+
+// AnnotationsToEnv converts the provided annotations to environmental
+// variables in the form KEY=VALUE which store them on the instance.
+func AnnotationsToEnv(annotations map[string]string) []string {
+	env := make([]string, 0, len(annotations))
+	for key, value := range annotations {
+		env = append(env, AnnotationEnvPrefix+key+"="+value)
+	}
+
+	sort.Strings(env)
+
+	return env
+}
+
+// InstanceAnnotations returns the annotations which are stored on the provided
+// instance.
+func InstanceAnnotations(instance kcinstances.GetResponseItem) map[string]string {
+	annotations := map[string]string{}
+	for key, value := range instance.Env {
+		if strings.HasPrefix(key, AnnotationEnvPrefix) {
+			annotations[strings.TrimPrefix(key, AnnotationEnvPrefix)] = value
+		}
+	}
+
+	return annotations
+}
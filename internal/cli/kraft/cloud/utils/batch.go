@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"kraftkit.sh/log"
+)
+
+// ForEachContinue calls fn for each of the provided instances in order.  A
+// failure for one instance does not prevent the remaining instances from being
+// processed.  Once all have been processed, the number of instances which
+// succeeded and failed is logged and the instances which succeeded are
+// returned alongside an error summarising those which failed.
+func ForEachContinue(ctx context.Context, instances []string, fn func(context.Context, string) error) ([]string, error) {
+	var succeeded, failed []string
+	var errs []error
+
+	for _, instance := range instances {
+		if err := fn(ctx, instance); err != nil {
+			log.G(ctx).
+				WithField("instance", instance).
+				WithError(err).
+				Debug("failed")
+
+			failed = append(failed, instance)
+			errs = append(errs, err)
+			continue
+		}
+
+		succeeded = append(succeeded, instance)
+	}
+
+	log.G(ctx).Infof("%d succeeded, %d failed", len(succeeded), len(failed))
+
+	if len(failed) == 0 {
+		return succeeded, nil
+	}
+
+	return succeeded, fmt.Errorf("%d succeeded, %d failed (%s): %w",
+		len(succeeded),
+		len(failed),
+		strings.Join(failed, ", "),
+		errors.Join(errs...),
+	)
+}
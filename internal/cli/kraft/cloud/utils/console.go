@@ -0,0 +1,27 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package utils
+
+import "strings"
+
+// UnseenOutput returns the part of the current console output which follows
+// the previously seen output.  The console only retains its most recent
+// output, so the end of the previous output is matched against the start of
+// the current one.  If they do not overlap at all, e.g. because the instance
+// restarted, the current output is returned in full.
+func UnseenOutput(prev, cur string) string {
+	if strings.HasPrefix(cur, prev) {
+		return cur[len(prev):]
+	}
+
+	for i := 1; i < len(prev); i++ {
+		if strings.HasPrefix(cur, prev[i:]) {
+			return cur[len(prev)-i:]
+		}
+	}
+
+	return cur
+}
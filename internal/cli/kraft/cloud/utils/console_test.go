@@ -0,0 +1,32 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package utils
+
+import "testing"
+
+func TestUnseenOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		prev     string
+		cur      string
+		expected string
+	}{
+		{name: "nothing seen", cur: "booting\n", expected: "booting\n"},
+		{name: "appended", prev: "booting\n", cur: "booting\nready\n", expected: "ready\n"},
+		{name: "unchanged", prev: "booting\n", cur: "booting\n", expected: ""},
+		{name: "start truncated", prev: "booting\nready\n", cur: "ready\nserving\n", expected: "serving\n"},
+		{name: "no overlap", prev: "booting\n", cur: "restarted\n", expected: "restarted\n"},
+		{name: "empty", prev: "booting\n", cur: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := UnseenOutput(tt.prev, tt.cur); actual != tt.expected {
+				t.Errorf("UnseenOutput(%q, %q) = %q, expected %q", tt.prev, tt.cur, actual, tt.expected)
+			}
+		})
+	}
+}
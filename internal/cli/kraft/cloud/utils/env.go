@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/compose-spec/compose-go/template"
+)
+
+// ExpandEnv interpolates variables from the caller's environment in the values
+// of the provided KEY=VALUE pairs, using the same syntax as Compose files, e.g.
+// `${VAR}` or `${VAR:-default}`.  A literal dollar sign is written as `$$`.
+// Entries without a value are returned unchanged.
+func ExpandEnv(envs []string) ([]string, error) {
+	expanded := make([]string, 0, len(envs))
+
+	for _, env := range envs {
+		key, value, ok := strings.Cut(env, "=")
+		if !ok {
+			expanded = append(expanded, env)
+			continue
+		}
+
+		value, err := template.Substitute(value, os.LookupEnv)
+		if err != nil {
+			return nil, fmt.Errorf("could not expand environment variable '%s': %w", key, err)
+		}
+
+		expanded = append(expanded, key+"="+value)
+	}
+
+	return expanded, nil
+}
@@ -0,0 +1,198 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"kraftkit.sh/cmdfactory"
+)
+
+// Exit codes with which cloud commands terminate depending on the class of
+// error they fail with.  Any other error results in the exit code 1.
+const (
+	ExitCodeAuth     = 2
+	ExitCodeNotFound = 3
+	ExitCodeQuota    = 4
+	ExitCodeConflict = 5
+)
+
+// statusClasses maps the HTTP status codes with which the KraftCloud API
+// rejects requests to the exit code of their class.
+var statusClasses = map[int]int{
+	http.StatusUnauthorized:    ExitCodeAuth,
+	http.StatusForbidden:       ExitCodeAuth,
+	http.StatusNotFound:        ExitCodeNotFound,
+	http.StatusConflict:        ExitCodeConflict,
+	http.StatusPaymentRequired: ExitCodeQuota,
+	http.StatusTooManyRequests: ExitCodeQuota,
+}
+
+// errorClasses maps the exit code of each class of error to the fragments of
+// the messages of the errors which belong to it.  They only apply to errors
+// which carry no status code, e.g. those reported by the KraftCloud API for a
+// single item of a response, and are matched case-insensitively.
+var errorClasses = []struct {
+	code      int
+	fragments []string
+}{
+	{ExitCodeAuth, []string{
+		"could not retrieve credentials",
+		"unauthorized",
+		"unauthenticated",
+		"forbidden",
+		"invalid token",
+		"status 401",
+		"status 403",
+	}},
+	{ExitCodeQuota, []string{
+		"quota",
+		"limit exceeded",
+		"limit reached",
+		"status 402",
+		"status 429",
+	}},
+	{ExitCodeNotFound, []string{
+		"not found",
+		"does not exist",
+		"status 404",
+	}},
+	{ExitCodeConflict, []string{
+		"already exists",
+		"already in use",
+		"already taken",
+		"status 409",
+	}},
+}
+
+// quotedRegex matches the quoted parts of error messages, e.g. names, which
+// are ignored when classifying errors by their message.
+var quotedRegex = regexp.MustCompile(`'[^']*'|"[^"]*"`)
+
+// APIError is the error of a request to the KraftCloud API which was rejected
+// with the provided HTTP status code, see NewHTTPClient.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+// Error implements error.
+func (e *APIError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("status %d: %s", e.StatusCode, strings.ToLower(http.StatusText(e.StatusCode)))
+	}
+
+	return fmt.Sprintf("status %d: %s", e.StatusCode, e.Message)
+}
+
+// statusTransport turns the responses of the KraftCloud API with an error
+// status code into an *APIError, such that they can be classified by their
+// status code, see ClassifyError.
+type statusTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *statusTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.StatusCode < http.StatusBadRequest {
+		return resp, err
+	}
+
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+
+	return nil, &APIError{
+		StatusCode: resp.StatusCode,
+		Message:    apiErrorMessage(body),
+	}
+}
+
+// apiErrorMessage returns the message of the provided body of a response of
+// the KraftCloud API with an error status code.
+func apiErrorMessage(body []byte) string {
+	var resp struct {
+		Message string `json:"message"`
+		Errors  []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return strings.TrimSpace(string(body))
+	}
+
+	messages := []string{}
+	if resp.Message != "" {
+		messages = append(messages, resp.Message)
+	}
+
+	for _, item := range resp.Errors {
+		if item.Message != "" && !slices.Contains(messages, item.Message) {
+			messages = append(messages, item.Message)
+		}
+	}
+
+	return strings.Join(messages, ": ")
+}
+
+// ClassifyError wraps the provided error such that the program terminates
+// with the exit code of its class.  Errors of requests rejected by the
+// KraftCloud API are classified by their status code, whereas failures to
+// reach it, e.g. DNS errors, are never classified.  Errors which cannot be
+// classified, as well as those which already carry an exit code, are returned
+// unchanged.
+func ClassifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var exitErr *cmdfactory.ExitError
+	if errors.As(err, &exitErr) {
+		return err
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if code, ok := statusClasses[apiErr.StatusCode]; ok {
+			return &cmdfactory.ExitError{Code: code, Err: err}
+		}
+
+		return err
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return err
+	}
+
+	msg := strings.ToLower(quotedRegex.ReplaceAllString(err.Error(), ""))
+	for _, class := range errorClasses {
+		for _, fragment := range class.fragments {
+			if strings.Contains(msg, fragment) {
+				return &cmdfactory.ExitError{Code: class.code, Err: err}
+			}
+		}
+	}
+
+	return err
+}
+
+// IsNotFound returns whether the provided error reports that a resource does
+// not exist.
+func IsNotFound(err error) bool {
+	var exitErr *cmdfactory.ExitError
+	return errors.As(ClassifyError(err), &exitErr) && exitErr.Code == ExitCodeNotFound
+}
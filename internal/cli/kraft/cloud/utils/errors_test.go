@@ -0,0 +1,128 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"kraftkit.sh/cmdfactory"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{name: "nil"},
+		{name: "not found status", err: fmt.Errorf("getting details: %w", &APIError{StatusCode: 404}), expected: ExitCodeNotFound},
+		{name: "unauthorized status", err: &APIError{StatusCode: 401, Message: "invalid token"}, expected: ExitCodeAuth},
+		{name: "forbidden status", err: &APIError{StatusCode: 403}, expected: ExitCodeAuth},
+		{name: "conflict status", err: &APIError{StatusCode: 409, Message: "name already taken"}, expected: ExitCodeConflict},
+		{name: "rate limited status", err: &APIError{StatusCode: 429}, expected: ExitCodeQuota},
+		{name: "status wins over message", err: &APIError{StatusCode: 400, Message: "instance not found"}},
+		{name: "server error", err: &APIError{StatusCode: 500, Message: "quota service unavailable"}},
+		{
+			name: "dns error",
+			err: &url.Error{Op: "Get", URL: "https://api.fra0.kraft.cloud", Err: &net.DNSError{
+				Err:        "no such host",
+				Name:       "api.fra0.kraft.cloud",
+				IsNotFound: true,
+			}},
+		},
+		{name: "item not found", err: errors.New("instance 'my-app' not found"), expected: ExitCodeNotFound},
+		{name: "item already taken", err: errors.New("the name is already taken"), expected: ExitCodeConflict},
+		{name: "quoted name is ignored", err: errors.New("could not start 'quota-not-found-app': boot failed")},
+		{name: "double quoted name is ignored", err: errors.New(`volume "already-exists" is busy`)},
+		{name: "credentials", err: errors.New("could not retrieve credentials: no token"), expected: ExitCodeAuth},
+		{name: "unclassified", err: errors.New("boom")},
+		{name: "existing exit code", err: &cmdfactory.ExitError{Code: 42, Err: errors.New("not found")}, expected: 42},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ClassifyError(tt.err)
+
+			var exitErr *cmdfactory.ExitError
+			actual := 0
+			if errors.As(err, &exitErr) {
+				actual = exitErr.Code
+			}
+
+			if actual != tt.expected {
+				t.Errorf("ClassifyError(%v) exits with %d, expected %d", tt.err, actual, tt.expected)
+			}
+
+			if !errors.Is(err, tt.err) {
+				t.Errorf("ClassifyError(%v) = %v, which does not wrap the original error", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestStatusTransport(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		expected string
+	}{
+		{name: "success", status: 200, body: `{"status":"success"}`},
+		{name: "json message", status: 404, body: `{"status":"error","message":"instance not found"}`, expected: "status 404: instance not found"},
+		{name: "json item errors", status: 409, body: `{"message":"conflict","errors":[{"message":"name already taken"}]}`, expected: "status 409: conflict: name already taken"},
+		{name: "plain body", status: 403, body: "forbidden\n", expected: "status 403: forbidden"},
+		{name: "empty body", status: 401, expected: "status 401: unauthorized"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &statusTransport{
+				base: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+					return &http.Response{
+						StatusCode: tt.status,
+						Body:       io.NopCloser(strings.NewReader(tt.body)),
+					}, nil
+				}),
+			}
+
+			req, err := http.NewRequest(http.MethodGet, "https://api.fra0.kraft.cloud", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := transport.RoundTrip(req)
+			if tt.expected == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				resp.Body.Close()
+				return
+			}
+
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status {
+				t.Fatalf("RoundTrip() = %v, expected an *APIError with status %d", err, tt.status)
+			}
+
+			if err.Error() != tt.expected {
+				t.Errorf("RoundTrip() = %q, expected %q", err.Error(), tt.expected)
+			}
+		})
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
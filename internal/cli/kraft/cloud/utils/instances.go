@@ -0,0 +1,42 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package utils
+
+import (
+	"context"
+	"fmt"
+
+	kcinstances "sdk.kraft.cloud/instances"
+)
+
+// GetInstances retrieves the details of the provided instances, each of which
+// may be identified either by its UUID or by its name.
+func GetInstances(ctx context.Context, client kcinstances.InstancesService, args ...string) ([]kcinstances.GetResponseItem, error) {
+	uuids, names, err := ClassifyArgs(args)
+	if err != nil {
+		return nil, err
+	}
+
+	var instances []kcinstances.GetResponseItem
+
+	if len(uuids) > 0 {
+		resp, err := client.GetByUUIDs(ctx, uuids...)
+		if err != nil {
+			return nil, fmt.Errorf("getting details of %d instance(s): %w", len(uuids), err)
+		}
+		instances = append(instances, resp...)
+	}
+
+	if len(names) > 0 {
+		resp, err := client.GetByNames(ctx, names...)
+		if err != nil {
+			return nil, fmt.Errorf("getting details of %d instance(s): %w", len(names), err)
+		}
+		instances = append(instances, resp...)
+	}
+
+	return instances, nil
+}
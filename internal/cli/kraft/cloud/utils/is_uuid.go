@@ -5,10 +5,40 @@
 
 package utils
 
-import "github.com/google/uuid"
+import (
+	"fmt"
 
-// IsUUID returns whether the provided the string is a UUID or not.
+	"github.com/google/uuid"
+)
+
+// IsUUID returns whether the provided the string is a UUID in its canonical
+// form, e.g. `77d0316a-fbbe-488d-8618-5bf7a612477a`.  Other forms accepted by
+// uuid.Parse, such as 32 hexadecimal digits without dashes, are valid names
+// and are therefore not considered UUIDs.
 func IsUUID(arg string) bool {
+	if len(arg) != 36 {
+		return false
+	}
+
 	_, uuidErr := uuid.Parse(arg)
 	return uuidErr == nil
 }
+
+// ClassifyArgs splits the provided arguments, each identifying a resource
+// either by its UUID or by its name, into UUIDs and names whilst retaining
+// their order.  An error is returned if any of the arguments is empty.
+func ClassifyArgs(args []string) (uuids, names []string, err error) {
+	for i, arg := range args {
+		if arg == "" {
+			return nil, nil, fmt.Errorf("argument %d is empty: expected a UUID or a name", i+1)
+		}
+
+		if IsUUID(arg) {
+			uuids = append(uuids, arg)
+		} else {
+			names = append(names, arg)
+		}
+	}
+
+	return uuids, names, nil
+}
@@ -0,0 +1,97 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package utils
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestIsUUID(t *testing.T) {
+	tests := []struct {
+		arg      string
+		expected bool
+	}{
+		{"77d0316a-fbbe-488d-8618-5bf7a612477a", true},
+		{"77D0316A-FBBE-488D-8618-5BF7A612477A", true},
+		{"77d0316afbbe488d86185bf7a612477a", false},
+		{"{77d0316a-fbbe-488d-8618-5bf7a612477a}", false},
+		{"urn:uuid:77d0316a-fbbe-488d-8618-5bf7a612477a", false},
+		{"77d0316a-fbbe-488d-8618-5bf7a612477", false},
+		{"my-instance-431342", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			if actual := IsUUID(tt.arg); actual != tt.expected {
+				t.Errorf("IsUUID(%q) = %t, expected %t", tt.arg, actual, tt.expected)
+			}
+		})
+	}
+}
+
+func TestClassifyArgs(t *testing.T) {
+	tests := []struct {
+		name  string
+		args  []string
+		uuids []string
+		names []string
+		err   bool
+	}{
+		{
+			name:  "only uuids",
+			args:  []string{"77d0316a-fbbe-488d-8618-5bf7a612477a", "0e6ee2e4-fe22-4f3a-b2e1-2e4b3ef7c3d2"},
+			uuids: []string{"77d0316a-fbbe-488d-8618-5bf7a612477a", "0e6ee2e4-fe22-4f3a-b2e1-2e4b3ef7c3d2"},
+		},
+		{
+			name:  "only names",
+			args:  []string{"my-instance", "other-instance"},
+			names: []string{"my-instance", "other-instance"},
+		},
+		{
+			name:  "mixed batch retains order",
+			args:  []string{"my-instance", "77d0316a-fbbe-488d-8618-5bf7a612477a", "other-instance"},
+			uuids: []string{"77d0316a-fbbe-488d-8618-5bf7a612477a"},
+			names: []string{"my-instance", "other-instance"},
+		},
+		{
+			name:  "hex-ish name is not a uuid",
+			args:  []string{"77d0316afbbe488d86185bf7a612477a", "0e6ee2e4-fe22-4f3a-b2e1-2e4b3ef7c3d2"},
+			uuids: []string{"0e6ee2e4-fe22-4f3a-b2e1-2e4b3ef7c3d2"},
+			names: []string{"77d0316afbbe488d86185bf7a612477a"},
+		},
+		{
+			name: "empty argument",
+			args: []string{"my-instance", ""},
+			err:  true,
+		},
+		{
+			name: "no arguments",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uuids, names, err := ClassifyArgs(tt.args)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("expected error")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !slices.Equal(uuids, tt.uuids) {
+				t.Errorf("expected uuids %v, got %v", tt.uuids, uuids)
+			}
+			if !slices.Equal(names, tt.names) {
+				t.Errorf("expected names %v, got %v", tt.names, names)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	kcinstances "sdk.kraft.cloud/instances"
+)
+
+// LabelEnvPrefix is the prefix of the environmental variables in which the
+// labels of an instance are stored, since KraftCloud has no dedicated field
+// for arbitrary instance metadata.
+const LabelEnvPrefix = "KRAFTKIT_LABEL_"
+
+// labelKeyRegex restricts label keys to characters which are valid in the
+// name of an environmental variable, optionally namespaced with dots, e.g.
+// "git.sha".
+var labelKeyRegex = regexp.MustCompile(`^[A-Za-z0-9_]+(\.[A-Za-z0-9_]+)*$`)
+
+// ParseLabels parses the provided list of labels in the form KEY=VALUE.
+func ParseLabels(labels []string) (map[string]string, error) {
+	return parseKeyValues("label", labels)
+}
+
+// parseKeyValues parses the provided list of pairs in the form KEY=VALUE,
+// whose keys must be valid label keys.  The kind of the pairs, e.g. "label",
+// is used in error messages.
+func parseKeyValues(kind string, pairs []string) (map[string]string, error) {
+	parsed := make(map[string]string, len(pairs))
+
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid %s '%s': expected KEY=VALUE", kind, pair)
+		}
+
+		if !labelKeyRegex.MatchString(key) {
+			return nil, fmt.Errorf("invalid %s key '%s': only letters, digits, underscores and dots between them are allowed", kind, key)
+		}
+
+		parsed[key] = value
+	}
+
+	return parsed, nil
+}
+
+// LabelsToEnv converts the provided labels to environmental variables in the
+// form KEY=VALUE which store them on the instance.
+func LabelsToEnv(labels map[string]string) []string {
+	env := make([]string, 0, len(labels))
+	for key, value := range labels {
+		env = append(env, LabelEnvPrefix+key+"="+value)
+	}
+
+	sort.Strings(env)
+
+	return env
+}
+
+// InstanceLabels returns the labels which are stored on the provided instance.
+func InstanceLabels(instance kcinstances.GetResponseItem) map[string]string {
+	labels := map[string]string{}
+	for key, value := range instance.Env {
+		if strings.HasPrefix(key, LabelEnvPrefix) {
+			labels[strings.TrimPrefix(key, LabelEnvPrefix)] = value
+		}
+	}
+
+	return labels
+}
+
+// MatchLabels returns whether the provided instance carries all of the
+// provided labels.
+func MatchLabels(instance kcinstances.GetResponseItem, selector map[string]string) bool {
+	labels := InstanceLabels(instance)
+	for key, value := range selector {
+		if actual, ok := labels[key]; !ok || actual != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+// FilterInstancesByLabels returns the instances which carry all of the
+// provided labels.
+func FilterInstancesByLabels(instances []kcinstances.GetResponseItem, selector map[string]string) []kcinstances.GetResponseItem {
+	if len(selector) == 0 {
+		return instances
+	}
+
+	filtered := make([]kcinstances.GetResponseItem, 0, len(instances))
+	for _, instance := range instances {
+		if MatchLabels(instance, selector) {
+			filtered = append(filtered, instance)
+		}
+	}
+
+	return filtered
+}
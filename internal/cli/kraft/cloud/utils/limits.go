@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	kraftcloud "sdk.kraft.cloud"
+
+	"kraftkit.sh/config"
+	"kraftkit.sh/log"
+)
+
+// limitsCacheTTL is the duration for which the limits of a metro are re-used
+// before they are fetched again.
+const limitsCacheTTL = time.Hour
+
+// MetroLimits are the limits which the resources of an instance created in a
+// metro must abide by.
+type MetroLimits struct {
+	MinMemoryMB      int       `json:"min_memory_mb"`
+	MaxMemoryMB      int       `json:"max_memory_mb"`
+	MinAutoscaleSize int       `json:"min_autoscale_size"`
+	MaxAutoscaleSize int       `json:"max_autoscale_size"`
+	FetchedAt        time.Time `json:"fetched_at"`
+}
+
+var (
+	limitsMu    sync.Mutex
+	limitsCache = map[string]MetroLimits{}
+)
+
+// GetMetroLimits returns the limits of the provided metro for the provided
+// user.  Results are cached both in memory and in the KraftKit config
+// directory such that subsequent invocations do not query the API again.
+func GetMetroLimits(ctx context.Context, auth *config.AuthConfig, metro string) (*MetroLimits, error) {
+	key := auth.User + "@" + metro
+
+	limitsMu.Lock()
+	defer limitsMu.Unlock()
+
+	if limits, ok := limitsCache[key]; ok && time.Since(limits.FetchedAt) < limitsCacheTTL {
+		return &limits, nil
+	}
+
+	cacheFile := limitsCacheFile(ctx)
+	cached := readLimitsCache(ctx, cacheFile)
+
+	if limits, ok := cached[key]; ok && time.Since(limits.FetchedAt) < limitsCacheTTL {
+		limitsCache[key] = limits
+		return &limits, nil
+	}
+
+	client := kraftcloud.NewUsersClient(
+		kraftcloud.WithToken(config.GetKraftCloudTokenAuthConfig(*auth)),
+		kraftcloud.WithHTTPClient(NewHTTPClient(ctx)),
+	)
+
+	quotas, err := client.WithMetro(metro).Quotas(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not get quotas: %w", err)
+	}
+
+	limits := MetroLimits{
+		MinMemoryMB:      int(quotas.Limits.MinMemoryMb),
+		MaxMemoryMB:      int(quotas.Limits.MaxMemoryMb),
+		MinAutoscaleSize: int(quotas.Limits.MinAutoscaleSize),
+		MaxAutoscaleSize: int(quotas.Limits.MaxAutoscaleSize),
+		FetchedAt:        time.Now(),
+	}
+
+	limitsCache[key] = limits
+	cached[key] = limits
+
+	if err := writeLimitsCache(cacheFile, cached); err != nil {
+		log.G(ctx).Debugf("could not cache metro limits: %v", err)
+	}
+
+	return &limits, nil
+}
+
+// limitsCacheFile returns the path to the file in which metro limits are
+// cached.
+func limitsCacheFile(ctx context.Context) string {
+	return filepath.Join(config.G[config.KraftKit](ctx).Paths.Config, "kraftcloud-limits.json")
+}
+
+// readLimitsCache reads the cached metro limits from the provided file.  A
+// missing or malformed cache is treated as empty.
+func readLimitsCache(ctx context.Context, path string) map[string]MetroLimits {
+	cached := map[string]MetroLimits{}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return cached
+	}
+
+	if err := json.Unmarshal(raw, &cached); err != nil {
+		log.G(ctx).Debugf("ignoring malformed metro limits cache: %v", err)
+		return map[string]MetroLimits{}
+	}
+
+	return cached
+}
+
+// writeLimitsCache writes the provided metro limits to the provided file.
+func writeLimitsCache(path string, cached map[string]MetroLimits) error {
+	raw, err := json.Marshal(cached)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, raw, 0o644)
+}
+
+// CheckLimits validates the requested amount of memory (in MiB) and number of
+// replicas against the provided limits.  Zero values are not checked since
+// they defer to the defaults of the metro.
+func (limits *MetroLimits) CheckLimits(memory, replicas int) error {
+	if memory > 0 {
+		if limits.MaxMemoryMB > 0 && memory > limits.MaxMemoryMB {
+			return fmt.Errorf("requested %d MiB exceeds metro max of %d MiB", memory, limits.MaxMemoryMB)
+		} else if memory < limits.MinMemoryMB {
+			return fmt.Errorf("requested %d MiB is below metro min of %d MiB", memory, limits.MinMemoryMB)
+		}
+	}
+
+	if replicas > 0 && limits.MaxAutoscaleSize > 0 && replicas > limits.MaxAutoscaleSize {
+		return fmt.Errorf("requested %d replicas exceeds metro max of %d", replicas, limits.MaxAutoscaleSize)
+	}
+
+	return nil
+}
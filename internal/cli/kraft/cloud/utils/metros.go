@@ -0,0 +1,132 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	kraftcloud "sdk.kraft.cloud"
+
+	"kraftkit.sh/config"
+	"kraftkit.sh/log"
+)
+
+// metrosCacheTTL is the duration for which the list of known metros is
+// re-used before it is fetched again.
+const metrosCacheTTL = 24 * time.Hour
+
+// metrosTimeout bounds the request listing the known metros when no timeout of
+// remote procedure calls was set, as the list is only used opportunistically.
+const metrosTimeout = 10 * time.Second
+
+// metrosCache is the on-disk representation of the list of known metros.
+type metrosCache struct {
+	Codes     []string  `json:"codes"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// KnownMetros returns the codes of the metros which are available on
+// KraftCloud.  The list is cached in the KraftKit config directory such that
+// subsequent invocations do not query the API again.  An empty list is
+// reported as an error and never cached.
+func KnownMetros(ctx context.Context) ([]string, error) {
+	metros, _, err := knownMetros(ctx, false)
+	return metros, err
+}
+
+// knownMetros returns the codes of the known metros, see KnownMetros, and
+// whether they were read from the cache.  With refresh, the cache is bypassed
+// and updated with the list fetched from the API.
+func knownMetros(ctx context.Context, refresh bool) ([]string, bool, error) {
+	path := filepath.Join(config.G[config.KraftKit](ctx).Paths.Config, "kraftcloud-metros.json")
+
+	var cached metrosCache
+	if raw, err := os.ReadFile(path); err == nil && !refresh {
+		if err := json.Unmarshal(raw, &cached); err != nil {
+			log.G(ctx).Debugf("ignoring malformed metros cache: %v", err)
+		} else if len(cached.Codes) > 0 && time.Since(cached.FetchedAt) < metrosCacheTTL {
+			return cached.Codes, true, nil
+		}
+	}
+
+	client := NewHTTPClient(ctx)
+	if client.Timeout == 0 {
+		client.Timeout = metrosTimeout
+	}
+
+	metros, err := kraftcloud.NewMetrosClient(
+		kraftcloud.WithHTTPClient(client),
+	).List(ctx, false)
+	if err != nil {
+		return nil, false, fmt.Errorf("could not list metros: %w", err)
+	}
+
+	cached = metrosCache{FetchedAt: time.Now()}
+	for _, metro := range metros {
+		if metro.Code != "" {
+			cached.Codes = append(cached.Codes, metro.Code)
+		}
+	}
+
+	if len(cached.Codes) == 0 {
+		return nil, false, fmt.Errorf("no metros were listed")
+	}
+
+	if raw, err := json.Marshal(cached); err == nil {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err == nil {
+			err = os.WriteFile(path, raw, 0o644)
+		}
+		if err != nil {
+			log.G(ctx).Debugf("could not cache metros: %v", err)
+		}
+	}
+
+	return cached.Codes, false, nil
+}
+
+// ValidateMetro checks that the provided metro is one of the known metros and
+// otherwise suggests the closest one.  A metro missing from the cached list is
+// looked up again, since it may have been added since the list was cached.  If
+// the known metros cannot be determined, e.g. when offline, a warning is
+// logged and the metro is assumed to be valid.
+func ValidateMetro(ctx context.Context, metro string) error {
+	isKnown := func(metros []string) bool {
+		return slices.ContainsFunc(metros, func(known string) bool { return strings.EqualFold(known, metro) })
+	}
+
+	metros, fromCache, err := knownMetros(ctx, false)
+	if err == nil && fromCache && !isKnown(metros) {
+		log.G(ctx).
+			WithField("metro", metro).
+			Debug("metro is not cached, refreshing the list of metros")
+
+		metros, _, err = knownMetros(ctx, true)
+	}
+	if err != nil {
+		log.G(ctx).
+			WithField("metro", metro).
+			WithError(err).
+			Warn("could not validate metro")
+		return nil
+	}
+
+	if isKnown(metros) {
+		return nil
+	}
+
+	if suggestions := Suggest(metro, metros); len(suggestions) > 0 {
+		return fmt.Errorf("unknown metro '%s', did you mean '%s'?", metro, suggestions[0])
+	}
+
+	return fmt.Errorf("unknown metro '%s', available metros: %s", metro, strings.Join(metros, ", "))
+}
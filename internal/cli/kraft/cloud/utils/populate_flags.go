@@ -2,23 +2,209 @@ package utils
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
 	"kraftkit.sh/log"
 )
 
+// PopulateMetroToken sets the provided metro and token from the --metro and
+// --token flags (or their environmental variables), where the token may also
+// be read from a file or the standard input, see populateToken.  When unset,
+// the metro and token fall back to the ones last deployed with from the project
+// directory, which are recorded in its ProjectConfigFile and the
+// ProjectTokensFile, respectively.  Only a metro given via --metro
+// is validated, see ValidateMetro.  The timeout of remote procedure calls is
+// populated as well, see PopulateRPCTimeout.
 func PopulateMetroToken(cmd *cobra.Command, metro, token *string) error {
+	project := projectConfigOrEmpty(cmd)
+
+	if err := PopulateRPCTimeout(cmd); err != nil {
+		return err
+	}
+
 	*metro = cmd.Flag("metro").Value.String()
+	if *metro != "" {
+		if err := ValidateMetro(cmd.Context(), *metro); err != nil {
+			return err
+		}
+	}
+
+	if *metro == "" && config.G[config.KraftKit](cmd.Context()).KraftCloud.MetroFromFQDN {
+		*metro = metroFromFQDN(cmd)
+	}
+	if *metro == "" {
+		*metro = project.Metro
+	}
 	if *metro == "" {
 		return fmt.Errorf("kraftcloud metro is unset, try setting `KRAFTCLOUD_METRO`, or use the `--metro` flag")
 	}
 
 	log.G(cmd.Context()).WithField("metro", *metro).Debug("using")
 
+	return populateToken(cmd, token, projectToken(cmd))
+}
+
+// PopulateMetrosToken is like PopulateMetroToken but additionally accepts a
+// comma-separated list of metros via the --metro flag, e.g. `fra0,was1`, for
+// commands which can act on multiple metros at once.
+func PopulateMetrosToken(cmd *cobra.Command, metros *[]string, token *string) error {
+	if !strings.Contains(cmd.Flag("metro").Value.String(), ",") {
+		var metro string
+		if err := PopulateMetroToken(cmd, &metro, token); err != nil {
+			return err
+		}
+
+		*metros = []string{metro}
+
+		return nil
+	}
+
+	if err := PopulateRPCTimeout(cmd); err != nil {
+		return err
+	}
+
+	*metros = nil
+	for _, metro := range strings.Split(cmd.Flag("metro").Value.String(), ",") {
+		metro = strings.TrimSpace(metro)
+		if metro == "" || slices.Contains(*metros, metro) {
+			continue
+		}
+
+		if err := ValidateMetro(cmd.Context(), metro); err != nil {
+			return err
+		}
+
+		*metros = append(*metros, metro)
+	}
+
+	log.G(cmd.Context()).WithField("metros", strings.Join(*metros, ",")).Debug("using")
+
+	return populateToken(cmd, token, projectToken(cmd))
+}
+
+// populateToken sets the provided token, in order of precedence, from the
+// --token flag, the file provided via the --token-from-file flag, the standard
+// input when the --token flag is `-`, the KRAFTCLOUD_TOKEN environmental
+// variable and finally the provided token recorded for the project.
+func populateToken(cmd *cobra.Command, token *string, project string) error {
 	*token = cmd.Flag("token").Value.String()
+
+	// The flag is always marked as changed, as it is set from the environmental
+	// variable even when empty, such that only its value tells whether it was
+	// provided explicitly.
+	fromEnv := *token == os.Getenv("KRAFTCLOUD_TOKEN")
+
+	switch {
+	case *token != "" && *token != "-" && !fromEnv:
+		// Explicitly provided tokens take precedence.
+	case tokenFromFile(cmd) != "":
+		data, err := os.ReadFile(tokenFromFile(cmd))
+		if err != nil {
+			return fmt.Errorf("could not read token from file: %w", err)
+		}
+
+		*token = strings.TrimSpace(string(data))
+		if *token == "" {
+			return fmt.Errorf("token file '%s' is empty", tokenFromFile(cmd))
+		}
+	case *token == "-":
+		data, err := io.ReadAll(iostreams.G(cmd.Context()).In)
+		if err != nil {
+			return fmt.Errorf("could not read token from stdin: %w", err)
+		}
+
+		*token = strings.TrimSpace(string(data))
+		if *token == "" {
+			return fmt.Errorf("no token provided via stdin")
+		}
+	}
+
+	if *token == "" {
+		*token = project
+	}
 	if *token != "" {
 		log.G(cmd.Context()).WithField("token", *token).Debug("using")
 	}
 
 	return nil
 }
+
+// tokenFromFile returns the path provided via the --token-from-file flag, if
+// the command has one.
+func tokenFromFile(cmd *cobra.Command) string {
+	if flag := cmd.Flag("token-from-file"); flag != nil {
+		return flag.Value.String()
+	}
+
+	return ""
+}
+
+// projectConfigOrEmpty returns the project configuration of the command, or an
+// empty one if it cannot be read.
+func projectConfigOrEmpty(cmd *cobra.Command) *projectConfig {
+	project, err := loadProjectConfig(cmd)
+	if err != nil {
+		log.G(cmd.Context()).
+			WithError(err).
+			Debug("could not read project configuration")
+		return &projectConfig{}
+	}
+
+	return project
+}
+
+// metroFromFQDN returns the code of the metro which is part of the domain
+// provided via the --fqdn, --domain or --subdomain flags of the command, e.g.
+// `fra0` for `my-app.fra0.kraft.host`.  An empty string is returned if no
+// known metro could be inferred.
+func metroFromFQDN(cmd *cobra.Command) string {
+	var domains []string
+	for _, name := range []string{"fqdn", "domain", "subdomain"} {
+		flag := cmd.Flag(name)
+		if flag == nil {
+			continue
+		}
+
+		// The --fqdn and --domain flags can be repeated.
+		if values, ok := flag.Value.(pflag.SliceValue); ok {
+			domains = append(domains, values.GetSlice()...)
+		} else if flag.Value.String() != "" {
+			domains = append(domains, flag.Value.String())
+		}
+	}
+
+	if len(domains) == 0 {
+		return ""
+	}
+
+	metros, err := KnownMetros(cmd.Context())
+	if err != nil {
+		log.G(cmd.Context()).
+			WithError(err).
+			Debug("could not list metros to infer metro from fqdn")
+		return ""
+	}
+
+	for _, domain := range domains {
+		for _, label := range strings.Split(strings.TrimSuffix(domain, "."), ".") {
+			for _, metro := range metros {
+				if strings.EqualFold(label, metro) {
+					log.G(cmd.Context()).
+						WithField("fqdn", domain).
+						Infof("inferred metro %s", metro)
+					return metro
+				}
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package utils
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestPopulateToken(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(file, []byte("from-file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		env      string
+		flag     string
+		file     string
+		project  string
+		expected string
+	}{
+		{name: "file without flag", file: file, expected: "from-file"},
+		{name: "file over env", env: "from-env", file: file, expected: "from-file"},
+		{name: "flag over file", flag: "from-flag", file: file, expected: "from-flag"},
+		{name: "env", env: "from-env", expected: "from-env"},
+		{name: "project", project: "from-project", expected: "from-project"},
+		{name: "env over project", env: "from-env", project: "from-project", expected: "from-env"},
+		{name: "unset"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("KRAFTCLOUD_TOKEN", tt.env)
+
+			// Mirror cmdfactory, which sets the flag from the environmental
+			// variable unless it is provided explicitly.
+			value := tt.env
+			if tt.flag != "" {
+				value = tt.flag
+			}
+
+			cmd := &cobra.Command{}
+			cmd.SetContext(context.Background())
+			cmd.Flags().String("token", "", "")
+			cmd.Flags().String("token-from-file", "", "")
+
+			if err := cmd.Flags().Set("token", value); err != nil {
+				t.Fatal(err)
+			}
+			if err := cmd.Flags().Set("token-from-file", tt.file); err != nil {
+				t.Fatal(err)
+			}
+
+			var token string
+			if err := populateToken(cmd, &token, tt.project); err != nil {
+				t.Fatalf("populateToken: %v", err)
+			}
+
+			if token != tt.expected {
+				t.Errorf("populateToken() = %q, expected %q", token, tt.expected)
+			}
+		})
+	}
+}
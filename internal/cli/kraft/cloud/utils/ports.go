@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package utils
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	kcservices "sdk.kraft.cloud/services"
+)
+
+// ParsePort parses a port mapping in the form of
+// EXTERNAL[:INTERNAL][/HANDLER[+HANDLER...]] into a service definition.  Both
+// ports must lie within the valid port range and all handlers must be known to
+// KraftCloud.
+func ParsePort(spec string) (*kcservices.CreateRequestService, error) {
+	var service kcservices.CreateRequestService
+
+	ports, handlers, hasHandlers := strings.Cut(spec, "/")
+	if hasHandlers {
+		if len(handlers) == 0 || strings.ContainsRune(handlers, '/') {
+			return nil, fmt.Errorf("invalid port mapping '%s': expected format EXTERNAL:INTERNAL[/HANDLER[+HANDLER...]]", spec)
+		}
+
+		for _, handler := range strings.Split(handlers, "+") {
+			h := kcservices.Handler(handler)
+			if !slices.Contains(kcservices.Handlers(), h) {
+				return nil, fmt.Errorf("invalid port mapping '%s': unknown handler '%s' (choice of %v)", spec, handler, kcservices.Handlers())
+			}
+
+			service.Handlers = append(service.Handlers, h)
+		}
+	}
+
+	external, internal, hasInternal := strings.Cut(ports, ":")
+	if !hasInternal {
+		internal = external
+	}
+
+	var err error
+
+	service.Port, err = parsePortNumber(external)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port mapping '%s': external port: %w", spec, err)
+	}
+
+	dstPort, err := parsePortNumber(internal)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port mapping '%s': internal port: %w", spec, err)
+	}
+
+	service.DestinationPort = &dstPort
+
+	return &service, nil
+}
+
+// parsePortNumber parses a single port number and checks that it is within
+// the valid port range.
+func parsePortNumber(port string) (int, error) {
+	num, err := strconv.Atoi(port)
+	if err != nil {
+		return 0, fmt.Errorf("'%s' is not a number", port)
+	}
+
+	if num < 1 || num > 65535 {
+		return 0, fmt.Errorf("%d is out of range (1-65535)", num)
+	}
+
+	return num, nil
+}
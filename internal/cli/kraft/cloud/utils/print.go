@@ -9,11 +9,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/dustin/go-humanize"
+	"sigs.k8s.io/yaml"
+
 	"kraftkit.sh/config"
 	"kraftkit.sh/internal/fancymap"
 	"kraftkit.sh/internal/tableprinter"
@@ -66,9 +69,74 @@ var (
 // PrintInstances pretty-prints the provided set of instances or returns
 // an error if unable to send to stdout via the provided context.
 func PrintInstances(ctx context.Context, format string, instances ...kcinstances.GetResponseItem) error {
+	return printInstances(ctx, format, nil, nil, instances...)
+}
+
+// metroInstance is an instance alongside the metro it resides in and, when
+// inspected, the ports and domains of its service group.
+type metroInstance struct {
+	Metro   string   `json:"metro"`
+	Ports   []string `json:"ports,omitempty"`
+	Domains []string `json:"domains,omitempty"`
+	kcinstances.GetResponseItem
+}
+
+// PrintMetroInstances is like PrintInstances but additionally prints the metro
+// each of the instances resides in, where the metro at each index of metros
+// belongs to the instance at the same index.
+func PrintMetroInstances(ctx context.Context, format string, metros []string, instances ...kcinstances.GetResponseItem) error {
+	return PrintInspectedInstances(ctx, format, metros, nil, instances...)
+}
+
+// PrintInspectedInstances is like PrintMetroInstances but additionally prints
+// the ports and domains of the service group of each of the instances, where
+// the service group at each index of serviceGroups, if any, belongs to the
+// instance at the same index.
+func PrintInspectedInstances(ctx context.Context, format string, metros []string, serviceGroups []*kcservices.GetResponseItem, instances ...kcinstances.GetResponseItem) error {
+	if len(metros) != len(instances) {
+		return fmt.Errorf("expected %d metros but got %d", len(instances), len(metros))
+	} else if serviceGroups != nil && len(serviceGroups) != len(instances) {
+		return fmt.Errorf("expected %d service groups but got %d", len(instances), len(serviceGroups))
+	}
+
+	if format == "json" || format == "jsonl" || format == "yaml" || tableprinter.IsGoTemplate(format) {
+		items := make([]metroInstance, len(instances))
+		for i, instance := range instances {
+			items[i] = metroInstance{Metro: metros[i], GetResponseItem: instance}
+			if serviceGroups != nil {
+				items[i].Ports = ServiceGroupPorts(serviceGroups[i])
+				items[i].Domains = InstanceDomains(&instances[i], serviceGroups[i])
+			}
+		}
+
+		switch {
+		case format == "json":
+			return printJSON(ctx, items)
+		case format == "jsonl":
+			return printJSONLines(ctx, items)
+		case format == "yaml":
+			return printYAML(ctx, items)
+		}
+
+		return printGoTemplate(ctx, format, items)
+	}
+
+	return printInstances(ctx, format, metros, serviceGroups, instances...)
+}
+
+func printInstances(ctx context.Context, format string, metros []string, serviceGroups []*kcservices.GetResponseItem, instances ...kcinstances.GetResponseItem) error {
 	if format == "json" {
 		return printJSON(ctx, instances)
 	}
+	if format == "jsonl" {
+		return printJSONLines(ctx, instances)
+	}
+	if format == "yaml" {
+		return printYAML(ctx, instances)
+	}
+	if tableprinter.IsGoTemplate(format) {
+		return printGoTemplate(ctx, format, instances)
+	}
 
 	var err error
 
@@ -92,6 +160,9 @@ func PrintInstances(ctx context.Context, format string, instances ...kcinstances
 		table.AddField("UUID", cs.Bold)
 	}
 	table.AddField("NAME", cs.Bold)
+	if metros != nil {
+		table.AddField("METRO", cs.Bold)
+	}
 	table.AddField("FQDN", cs.Bold)
 	if format != "table" {
 		table.AddField("PRIVATE FQDN", cs.Bold)
@@ -104,8 +175,13 @@ func PrintInstances(ctx context.Context, format string, instances ...kcinstances
 	table.AddField("ARGS", cs.Bold)
 	if format != "table" {
 		table.AddField("ENV", cs.Bold)
+		table.AddField("ANNOTATIONS", cs.Bold)
 		table.AddField("VOLUMES", cs.Bold)
 		table.AddField("SERVICE GROUP", cs.Bold)
+		if serviceGroups != nil {
+			table.AddField("PORTS", cs.Bold)
+			table.AddField("DOMAINS", cs.Bold)
+		}
 	}
 	table.AddField("BOOT TIME", cs.Bold)
 	table.EndRow()
@@ -114,7 +190,7 @@ func PrintInstances(ctx context.Context, format string, instances ...kcinstances
 		instanceStateColor = instanceStateColorNil
 	}
 
-	for _, instance := range instances {
+	for i, instance := range instances {
 		var createdAt string
 
 		if len(instance.CreatedAt) > 0 {
@@ -134,6 +210,9 @@ func PrintInstances(ctx context.Context, format string, instances ...kcinstances
 		}
 
 		table.AddField(instance.Name, nil)
+		if metros != nil {
+			table.AddField(metros[i], nil)
+		}
 		table.AddField(instance.FQDN, nil)
 
 		if format != "table" {
@@ -154,6 +233,13 @@ func PrintInstances(ctx context.Context, format string, instances ...kcinstances
 			}
 			table.AddField(strings.Join(envs, ", "), nil)
 
+			annotations := []string{}
+			for k, v := range InstanceAnnotations(instance) {
+				annotations = append(annotations, fmt.Sprintf("%s=%s", k, v))
+			}
+			slices.Sort(annotations)
+			table.AddField(strings.Join(annotations, ", "), nil)
+
 			vols := make([]string, len(instance.Volumes))
 			for i, vol := range instance.Volumes {
 				vols[i] = fmt.Sprintf("%s:%s", vol.Name, vol.At)
@@ -167,6 +253,10 @@ func PrintInstances(ctx context.Context, format string, instances ...kcinstances
 			} else {
 				table.AddField("", nil)
 			}
+			if serviceGroups != nil {
+				table.AddField(strings.Join(ServiceGroupPorts(serviceGroups[i]), " "), nil)
+				table.AddField(strings.Join(InstanceDomains(&instances[i], serviceGroups[i]), " "), nil)
+			}
 		}
 
 		table.AddField(fmt.Sprintf("%.2f ms", float64(instance.BootTimeUs)/1000), nil)
@@ -180,9 +270,60 @@ func PrintInstances(ctx context.Context, format string, instances ...kcinstances
 // PrintVolumes pretty-prints the provided set of volumes or returns
 // an error if unable to send to stdout via the provided context.
 func PrintVolumes(ctx context.Context, format string, volumes ...kcvolumes.GetResponseItem) error {
+	return printVolumes(ctx, format, nil, volumes...)
+}
+
+// VolumeMount describes where a volume is mounted in the instance it is
+// attached to.
+type VolumeMount struct {
+	Instance string `json:"instance"`
+	At       string `json:"at"`
+	State    string `json:"state"`
+}
+
+// mountedVolume is a volume alongside where it is mounted.
+type mountedVolume struct {
+	Mounts []VolumeMount `json:"mounts"`
+	kcvolumes.GetResponseItem
+}
+
+// PrintVolumeMounts is like PrintVolumes but additionally prints where each of
+// the volumes is mounted and the state of the instances it is mounted in,
+// where the mounts at each index of mounts belong to the volume at the same
+// index.
+func PrintVolumeMounts(ctx context.Context, format string, mounts [][]VolumeMount, volumes ...kcvolumes.GetResponseItem) error {
+	if len(mounts) != len(volumes) {
+		return fmt.Errorf("expected mounts of %d volumes but got %d", len(volumes), len(mounts))
+	}
+
+	if format == "json" || format == "jsonl" || tableprinter.IsGoTemplate(format) {
+		items := make([]mountedVolume, len(volumes))
+		for i, volume := range volumes {
+			items[i] = mountedVolume{Mounts: mounts[i], GetResponseItem: volume}
+		}
+
+		if format == "json" {
+			return printJSON(ctx, items)
+		} else if format == "jsonl" {
+			return printJSONLines(ctx, items)
+		}
+
+		return printGoTemplate(ctx, format, items)
+	}
+
+	return printVolumes(ctx, format, mounts, volumes...)
+}
+
+func printVolumes(ctx context.Context, format string, mounts [][]VolumeMount, volumes ...kcvolumes.GetResponseItem) error {
 	if format == "json" {
 		return printJSON(ctx, volumes)
 	}
+	if format == "jsonl" {
+		return printJSONLines(ctx, volumes)
+	}
+	if tableprinter.IsGoTemplate(format) {
+		return printGoTemplate(ctx, format, volumes)
+	}
 
 	var err error
 
@@ -209,11 +350,15 @@ func PrintVolumes(ctx context.Context, format string, volumes ...kcvolumes.GetRe
 	table.AddField("CREATED AT", cs.Bold)
 	table.AddField("SIZE", cs.Bold)
 	table.AddField("ATTACHED TO", cs.Bold)
+	if mounts != nil {
+		table.AddField("MOUNTED AT", cs.Bold)
+		table.AddField("INSTANCE STATE", cs.Bold)
+	}
 	table.AddField("STATE", cs.Bold)
 	table.AddField("PERSISTENT", cs.Bold)
 	table.EndRow()
 
-	for _, volume := range volumes {
+	for i, volume := range volumes {
 		var createdAt string
 		if len(volume.CreatedAt) > 0 {
 			createdTime, err := time.Parse(time.RFC3339, volume.CreatedAt)
@@ -241,6 +386,18 @@ func PrintVolumes(ctx context.Context, format string, volumes ...kcvolumes.GetRe
 		}
 
 		table.AddField(strings.Join(attachedTo, ","), nil)
+
+		if mounts != nil {
+			var at, states []string
+			for _, mount := range mounts[i] {
+				at = append(at, mount.At)
+				states = append(states, mount.State)
+			}
+
+			table.AddField(strings.Join(at, ","), nil)
+			table.AddField(strings.Join(states, ","), nil)
+		}
+
 		table.AddField(string(volume.State), nil)
 		table.AddField(fmt.Sprintf("%t", volume.Persistent), nil)
 
@@ -256,6 +413,9 @@ func PrintAutoscaleConfiguration(ctx context.Context, format string, aconf kcaut
 	if format == "json" {
 		return printJSON(ctx, aconf)
 	}
+	if tableprinter.IsGoTemplate(format) {
+		return printGoTemplate(ctx, format, []kcautoscale.GetResponseItem{aconf})
+	}
 
 	var err error
 
@@ -357,6 +517,12 @@ func PrintServiceGroups(ctx context.Context, format string, serviceGroups ...kcs
 	if format == "json" {
 		return printJSON(ctx, serviceGroups)
 	}
+	if format == "jsonl" {
+		return printJSONLines(ctx, serviceGroups)
+	}
+	if tableprinter.IsGoTemplate(format) {
+		return printGoTemplate(ctx, format, serviceGroups)
+	}
 
 	var err error
 
@@ -395,17 +561,7 @@ func PrintServiceGroups(ctx context.Context, format string, serviceGroups ...kcs
 		table.AddField(sg.Name, nil)
 		table.AddField(sg.FQDN, nil)
 
-		var services []string
-		for _, service := range sg.Services {
-			var handlers []string
-			for _, handler := range service.Handlers {
-				handlers = append(handlers, string(handler))
-			}
-
-			services = append(services, fmt.Sprintf("%d:%d/%s", service.Port, service.DestinationPort, strings.Join(handlers, "+")))
-		}
-
-		table.AddField(strings.Join(services, " "), nil)
+		table.AddField(strings.Join(ServiceGroupPorts(&sg), " "), nil)
 		table.AddField(strings.Join(sg.Instances, " "), nil)
 
 		var createdAt string
@@ -436,6 +592,9 @@ func PrintQuotas(ctx context.Context, format string, quotas ...kcusers.QuotasRes
 	if format == "json" {
 		return printJSON(ctx, quotas)
 	}
+	if tableprinter.IsGoTemplate(format) {
+		return printGoTemplate(ctx, format, quotas)
+	}
 
 	var err error
 
@@ -496,6 +655,9 @@ func PrintLimits(ctx context.Context, format string, quotas ...kcusers.QuotasRes
 	if format == "json" {
 		return printJSON(ctx, quotas)
 	}
+	if tableprinter.IsGoTemplate(format) {
+		return printGoTemplate(ctx, format, quotas)
+	}
 
 	var err error
 
@@ -561,6 +723,12 @@ func PrintCertificates(ctx context.Context, format string, certs ...kccerts.GetR
 	if format == "json" {
 		return printJSON(ctx, certs)
 	}
+	if format == "jsonl" {
+		return printJSONLines(ctx, certs)
+	}
+	if tableprinter.IsGoTemplate(format) {
+		return printGoTemplate(ctx, format, certs)
+	}
 
 	var err error
 
@@ -669,12 +837,13 @@ func PrintCertificates(ctx context.Context, format string, certs ...kccerts.GetR
 // PrettyPrintInstance outputs a single instance and information about it.
 func PrettyPrintInstance(ctx context.Context, instance *kcinstances.GetResponseItem, serviceGroup *kcservices.GetResponseItem, autoStart bool) {
 	out := iostreams.G(ctx).Out
-	fqdn := instance.FQDN
+	domains := InstanceDomains(instance, serviceGroup)
 
-	if serviceGroup != nil && fqdn != "" {
+	https := false
+	if serviceGroup != nil {
 		for _, port := range serviceGroup.Services {
 			if port.Port == 443 {
-				fqdn = "https://" + fqdn
+				https = true
 				break
 			}
 		}
@@ -711,18 +880,24 @@ func PrettyPrintInstance(ctx context.Context, instance *kcinstances.GetResponseI
 		},
 	}
 
-	if len(fqdn) > 0 {
-		if strings.HasPrefix(fqdn, "https://") {
-			entries = append(entries, fancymap.FancyMapEntry{
-				Key:   "url",
-				Value: fqdn,
-			})
-		} else {
-			entries = append(entries, fancymap.FancyMapEntry{
-				Key:   "fqdn",
-				Value: fqdn,
-			})
+	if len(domains) > 0 {
+		key := "fqdn"
+		values := slices.Clone(domains)
+		if https {
+			key = "url"
+			for i, domain := range values {
+				values[i] = "https://" + domain
+			}
+		}
+
+		if len(values) > 1 {
+			key = "domains"
 		}
+
+		entries = append(entries, fancymap.FancyMapEntry{
+			Key:   key,
+			Value: strings.Join(values, ", "),
+		})
 	}
 
 	entries = append(entries, fancymap.FancyMapEntry{
@@ -778,6 +953,20 @@ func PrettyPrintInstance(ctx context.Context, instance *kcinstances.GetResponseI
 		}...)
 	}
 
+	if annotations := InstanceAnnotations(*instance); len(annotations) > 0 {
+		pairs := make([]string, 0, len(annotations))
+		for key, value := range annotations {
+			pairs = append(pairs, key+"="+value)
+		}
+
+		slices.Sort(pairs)
+
+		entries = append(entries, fancymap.FancyMapEntry{
+			Key:   "annotations",
+			Value: strings.Join(pairs, ", "),
+		})
+	}
+
 	fancymap.PrintFancyMap(
 		out,
 		title,
@@ -785,6 +974,17 @@ func PrettyPrintInstance(ctx context.Context, instance *kcinstances.GetResponseI
 		entries...,
 	)
 
+	if instance.State == "running" || instance.State == "starting" || instance.State == "standby" {
+		var urls []string
+		for _, domain := range domains {
+			urls = append(urls, ServiceURLs(domain, serviceGroup)...)
+		}
+
+		if len(urls) > 0 {
+			fmt.Fprintf(out, "\n  Your service is live at: %s\n\n", strings.Join(urls, ", "))
+		}
+	}
+
 	if instance.State != "running" && instance.State != "starting" && autoStart {
 		fmt.Fprintf(out, "\n")
 		log.G(ctx).Info("it looks like the instance did not come online, to view logs run:")
@@ -792,6 +992,88 @@ func PrettyPrintInstance(ctx context.Context, instance *kcinstances.GetResponseI
 	}
 }
 
+// InstanceDomains returns every domain at which the provided instance can be
+// reached, i.e. its own FQDN and that of its service group, without duplicates.
+func InstanceDomains(instance *kcinstances.GetResponseItem, serviceGroup *kcservices.GetResponseItem) []string {
+	candidates := []string{instance.FQDN}
+	if serviceGroup != nil {
+		candidates = append(candidates, serviceGroup.FQDN)
+	}
+
+	var domains []string
+	for _, domain := range candidates {
+		domain = strings.TrimSuffix(domain, ".")
+		if domain != "" && !slices.Contains(domains, domain) {
+			domains = append(domains, domain)
+		}
+	}
+
+	return domains
+}
+
+// ServiceGroupPorts returns the exposed ports of the provided service group in
+// the form PORT:DESTINATION_PORT/HANDLERS.
+func ServiceGroupPorts(serviceGroup *kcservices.GetResponseItem) []string {
+	if serviceGroup == nil {
+		return nil
+	}
+
+	var ports []string
+	for _, service := range serviceGroup.Services {
+		var handlers []string
+		for _, handler := range service.Handlers {
+			handlers = append(handlers, string(handler))
+		}
+
+		ports = append(ports, fmt.Sprintf("%d:%d/%s", service.Port, service.DestinationPort, strings.Join(handlers, "+")))
+	}
+
+	return ports
+}
+
+// ServiceURLs returns the addresses at which the exposed ports of the provided
+// service group can be reached via the given FQDN.  Ports which terminate TLS
+// and speak HTTP are returned as https:// URLs, plain HTTP ports as http://
+// URLs and any other ports as FQDN:PORT.  Ports which only redirect to another
+// port are omitted.
+func ServiceURLs(fqdn string, serviceGroup *kcservices.GetResponseItem) []string {
+	if fqdn == "" || serviceGroup == nil {
+		return nil
+	}
+
+	var urls []string
+	for _, service := range serviceGroup.Services {
+		if slices.Contains(service.Handlers, kcservices.HandlerRedirect) {
+			continue
+		}
+
+		isHTTP := slices.Contains(service.Handlers, kcservices.HandlerHTTP)
+		isTLS := slices.Contains(service.Handlers, kcservices.HandlerTLS)
+
+		var url string
+		switch {
+		case service.Port == 443 || (isHTTP && isTLS):
+			url = "https://" + fqdn
+			if service.Port != 443 {
+				url += ":" + strconv.Itoa(service.Port)
+			}
+		case isHTTP:
+			url = "http://" + fqdn
+			if service.Port != 80 {
+				url += ":" + strconv.Itoa(service.Port)
+			}
+		default:
+			url = fqdn + ":" + strconv.Itoa(service.Port)
+		}
+
+		if !slices.Contains(urls, url) {
+			urls = append(urls, url)
+		}
+	}
+
+	return urls
+}
+
 func printJSON(ctx context.Context, data any) error {
 	b, err := json.Marshal(data)
 	if err != nil {
@@ -800,3 +1082,50 @@ func printJSON(ctx context.Context, data any) error {
 	fmt.Fprintln(iostreams.G(ctx).Out, string(b))
 	return nil
 }
+
+// printYAML prints the provided data as YAML, using the same field names as
+// printJSON.
+func printYAML(ctx context.Context, data any) error {
+	b, err := yaml.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("serializing data to YAML: %w", err)
+	}
+	fmt.Fprint(iostreams.G(ctx).Out, string(b))
+	return nil
+}
+
+// printGoTemplate prints each of the provided items using the Go template of
+// the provided `go-template=...` output format, followed by a newline.
+func printGoTemplate[T any](ctx context.Context, format string, items []T) error {
+	tmpl, err := tableprinter.ParseGoTemplate(format)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if err := tmpl.Execute(iostreams.G(ctx).Out, item); err != nil {
+			return fmt.Errorf("could not execute output template: %w", err)
+		}
+		fmt.Fprintln(iostreams.G(ctx).Out)
+	}
+
+	return nil
+}
+
+// ValidateOutput checks that the provided output format is well-formed, i.e.
+// that its template parses when it is a `go-template=...` output format.
+func ValidateOutput(format string) error {
+	_, err := tableprinter.ParseGoTemplate(format)
+	return err
+}
+
+// printJSONLines prints each of the provided items as a standalone JSON object
+// on its own line.
+func printJSONLines[T any](ctx context.Context, items []T) error {
+	for _, item := range items {
+		if err := printJSON(ctx, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package utils
+
+import (
+	"context"
+	"time"
+
+	kraftutils "kraftkit.sh/internal/cli/kraft/utils"
+	"kraftkit.sh/log"
+)
+
+// ForEachWithProgress calls fn for each of the provided instances using at
+// most parallel concurrent workers.  When attached to a terminal, progress is
+// rendered as a process tree with a running completed/total counter;
+// otherwise, each completion is logged as a plain line with an estimate of the
+// remaining time.  A failure for one instance does not prevent the remaining
+// instances from being processed; the number of instances which succeeded is
+// returned alongside all failures.
+func ForEachWithProgress(ctx context.Context, verb string, parallel int, instances []string, fn func(context.Context, string) error) (int, error) {
+	start := time.Now()
+
+	return kraftutils.ForEachWithProgress(ctx, kraftutils.ProgressOptions[string]{
+		Verb:          verb,
+		Parallel:      parallel,
+		Name:          func(instance string) string { return instance },
+		HideOnSuccess: true,
+		Done: func(ctx context.Context, instance string, n int, err error) {
+			if err != nil {
+				log.G(ctx).
+					WithField("instance", instance).
+					WithError(err).
+					Debug("failed")
+			}
+
+			eta := time.Since(start) / time.Duration(n) * time.Duration(len(instances)-n)
+			log.G(ctx).
+				WithField("instance", instance).
+				Infof("%s: %d/%d instance(s) done, eta %s", verb, n, len(instances), eta.Round(time.Second))
+		},
+	}, instances, fn)
+}
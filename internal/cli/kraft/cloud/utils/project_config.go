@@ -0,0 +1,214 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"kraftkit.sh/config"
+	"kraftkit.sh/log"
+	"kraftkit.sh/unikraft/app"
+)
+
+// ProjectConfigFile is the path, relative to a project directory, of the file
+// which records the metro last deployed to from that directory.
+var ProjectConfigFile = filepath.Join(".kraft", "cloud.yaml")
+
+// ProjectTokensFile is the path, relative to the configuration directory, of
+// the file which records the token last deployed with from each project
+// directory.  Unlike the ProjectConfigFile, it is kept outside of the project,
+// such that the token cannot be committed alongside it, and is only readable
+// by the user.
+var ProjectTokensFile = "cloud-tokens.yaml"
+
+// projectConfig is the content of the ProjectConfigFile.  Tokens are recorded
+// in the ProjectTokensFile instead.
+type projectConfig struct {
+	Metro string `yaml:"metro,omitempty"`
+}
+
+// projectDir returns the directory whose ProjectConfigFile is used by the
+// command, i.e. the directory given by its --workdir flag or the current
+// working directory.
+func projectDir(cmd *cobra.Command) (string, error) {
+	if flag := cmd.Flag("workdir"); flag != nil && flag.Value.String() != "" {
+		return flag.Value.String(), nil
+	}
+
+	return os.Getwd()
+}
+
+// loadProjectConfig reads the ProjectConfigFile of the command's project
+// directory.  An empty configuration is returned if it does not exist.
+func loadProjectConfig(cmd *cobra.Command) (*projectConfig, error) {
+	dir, err := projectDir(cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, ProjectConfigFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return &projectConfig{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	cfg := &projectConfig{}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", ProjectConfigFile, err)
+	}
+
+	return cfg, nil
+}
+
+// SaveProjectMetro records the provided metro in the ProjectConfigFile of the
+// command's project directory, such that later commands run from it default to
+// that metro.  To avoid littering arbitrary directories, it is only recorded
+// in directories which contain a Kraftfile.
+func SaveProjectMetro(cmd *cobra.Command, metro string) error {
+	dir, err := projectDir(cmd)
+	if err != nil {
+		return err
+	}
+
+	if !isProjectDir(dir) {
+		return nil
+	}
+
+	cfg, err := loadProjectConfig(cmd)
+	if err != nil {
+		return err
+	}
+
+	if cfg.Metro == metro {
+		return nil
+	}
+
+	path := filepath.Join(dir, ProjectConfigFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	cfg.Metro = metro
+
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return err
+	}
+
+	log.G(cmd.Context()).WithField("path", path).Debug("recording metro")
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+// isProjectDir returns whether the provided directory contains a Kraftfile.
+func isProjectDir(dir string) bool {
+	for _, name := range app.DefaultFileNames {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// loadProjectTokens reads the ProjectTokensFile, which maps the absolute path
+// of a project directory to its token.  An empty map is returned if it does
+// not exist.
+func loadProjectTokens() (map[string]string, error) {
+	data, err := os.ReadFile(filepath.Join(config.ConfigDir(), ProjectTokensFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]string{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	tokens := map[string]string{}
+	if err := yaml.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %w", ProjectTokensFile, err)
+	}
+
+	return tokens, nil
+}
+
+// projectToken returns the token recorded for the command's project
+// directory, if any.
+func projectToken(cmd *cobra.Command) string {
+	dir, err := projectDir(cmd)
+	if err == nil {
+		dir, err = filepath.Abs(dir)
+	}
+	if err != nil {
+		return ""
+	}
+
+	tokens, err := loadProjectTokens()
+	if err != nil {
+		log.G(cmd.Context()).
+			WithError(err).
+			Debug("could not read project tokens")
+		return ""
+	}
+
+	return tokens[dir]
+}
+
+// SaveProjectToken records the provided token for the command's project
+// directory in the ProjectTokensFile, such that later commands run from it
+// default to that token.  Like the metro, it is only recorded for directories
+// which contain a Kraftfile.
+func SaveProjectToken(cmd *cobra.Command, token string) error {
+	dir, err := projectDir(cmd)
+	if err != nil {
+		return err
+	}
+
+	if token == "" || !isProjectDir(dir) {
+		return nil
+	}
+
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+
+	tokens, err := loadProjectTokens()
+	if err != nil {
+		return err
+	}
+
+	if tokens[dir] == token {
+		return nil
+	}
+
+	tokens[dir] = token
+
+	data, err := yaml.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(config.ConfigDir(), ProjectTokensFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	log.G(cmd.Context()).WithField("path", path).Debug("recording token")
+
+	// The file may predate this version with broader permissions.
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return err
+	}
+
+	return os.Chmod(path, 0o600)
+}
@@ -0,0 +1,180 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"kraftkit.sh/log"
+)
+
+const (
+	// RetriesEnv is the environmental variable which sets the number of retries
+	// when the --retries flag is not provided.
+	RetriesEnv = "KRAFTCLOUD_RETRIES"
+
+	// retryBaseDelay is the delay before the first retry, which doubles with
+	// every subsequent attempt.
+	retryBaseDelay = 250 * time.Millisecond
+
+	// retryMaxDelay caps the delay between two attempts.
+	retryMaxDelay = 8 * time.Second
+)
+
+// PopulateRetries sets the number of retries from the `KRAFTCLOUD_RETRIES`
+// environmental variable unless the `--retries` flag was explicitly provided.
+func PopulateRetries(cmd *cobra.Command, retries *int) error {
+	if env := os.Getenv(RetriesEnv); env != "" && !cmd.Flag("retries").Changed {
+		n, err := strconv.Atoi(env)
+		if err != nil {
+			return fmt.Errorf("invalid value for %s: %w", RetriesEnv, err)
+		}
+
+		*retries = n
+	}
+
+	if *retries < 0 {
+		return fmt.Errorf("number of retries cannot be negative")
+	}
+
+	log.G(cmd.Context()).WithField("retries", *retries).Debug("using")
+
+	return nil
+}
+
+// NewRetryHTTPClient returns an HTTP client which retries idempotent requests
+// up to the provided number of times when they fail with a transient error.
+// Attempts are spaced by an exponential backoff with full jitter.  Requests
+// which are not idempotent, e.g. the creation of a resource, are never
+// retried.  Like the client from NewHTTPClient, each request, including its
+// retries, is bounded by the timeout stored in the provided context.
+func NewRetryHTTPClient(ctx context.Context, retries int) *http.Client {
+	client := NewHTTPClient(ctx)
+	client.Transport = &statusTransport{
+		base: &retryTransport{
+			base:    http.DefaultTransport,
+			retries: retries,
+		},
+	}
+
+	return client
+}
+
+type retryTransport struct {
+	base    http.RoundTripper
+	retries int
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !isIdempotent(req.Method) || (req.Body != nil && req.GetBody == nil) {
+		return t.base.RoundTrip(req)
+	}
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+
+			req.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(req)
+		if attempt >= t.retries || !isRetryable(req.Context(), resp, err) {
+			return resp, err
+		}
+
+		delay := Backoff(attempt)
+		if resp != nil {
+			if after := retryAfter(resp); after > delay {
+				delay = after
+			}
+
+			// Drain the body so the underlying connection can be reused.
+			_, _ = io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		entry := log.G(req.Context()).
+			WithField("method", req.Method).
+			WithField("url", req.URL.String()).
+			WithField("attempt", attempt+1)
+		if err != nil {
+			entry = entry.WithError(err)
+		} else {
+			entry = entry.WithField("status", resp.StatusCode)
+		}
+		entry.Debugf("retrying in %s", delay)
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// isIdempotent returns whether a request with the provided method can safely
+// be sent more than once.
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+// isRetryable returns whether the outcome of a request is a transient failure.
+func isRetryable(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		return ctx.Err() == nil && !errors.Is(err, context.Canceled)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// Backoff returns a random delay of up to the exponentially growing delay for
+// the given attempt, counting from zero.
+func Backoff(attempt int) time.Duration {
+	delay := retryMaxDelay
+	if attempt < 6 {
+		delay = min(retryBaseDelay<<attempt, retryMaxDelay)
+	}
+
+	return time.Duration(rand.Int63n(int64(delay)) + 1)
+}
+
+// retryAfter returns the delay requested by the server via the Retry-After
+// header, if any.
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+
+	return min(time.Duration(seconds)*time.Second, retryMaxDelay)
+}
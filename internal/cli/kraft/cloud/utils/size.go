@@ -0,0 +1,31 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package utils
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	kraftutils "kraftkit.sh/internal/cli/kraft/utils"
+)
+
+// PopulateSizeMB parses the value of the flag with the provided name via
+// kraftutils.ParseSizeMB and sets size to the result, if the flag was set.
+func PopulateSizeMB(cmd *cobra.Command, name string, size *int) error {
+	if !cmd.Flags().Changed(name) {
+		return nil
+	}
+
+	mb, err := kraftutils.ParseSizeMB(cmd.Flag(name).Value.String())
+	if err != nil {
+		return fmt.Errorf("invalid --%s: %w", name, err)
+	}
+
+	*size = mb
+
+	return nil
+}
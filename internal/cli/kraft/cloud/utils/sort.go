@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package utils
+
+import (
+	"cmp"
+	"time"
+
+	kcinstances "sdk.kraft.cloud/instances"
+	kcvolumes "sdk.kraft.cloud/volumes"
+)
+
+// InstanceSortFields are the fields by which instances can be sorted via the
+// `--sort` flag.
+var InstanceSortFields = map[string]func(a, b kcinstances.GetResponseItem) int{
+	"name": func(a, b kcinstances.GetResponseItem) int {
+		return cmp.Compare(a.Name, b.Name)
+	},
+	"fqdn": func(a, b kcinstances.GetResponseItem) int {
+		return cmp.Compare(a.FQDN, b.FQDN)
+	},
+	"state": func(a, b kcinstances.GetResponseItem) int {
+		return cmp.Compare(string(a.State), string(b.State))
+	},
+	"created": func(a, b kcinstances.GetResponseItem) int {
+		return compareTimes(a.CreatedAt, b.CreatedAt)
+	},
+	"image": func(a, b kcinstances.GetResponseItem) int {
+		return cmp.Compare(a.Image, b.Image)
+	},
+	"memory": func(a, b kcinstances.GetResponseItem) int {
+		return cmp.Compare(a.MemoryMB, b.MemoryMB)
+	},
+	"boot-time": func(a, b kcinstances.GetResponseItem) int {
+		return cmp.Compare(a.BootTimeUs, b.BootTimeUs)
+	},
+}
+
+// VolumeSortFields are the fields by which volumes can be sorted via the
+// `--sort` flag.
+var VolumeSortFields = map[string]func(a, b kcvolumes.GetResponseItem) int{
+	"name": func(a, b kcvolumes.GetResponseItem) int {
+		return cmp.Compare(a.Name, b.Name)
+	},
+	"created": func(a, b kcvolumes.GetResponseItem) int {
+		return compareTimes(a.CreatedAt, b.CreatedAt)
+	},
+	"size": func(a, b kcvolumes.GetResponseItem) int {
+		return cmp.Compare(a.SizeMB, b.SizeMB)
+	},
+	"state": func(a, b kcvolumes.GetResponseItem) int {
+		return cmp.Compare(string(a.State), string(b.State))
+	},
+}
+
+// compareTimes compares two RFC3339 timestamps.  Timestamps which cannot be
+// parsed are ordered before all others.
+func compareTimes(a, b string) int {
+	ta, erra := time.Parse(time.RFC3339, a)
+	tb, errb := time.Parse(time.RFC3339, b)
+
+	switch {
+	case erra != nil && errb != nil:
+		return 0
+	case erra != nil:
+		return -1
+	case errb != nil:
+		return 1
+	}
+
+	return ta.Compare(tb)
+}
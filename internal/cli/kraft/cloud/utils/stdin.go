@@ -0,0 +1,65 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// StdinArg is the argument which indicates that further arguments should be
+// read from standard input.
+const StdinArg = "-"
+
+// ExpandStdinArgs replaces the StdinArg argument, if present, with the
+// values read from the provided reader.  Only the first whitespace-separated
+// field of each line is used, such that the rows printed by a listing in the
+// list output format, whose first field is the UUID, can be passed through as
+// is.  Empty lines are skipped.
+func ExpandStdinArgs(stdin io.Reader, args []string) ([]string, error) {
+	idx := -1
+	for i, arg := range args {
+		if arg != StdinArg {
+			continue
+		}
+
+		if idx >= 0 {
+			return nil, fmt.Errorf("'%s' can only be provided once", StdinArg)
+		}
+
+		idx = i
+	}
+
+	if idx < 0 {
+		return args, nil
+	}
+
+	var lines []string
+
+	scanner := bufio.NewScanner(stdin)
+	for scanner.Scan() {
+		if fields := strings.Fields(scanner.Text()); len(fields) > 0 {
+			lines = append(lines, fields[0])
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading from stdin: %w", err)
+	}
+
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("no arguments were read from stdin")
+	}
+
+	expanded := make([]string, 0, len(args)-1+len(lines))
+	expanded = append(expanded, args[:idx]...)
+	expanded = append(expanded, lines...)
+	expanded = append(expanded, args[idx+1:]...)
+
+	return expanded, nil
+}
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package utils
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestExpandStdinArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		stdin    string
+		args     []string
+		expected []string
+		err      bool
+	}{
+		{
+			name:     "no stdin argument",
+			stdin:    "ignored\n",
+			args:     []string{"my-instance", "other-instance"},
+			expected: []string{"my-instance", "other-instance"},
+		},
+		{
+			name:     "one value per line",
+			stdin:    "my-instance\nother-instance\n",
+			args:     []string{"-"},
+			expected: []string{"my-instance", "other-instance"},
+		},
+		{
+			name:     "list output rows",
+			stdin:    "77d0316a-fbbe-488d-8618-5bf7a612477a\tmy-app-1\tmy-app-1.fra0.kraft.host\trunning\n0e6ee2e4-fe22-4f3a-b2e1-2e4b3ef7c3d2\tmy-app-2\t-\tstopped\n",
+			args:     []string{"-"},
+			expected: []string{"77d0316a-fbbe-488d-8618-5bf7a612477a", "0e6ee2e4-fe22-4f3a-b2e1-2e4b3ef7c3d2"},
+		},
+		{
+			name:     "surrounding whitespace and empty lines",
+			stdin:    "  my-instance  \n\n\t\nother-instance\r\n",
+			args:     []string{"-"},
+			expected: []string{"my-instance", "other-instance"},
+		},
+		{
+			name:     "retains position among other arguments",
+			stdin:    "my-instance\n",
+			args:     []string{"first", "-", "last"},
+			expected: []string{"first", "my-instance", "last"},
+		},
+		{
+			name:  "stdin argument provided twice",
+			stdin: "my-instance\n",
+			args:  []string{"-", "-"},
+			err:   true,
+		},
+		{
+			name:  "nothing read from stdin",
+			stdin: "\n  \n",
+			args:  []string{"-"},
+			err:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ExpandStdinArgs(strings.NewReader(tt.stdin), tt.args)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("ExpandStdinArgs(%q) returned no error", tt.args)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ExpandStdinArgs(%q) returned unexpected error: %v", tt.args, err)
+			}
+
+			if !slices.Equal(actual, tt.expected) {
+				t.Errorf("ExpandStdinArgs(%q) = %q, expected %q", tt.args, actual, tt.expected)
+			}
+		})
+	}
+}
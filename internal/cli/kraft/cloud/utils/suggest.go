@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package utils
+
+import (
+	"slices"
+	"strings"
+)
+
+// Suggest returns the candidates which are reasonably close to the provided
+// string, e.g. to correct a typo, ordered from closest to furthest.  The
+// comparison is case-insensitive.
+func Suggest(s string, candidates []string) []string {
+	type suggestion struct {
+		candidate string
+		distance  int
+	}
+
+	var suggestions []suggestion
+	for _, candidate := range candidates {
+		d := levenshtein(strings.ToLower(s), strings.ToLower(candidate))
+		if d <= max(2, len(s)/2) {
+			suggestions = append(suggestions, suggestion{candidate, d})
+		}
+	}
+
+	slices.SortStableFunc(suggestions, func(a, b suggestion) int {
+		return a.distance - b.distance
+	})
+
+	ret := make([]string, len(suggestions))
+	for i, suggestion := range suggestions {
+		ret[i] = suggestion.candidate
+	}
+
+	return ret
+}
+
+// levenshtein returns the number of single-character insertions, deletions
+// and substitutions required to change a into b.
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, cur = cur, prev
+	}
+
+	return prev[len(b)]
+}
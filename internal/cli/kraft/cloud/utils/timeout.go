@@ -0,0 +1,99 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"kraftkit.sh/log"
+)
+
+// rpcTimeoutKey is the context key of the timeout of each remote procedure
+// call set via the --timeout flag of the cloud command.
+type rpcTimeoutKey struct{}
+
+// PopulateRPCTimeout stores the timeout set via the persistent --timeout flag
+// of the cloud command in the context of the provided command, such that it
+// bounds each remote procedure call made with the client from NewHTTPClient.
+// Subcommands which define their own --timeout flag take precedence, in which
+// case nothing is stored.
+func PopulateRPCTimeout(cmd *cobra.Command) error {
+	flag := cmd.InheritedFlags().Lookup("timeout")
+	if flag == nil {
+		return nil
+	}
+
+	timeout, err := time.ParseDuration(flag.Value.String())
+	if err != nil {
+		return fmt.Errorf("invalid timeout: %w", err)
+	} else if timeout < 0 {
+		return fmt.Errorf("timeout cannot be negative")
+	}
+
+	if timeout > 0 {
+		log.G(cmd.Context()).WithField("timeout", timeout).Debug("using")
+	}
+
+	cmd.SetContext(WithRPCTimeout(cmd.Context(), timeout))
+
+	return nil
+}
+
+// WithRPCTimeout returns a copy of the provided context which stores the
+// provided timeout of each remote procedure call, for subcommands whose own
+// --timeout flag sets it.
+func WithRPCTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, rpcTimeoutKey{}, timeout)
+}
+
+// RPCTimeout returns the timeout of each remote procedure call stored in the
+// provided context, or zero if there is none.
+func RPCTimeout(ctx context.Context) time.Duration {
+	timeout, _ := ctx.Value(rpcTimeoutKey{}).(time.Duration)
+	return timeout
+}
+
+// NewHTTPClient returns an HTTP client whose requests are bounded by the
+// timeout stored in the provided context by PopulateRPCTimeout, if any.
+// Requests rejected by the KraftCloud API fail with an *APIError.
+func NewHTTPClient(ctx context.Context) *http.Client {
+	return &http.Client{
+		Timeout:   RPCTimeout(ctx),
+		Transport: &statusTransport{base: http.DefaultTransport},
+	}
+}
+
+// WithTimeout returns a copy of the provided context which is cancelled once
+// the provided timeout elapses.  A timeout of zero disables it.
+func WithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// TimeoutError annotates the provided error with the progress made by an
+// operation over multiple instances, e.g. "stopped 3 of 10", if it failed
+// because the context reached its deadline.  Other errors are returned
+// unchanged.
+func TimeoutError(ctx context.Context, err error, timeout time.Duration, done string, n, total int) error {
+	if err == nil {
+		return nil
+	}
+
+	if !errors.Is(err, context.DeadlineExceeded) && !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+
+	return fmt.Errorf("timed out after %s having %s %d of %d instance(s): %w", timeout, done, n, total, err)
+}
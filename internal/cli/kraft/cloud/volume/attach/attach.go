@@ -57,6 +57,7 @@ func Attach(ctx context.Context, opts *AttachOptions, args ...string) (*kcvolume
 	if opts.Client == nil {
 		opts.Client = kraftcloud.NewVolumesClient(
 			kraftcloud.WithToken(config.GetKraftCloudTokenAuthConfig(*opts.Auth)),
+			kraftcloud.WithHTTPClient(utils.NewHTTPClient(ctx)),
 		)
 	}
 
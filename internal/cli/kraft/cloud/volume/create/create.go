@@ -7,7 +7,9 @@ package create
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/spf13/cobra"
@@ -18,54 +20,97 @@ import (
 	"kraftkit.sh/cmdfactory"
 	"kraftkit.sh/config"
 	"kraftkit.sh/internal/cli/kraft/cloud/utils"
+	kraftutils "kraftkit.sh/internal/cli/kraft/utils"
 	"kraftkit.sh/iostreams"
+	"kraftkit.sh/log"
 )
 
 type CreateOptions struct {
-	Auth   *config.AuthConfig       `noattribute:"true"`
-	Client kcvolumes.VolumesService `noattribute:"true"`
-	Metro  string                   `noattribute:"true"`
-	Name   string                   `local:"true" size:"name" short:"n"`
-	SizeMB int                      `local:"true" long:"size" short:"s" usage:"Size in MB"`
-	Token  string                   `noattribute:"true"`
+	Auth     *config.AuthConfig       `noattribute:"true"`
+	Client   kcvolumes.VolumesService `noattribute:"true"`
+	FromFile string                   `local:"true" long:"from-file" short:"f" usage:"Create the volumes listed in a YAML or JSON spec file (or - for stdin)"`
+	Metro    string                   `noattribute:"true"`
+	Name     string                   `local:"true" long:"name" short:"n" usage:"Name of the volume"`
+	Output   string                   `local:"true" long:"output" short:"o" usage:"Set output format of the results when creating multiple volumes. Options: table,yaml,json,list"`
+	SizeMB   int                      `noattribute:"true"`
+	Token    string                   `noattribute:"true"`
+
+	specs []volumeSpec
 }
 
 // Create a KraftCloud persistent volume.
 func Create(ctx context.Context, opts *CreateOptions) (*kcvolumes.CreateResponseItem, error) {
-	var err error
-
 	if opts == nil {
 		opts = &CreateOptions{}
 	}
 
+	if err := opts.initClient(ctx); err != nil {
+		return nil, err
+	}
+
+	return opts.Client.WithMetro(opts.Metro).Create(ctx, opts.Name, opts.SizeMB)
+}
+
+// initClient populates the credentials and the client of the options, unless
+// they were already provided.
+func (opts *CreateOptions) initClient(ctx context.Context) error {
+	var err error
+
 	if opts.Auth == nil {
 		opts.Auth, err = config.GetKraftCloudAuthConfig(ctx, opts.Token)
 		if err != nil {
-			return nil, fmt.Errorf("could not retrieve credentials: %w", err)
+			return fmt.Errorf("could not retrieve credentials: %w", err)
 		}
 	}
 
 	if opts.Client == nil {
 		opts.Client = kraftcloud.NewVolumesClient(
 			kraftcloud.WithToken(config.GetKraftCloudTokenAuthConfig(*opts.Auth)),
+			kraftcloud.WithHTTPClient(utils.NewHTTPClient(ctx)),
 		)
 	}
 
-	return opts.Client.WithMetro(opts.Metro).Create(ctx, opts.Name, opts.SizeMB)
+	return nil
 }
 
 func NewCmd() *cobra.Command {
 	cmd, err := cmdfactory.New(&CreateOptions{}, cobra.Command{
 		Short:   "Create a persistent volume",
-		Use:     "create [FLAGS]",
-		Args:    cobra.NoArgs,
+		Use:     "create [FLAGS] [NAME --size SIZE [NAME --size SIZE [...]]]",
+		Args:    cobra.ArbitraryArgs,
 		Aliases: []string{"crt"},
 		Long: heredoc.Doc(`
-			Create a new persistent volume.
+			Create one or more new persistent volumes.
+
+			Multiple volumes can be created at once either by providing their names as
+			arguments, each followed by its --size, or by listing them in a YAML or
+			JSON spec file provided via --from-file, e.g.:
+
+			  - name: data
+			    size: 2Gi
+			  - name: logs
+			    size: 512Mi
+
+			When a single --size is given, it applies to all of the named volumes.
+			All sizes are validated and the names are checked against the existing
+			volumes before any volume is created.  The result of creating each volume
+			is then reported individually.
 		`),
 		Example: heredoc.Doc(`
 			# Create a new persistent 100MiB volume named "my-volume"
 			$ kraft cloud volume create --size 100 --name my-volume
+
+			# Create a new persistent 2GiB volume named "my-volume"
+			$ kraft cloud volume create --size 2Gi --name my-volume
+
+			# Create a 2GiB volume named "data" and a 512MiB volume named "logs"
+			$ kraft cloud volume create data --size 2Gi logs --size 512Mi
+
+			# Create three 1GiB volumes
+			$ kraft cloud volume create vol-a vol-b vol-c --size 1Gi
+
+			# Create the volumes listed in a spec file
+			$ kraft cloud volume create --from-file volumes.yaml
 		`),
 		Annotations: map[string]string{
 			cmdfactory.AnnotationHelpGroup: "kraftcloud-vol",
@@ -75,15 +120,83 @@ func NewCmd() *cobra.Command {
 		panic(err)
 	}
 
+	cmd.Flags().StringArrayP(
+		"size",
+		"s",
+		nil,
+		"Size of the volume, "+kraftutils.SizeUsage,
+	)
+
 	return cmd
 }
 
-func (opts *CreateOptions) Pre(cmd *cobra.Command, _ []string) error {
-	if opts.SizeMB == 0 {
-		return fmt.Errorf("must specify --size flag")
+func (opts *CreateOptions) Pre(cmd *cobra.Command, args []string) error {
+	sizes, err := cmd.Flags().GetStringArray("size")
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case opts.FromFile != "":
+		if len(args) > 0 || len(sizes) > 0 || opts.Name != "" {
+			return fmt.Errorf("cannot combine --from-file with volume names or the --name and --size flags")
+		}
+
+		if opts.FromFile == "-" && cmd.Flag("token").Value.String() == "-" {
+			return fmt.Errorf("cannot read both the spec and the token from stdin")
+		}
+
+		opts.specs, err = readSpecs(cmd, opts.FromFile)
+		if err != nil {
+			return err
+		}
+
+	case len(args) > 0:
+		if opts.Name != "" {
+			return fmt.Errorf("cannot combine --name with volume names as arguments")
+		}
+
+		switch len(sizes) {
+		case 0:
+			return fmt.Errorf("must specify --size flag")
+		case 1, len(args):
+		default:
+			return fmt.Errorf("got %d --size flags for %d volumes: provide either one for all or one per volume", len(sizes), len(args))
+		}
+
+		for i, name := range args {
+			size := sizes[0]
+			if len(sizes) > 1 {
+				size = sizes[i]
+			}
+
+			opts.specs = append(opts.specs, volumeSpec{Name: name, Size: size})
+		}
+
+	default:
+		if len(sizes) != 1 {
+			return fmt.Errorf("must specify --size flag exactly once")
+		}
+
+		opts.specs = []volumeSpec{{Name: opts.Name, Size: sizes[0]}}
 	}
 
-	err := utils.PopulateMetroToken(cmd, &opts.Metro, &opts.Token)
+	if err := validateSpecs(opts.specs); err != nil {
+		return err
+	}
+
+	if len(opts.specs) == 1 {
+		opts.Name = opts.specs[0].Name
+		opts.SizeMB = opts.specs[0].sizeMB
+	}
+
+	if opts.Output != "" {
+		if err := utils.ValidateOutput(opts.Output); err != nil {
+			return err
+		}
+	}
+
+	err = utils.PopulateMetroToken(cmd, &opts.Metro, &opts.Token)
 	if err != nil {
 		return fmt.Errorf("could not populate metro and token: %w", err)
 	}
@@ -92,11 +205,89 @@ func (opts *CreateOptions) Pre(cmd *cobra.Command, _ []string) error {
 }
 
 func (opts *CreateOptions) Run(ctx context.Context, _ []string) error {
-	volume, err := Create(ctx, opts)
+	// Creating a single volume outputs its UUID only, such that it can be
+	// consumed by scripts.
+	if len(opts.specs) <= 1 && opts.Output == "" {
+		volume, err := Create(ctx, opts)
+		if err != nil {
+			return fmt.Errorf("could not create volume: %w", err)
+		}
+
+		_, err = fmt.Fprintln(iostreams.G(ctx).Out, volume.UUID)
+		return err
+	}
+
+	if err := opts.initClient(ctx); err != nil {
+		return err
+	}
+
+	if err := opts.checkExisting(ctx); err != nil {
+		return utils.ClassifyError(err)
+	}
+
+	results := make([]createResult, len(opts.specs))
+	var errs []error
+
+	created := 0
+	for i, spec := range opts.specs {
+		results[i] = createResult{
+			Name:   spec.Name,
+			SizeMB: spec.sizeMB,
+			Status: "failed",
+		}
+
+		volume, err := opts.Client.WithMetro(opts.Metro).Create(ctx, spec.Name, spec.sizeMB)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not create volume %s: %w", spec.Name, err))
+			continue
+		}
+
+		if results[i].Name == "" {
+			results[i].Name = volume.Name
+		}
+		results[i].UUID = volume.UUID
+		results[i].Status = "created"
+		created++
+	}
+
+	log.G(ctx).Infof("Created %d of %d volume(s)", created, len(opts.specs))
+
+	format := opts.Output
+	if format == "" {
+		format = "list"
+	}
+
+	if err := printResults(ctx, format, results); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// checkExisting returns an error naming the volumes to create whose name is
+// already taken by an existing volume, determined from a single listing of
+// all volumes.
+func (opts *CreateOptions) checkExisting(ctx context.Context) error {
+	vols, err := opts.Client.WithMetro(opts.Metro).List(ctx)
 	if err != nil {
-		return fmt.Errorf("could not create volume: %w", err)
+		return fmt.Errorf("could not list volumes: %w", err)
+	}
+
+	existing := make(map[string]struct{}, len(vols))
+	for _, vol := range vols {
+		existing[vol.Name] = struct{}{}
 	}
 
-	_, err = fmt.Fprintln(iostreams.G(ctx).Out, volume.UUID)
-	return err
+	var taken []string
+	for _, spec := range opts.specs {
+		if _, ok := existing[spec.Name]; ok && spec.Name != "" {
+			taken = append(taken, spec.Name)
+		}
+	}
+
+	if len(taken) > 0 {
+		return fmt.Errorf("volume(s) already exist: %s", strings.Join(taken, ", "))
+	}
+
+	return nil
 }
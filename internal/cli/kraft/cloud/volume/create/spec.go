@@ -0,0 +1,127 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package create
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	kraftutils "kraftkit.sh/internal/cli/kraft/utils"
+	"kraftkit.sh/internal/tableprinter"
+	"kraftkit.sh/iostreams"
+)
+
+// volumeSpec is a single volume to create, as listed in the file provided via
+// `--from-file` or given as arguments.
+type volumeSpec struct {
+	Name string `yaml:"name"`
+	Size string `yaml:"size"`
+
+	sizeMB int
+}
+
+// readSpecs reads the volumes to create from the provided YAML or JSON file,
+// or from the standard input if the path is `-`.
+func readSpecs(cmd *cobra.Command, path string) ([]volumeSpec, error) {
+	var data []byte
+	var err error
+
+	if path == "-" {
+		data, err = io.ReadAll(iostreams.G(cmd.Context()).In)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read volume spec: %w", err)
+	}
+
+	var specs []volumeSpec
+	if err := yaml.Unmarshal(data, &specs); err != nil {
+		return nil, fmt.Errorf("could not parse volume spec: %w", err)
+	}
+
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("volume spec '%s' does not list any volumes", path)
+	}
+
+	return specs, nil
+}
+
+// validateSpecs parses the size of each of the provided volumes and checks
+// that their names are unique.  All problems are reported at once.
+func validateSpecs(specs []volumeSpec) error {
+	var errs []error
+	seen := make(map[string]struct{}, len(specs))
+
+	for i := range specs {
+		spec := &specs[i]
+
+		if spec.Name == "" && len(specs) > 1 {
+			errs = append(errs, fmt.Errorf("volume %d: a name is required when creating multiple volumes", i+1))
+		} else if _, ok := seen[spec.Name]; ok {
+			errs = append(errs, fmt.Errorf("volume %s: name is listed more than once", spec.Name))
+		}
+		seen[spec.Name] = struct{}{}
+
+		if spec.Size == "" {
+			errs = append(errs, fmt.Errorf("volume %s: a size is required", spec.Name))
+			continue
+		}
+
+		mb, err := kraftutils.ParseSizeMB(spec.Size)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("volume %s: %w", spec.Name, err))
+			continue
+		}
+
+		spec.sizeMB = mb
+	}
+
+	return errors.Join(errs...)
+}
+
+// createResult represents the outcome of creating a single volume.
+type createResult struct {
+	UUID   string
+	Name   string
+	SizeMB int
+	Status string
+}
+
+// printResults outputs the result of creating each volume in the provided
+// format.
+func printResults(ctx context.Context, format string, results []createResult) error {
+	cs := iostreams.G(ctx).ColorScheme()
+	table, err := tableprinter.NewTablePrinter(ctx,
+		tableprinter.WithMaxWidth(iostreams.G(ctx).TerminalWidth()),
+		tableprinter.WithOutputFormatFromString(format),
+	)
+	if err != nil {
+		return err
+	}
+
+	table.AddField("UUID", cs.Bold)
+	table.AddField("NAME", cs.Bold)
+	table.AddField("SIZE", cs.Bold)
+	table.AddField("STATUS", cs.Bold)
+	table.EndRow()
+
+	for _, result := range results {
+		table.AddField(result.UUID, nil)
+		table.AddField(result.Name, nil)
+		table.AddField(fmt.Sprintf("%d MiB", result.SizeMB), nil)
+		table.AddField(result.Status, nil)
+		table.EndRow()
+	}
+
+	return table.Render(iostreams.G(ctx).Out)
+}
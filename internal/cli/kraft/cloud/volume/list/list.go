@@ -8,24 +8,33 @@ package list
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/spf13/cobra"
 
 	kraftcloud "sdk.kraft.cloud"
+	kcinstances "sdk.kraft.cloud/instances"
 	kcvolumes "sdk.kraft.cloud/volumes"
 
 	"kraftkit.sh/cmdfactory"
 	"kraftkit.sh/config"
 	"kraftkit.sh/internal/cli/kraft/cloud/utils"
+	"kraftkit.sh/internal/tableprinter"
+	"kraftkit.sh/iostreams"
 )
 
 type ListOptions struct {
-	Output string `long:"output" short:"o" usage:"Set output format. Options: table,yaml,json,list" default:"table"`
-	Watch  bool   `long:"watch" short:"w" usage:"After listing watch for changes."`
-
-	metro string
-	token string
+	Columns []string `long:"columns" usage:"Only show the given comma-separated columns, in the given order"`
+	Long    bool     `long:"long" short:"l" usage:"Show where each volume is mounted and the state of the instances it is attached to"`
+	Output  string   `long:"output" short:"o" usage:"Set output format. Options: table,yaml,json,jsonl,list,go-template=TEMPLATE" default:"table"`
+	Quiet   bool     `long:"quiet" short:"q" usage:"Only display volume UUIDs, one per line"`
+	Watch   bool     `long:"watch" short:"w" usage:"After listing watch for changes."`
+	Sort    string   `long:"sort" usage:"Sort the volumes by a field in the form FIELD[:asc|desc] (name, created, size, state)"`
+
+	metro     string
+	token     string
+	sortOrder *tableprinter.SortOrder
 }
 
 func NewCmd() *cobra.Command {
@@ -43,6 +52,15 @@ func NewCmd() *cobra.Command {
 
 			# List all volumes in your account in JSON format.
 			$ kraft cloud volume list -o json
+
+			# List all volumes in your account, the largest first.
+			$ kraft cloud volume list --sort size:desc
+
+			# List only the UUIDs of all volumes in your account.
+			$ kraft cloud volume list -q
+
+			# List all volumes in your account alongside where they are mounted.
+			$ kraft cloud volume list -l
 		`),
 		Annotations: map[string]string{
 			cmdfactory.AnnotationHelpGroup: "kraftcloud-vol",
@@ -56,37 +74,113 @@ func NewCmd() *cobra.Command {
 }
 
 func (opts *ListOptions) Pre(cmd *cobra.Command, _ []string) error {
+	if err := utils.ValidateOutput(opts.Output); err != nil {
+		return err
+	}
+
 	err := utils.PopulateMetroToken(cmd, &opts.metro, &opts.token)
 	if err != nil {
 		return fmt.Errorf("could not populate metro and token: %w", err)
 	}
 
-	return nil
+	opts.sortOrder, err = tableprinter.ParseSortOrder(opts.Sort, utils.VolumeSortFields)
+	return err
 }
 
 func (opts *ListOptions) Run(ctx context.Context, args []string) error {
+	ctx = tableprinter.WithColumnsInContext(ctx, opts.Columns)
+
 	auth, err := config.GetKraftCloudAuthConfig(ctx, opts.token)
 	if err != nil {
 		return fmt.Errorf("could not retrieve credentials: %w", err)
 	}
 
-	client := kraftcloud.NewVolumesClient(
+	client := kraftcloud.NewClient(
 		kraftcloud.WithToken(config.GetKraftCloudTokenAuthConfig(*auth)),
+		kraftcloud.WithHTTPClient(utils.NewHTTPClient(ctx)),
 	)
 
-	volListResp, err := client.WithMetro(opts.metro).List(ctx)
+	volListResp, err := client.Volumes().WithMetro(opts.metro).List(ctx)
 	if err != nil {
 		return fmt.Errorf("could not list volumes: %w", err)
 	}
 
 	vols := make([]kcvolumes.GetResponseItem, 0, len(volListResp))
 	for _, volItem := range volListResp {
-		v, err := client.WithMetro(opts.metro).GetByUUID(ctx, volItem.UUID)
+		v, err := client.Volumes().WithMetro(opts.metro).GetByUUID(ctx, volItem.UUID)
 		if err != nil {
 			return fmt.Errorf("getting details of volume %s: %w", volItem.UUID, err)
 		}
 		vols = append(vols, *v)
 	}
 
+	tableprinter.Sort(vols, opts.sortOrder, utils.VolumeSortFields)
+
+	if opts.Quiet {
+		for _, vol := range vols {
+			fmt.Fprintln(iostreams.G(ctx).Out, vol.UUID)
+		}
+
+		return nil
+	}
+
+	if opts.Long {
+		mounts, err := opts.mounts(ctx, client.Instances(), vols)
+		if err != nil {
+			return err
+		}
+
+		return utils.PrintVolumeMounts(ctx, opts.Output, mounts, vols...)
+	}
+
 	return utils.PrintVolumes(ctx, opts.Output, vols...)
 }
+
+// mounts joins the provided volumes against the instances they are attached
+// to, returning where each of the volumes is mounted.
+func (opts *ListOptions) mounts(ctx context.Context, client kcinstances.InstancesService, vols []kcvolumes.GetResponseItem) ([][]utils.VolumeMount, error) {
+	var names []string
+	for _, vol := range vols {
+		for _, attch := range vol.AttachedTo {
+			if !slices.Contains(names, attch.Name) {
+				names = append(names, attch.Name)
+			}
+		}
+	}
+
+	var insts []kcinstances.GetResponseItem
+	if len(names) > 0 {
+		var err error
+		insts, err = utils.GetInstances(ctx, client.WithMetro(opts.metro), names...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	mounts := make([][]utils.VolumeMount, len(vols))
+	for i, vol := range vols {
+		mounts[i] = []utils.VolumeMount{}
+
+		for _, attch := range vol.AttachedTo {
+			for _, inst := range insts {
+				if inst.Name != attch.Name {
+					continue
+				}
+
+				for _, instVol := range inst.Volumes {
+					if instVol.Name != vol.Name {
+						continue
+					}
+
+					mounts[i] = append(mounts[i], utils.VolumeMount{
+						Instance: inst.Name,
+						At:       instVol.At,
+						State:    string(inst.State),
+					})
+				}
+			}
+		}
+	}
+
+	return mounts, nil
+}
@@ -7,20 +7,30 @@ package remove
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/spf13/cobra"
+	"golang.org/x/sync/errgroup"
 
 	kraftcloud "sdk.kraft.cloud"
+	kcvolumes "sdk.kraft.cloud/volumes"
 
 	"kraftkit.sh/cmdfactory"
 	"kraftkit.sh/config"
 	"kraftkit.sh/internal/cli/kraft/cloud/utils"
+	"kraftkit.sh/internal/tableprinter"
 	"kraftkit.sh/iostreams"
+	"kraftkit.sh/log"
 )
 
 type RemoveOptions struct {
+	Force  bool   `long:"force" short:"f" usage:"Detach the volume from any instance before removing it"`
+	Output string `long:"output" short:"o" usage:"Set output format. Options: table,yaml,json,list" default:"list"`
+
 	metro string
 	token string
 }
@@ -37,7 +47,7 @@ func Remove(ctx context.Context, opts *RemoveOptions, args ...string) error {
 func NewCmd() *cobra.Command {
 	cmd, err := cmdfactory.New(&RemoveOptions{}, cobra.Command{
 		Short:   "Permanently delete a persistent volume",
-		Use:     "remove UUID [UUID [...]]",
+		Use:     "remove UUID|NAME [UUID|NAME [...]]",
 		Args:    cobra.MinimumNArgs(1),
 		Aliases: []string{"rm"},
 		Long: heredoc.Doc(`
@@ -46,6 +56,12 @@ func NewCmd() *cobra.Command {
 		Example: heredoc.Doc(`
 			# Delete three persistent volumes
 			$ kraft cloud volume rm UUID1 UUID2 UUID3
+
+			# Detach a persistent volume from its instance and delete it
+			$ kraft cloud volume rm --force my-volume
+
+			# Delete a persistent volume and report the result as JSON
+			$ kraft cloud volume rm -o json my-volume
 		`),
 		Annotations: map[string]string{
 			cmdfactory.AnnotationHelpGroup: "kraftcloud-vol",
@@ -68,6 +84,11 @@ func (opts *RemoveOptions) Pre(cmd *cobra.Command, _ []string) error {
 }
 
 func (opts *RemoveOptions) Run(ctx context.Context, args []string) error {
+	// Reject malformed arguments before any of the volumes is removed.
+	if _, _, err := utils.ClassifyArgs(args); err != nil {
+		return err
+	}
+
 	auth, err := config.GetKraftCloudAuthConfig(ctx, opts.token)
 	if err != nil {
 		return fmt.Errorf("could not retrieve credentials: %w", err)
@@ -75,23 +96,185 @@ func (opts *RemoveOptions) Run(ctx context.Context, args []string) error {
 
 	client := kraftcloud.NewVolumesClient(
 		kraftcloud.WithToken(config.GetKraftCloudTokenAuthConfig(*auth)),
+		kraftcloud.WithHTTPClient(utils.NewHTTPClient(ctx)),
 	)
 
-	for _, arg := range args {
+	results, errs := opts.resolve(ctx, client, args)
+
+	// There is no request which deletes several volumes at once, so remove the
+	// resolved volumes concurrently.  A failure to remove one volume does not
+	// prevent the others from being removed.
+	var mu sync.Mutex
+	var g errgroup.Group
+	g.SetLimit(concurrentRemovals)
+
+	removed := 0
+	for i, result := range results {
+		if result.UUID == "" {
+			continue
+		}
+
+		i, result := i, result
+		g.Go(func() error {
+			err := opts.detach(ctx, client, result.UUID, result.arg)
+			if err == nil {
+				if _, err = client.WithMetro(opts.metro).DeleteByUUID(ctx, result.UUID); err != nil {
+					err = fmt.Errorf("could not delete volume %s: %w", result.arg, err)
+				}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				errs = append(errs, err)
+				return nil
+			}
+
+			results[i].Status = "removed"
+			removed++
+
+			return nil
+		})
+	}
+
+	_ = g.Wait()
+
+	log.G(ctx).Infof("Removed %d of %d volume(s)", removed, len(args))
+
+	if err := printResults(ctx, opts.Output, results); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// concurrentRemovals is the maximum number of volumes which are removed at
+// once.
+const concurrentRemovals = 8
+
+// detach checks whether the volume with the provided UUID is attached to any
+// instance.  If it is, the volume is detached when `--force` is set, otherwise
+// an error naming the instance(s) is returned.
+func (opts *RemoveOptions) detach(ctx context.Context, client kcvolumes.VolumesService, uuid, arg string) error {
+	vol, err := client.WithMetro(opts.metro).GetByUUID(ctx, uuid)
+	if err != nil {
+		return fmt.Errorf("getting details of volume %s: %w", arg, err)
+	}
+
+	if len(vol.AttachedTo) == 0 {
+		return nil
+	}
+
+	attachedTo := make([]string, 0, len(vol.AttachedTo))
+	for _, attch := range vol.AttachedTo {
+		attachedTo = append(attachedTo, attch.Name)
+	}
+
+	if !opts.Force {
+		return fmt.Errorf("volume %s is attached to instance(s) %s: stop the instance(s) or use --force to detach it", arg, strings.Join(attachedTo, ", "))
+	}
+
+	log.G(ctx).
+		WithField("volume", arg).
+		Infof("detaching from %s", strings.Join(attachedTo, ", "))
+
+	if _, err := client.WithMetro(opts.metro).DetachByUUID(ctx, uuid); err != nil {
+		return fmt.Errorf("could not detach volume %s: %w", arg, err)
+	}
+
+	return nil
+}
+
+// removeResult represents the outcome of removing a single volume.
+type removeResult struct {
+	UUID   string
+	Name   string
+	Status string
+
+	arg string
+}
+
+// resolve returns the results for each of the provided volumes, each of which
+// may be identified either by its UUID or by its name, with their UUID and
+// name populated from a single listing of all volumes.  Volumes which could
+// not be resolved are returned as errors and have their status set to failed.
+func (opts *RemoveOptions) resolve(ctx context.Context, client kcvolumes.VolumesService, args []string) ([]removeResult, []error) {
+	results := make([]removeResult, len(args))
+	for i, arg := range args {
+		results[i] = removeResult{
+			Status: "failed",
+			arg:    arg,
+		}
+
 		if utils.IsUUID(arg) {
-			_, err = client.WithMetro(opts.metro).DeleteByUUID(ctx, arg)
+			results[i].UUID = arg
 		} else {
-			_, err = client.WithMetro(opts.metro).DeleteByName(ctx, arg)
+			results[i].Name = arg
 		}
-		if err != nil {
-			return fmt.Errorf("could not delete volume: %w", err)
+	}
+
+	vols, err := client.WithMetro(opts.metro).List(ctx)
+	if err != nil {
+		var errs []error
+		for _, result := range results {
+			if result.UUID == "" {
+				errs = append(errs, fmt.Errorf("could not resolve volume %s: %w", result.arg, err))
+			}
 		}
 
-		_, err = fmt.Fprintln(iostreams.G(ctx).Out, arg)
-		if err != nil {
-			return fmt.Errorf("could not write volume UUID: %w", err)
+		return results, errs
+	}
+
+	byName := make(map[string]string, len(vols))
+	byUUID := make(map[string]string, len(vols))
+	for _, vol := range vols {
+		byName[vol.Name] = vol.UUID
+		byUUID[vol.UUID] = vol.Name
+	}
+
+	var errs []error
+	for i, result := range results {
+		if result.UUID != "" {
+			results[i].Name = byUUID[result.UUID]
+			continue
+		}
+
+		uuid, ok := byName[result.Name]
+		if !ok {
+			errs = append(errs, fmt.Errorf("could not find volume %s", result.Name))
+			continue
 		}
+
+		results[i].UUID = uuid
 	}
 
-	return nil
+	return results, errs
+}
+
+// printResults outputs the result of removing each volume in the provided
+// format.
+func printResults(ctx context.Context, format string, results []removeResult) error {
+	cs := iostreams.G(ctx).ColorScheme()
+	table, err := tableprinter.NewTablePrinter(ctx,
+		tableprinter.WithMaxWidth(iostreams.G(ctx).TerminalWidth()),
+		tableprinter.WithOutputFormatFromString(format),
+	)
+	if err != nil {
+		return err
+	}
+
+	table.AddField("UUID", cs.Bold)
+	table.AddField("NAME", cs.Bold)
+	table.AddField("STATUS", cs.Bold)
+	table.EndRow()
+
+	for _, result := range results {
+		table.AddField(result.UUID, nil)
+		table.AddField(result.Name, nil)
+		table.AddField(result.Status, nil)
+		table.EndRow()
+	}
+
+	return table.Render(iostreams.G(ctx).Out)
 }
@@ -25,7 +25,8 @@ import (
 )
 
 type BuildOptions struct {
-	composefile string
+	composefiles []string
+	profiles     []string
 }
 
 func NewCmd() *cobra.Command {
@@ -51,11 +52,21 @@ func (opts *BuildOptions) Pre(cmd *cobra.Command, _ []string) error {
 
 	cmd.SetContext(ctx)
 
-	if cmd.Flag("file").Changed {
-		opts.composefile = cmd.Flag("file").Value.String()
+	composefiles, err := cmd.Flags().GetStringSlice("file")
+	if err != nil {
+		return err
+	}
+
+	opts.composefiles = composefiles
+
+	profiles, err := cmd.Flags().GetStringSlice("profile")
+	if err != nil {
+		return err
 	}
 
-	log.G(cmd.Context()).WithField("composefile", opts.composefile).Debug("using")
+	opts.profiles = profiles
+
+	log.G(cmd.Context()).WithField("composefiles", opts.composefiles).Debug("using")
 	return nil
 }
 
@@ -65,7 +76,7 @@ func (opts *BuildOptions) Run(ctx context.Context, args []string) error {
 		return err
 	}
 
-	project, err := compose.NewProjectFromComposeFile(ctx, workdir, opts.composefile)
+	project, err := compose.NewProjectFromComposeFiles(ctx, workdir, opts.composefiles, compose.WithProfiles(opts.profiles...))
 	if err != nil {
 		return err
 	}
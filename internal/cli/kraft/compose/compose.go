@@ -14,14 +14,21 @@ import (
 
 	"kraftkit.sh/cmdfactory"
 	"kraftkit.sh/internal/cli/kraft/compose/build"
+	"kraftkit.sh/internal/cli/kraft/compose/config"
 	"kraftkit.sh/internal/cli/kraft/compose/down"
+	"kraftkit.sh/internal/cli/kraft/compose/events"
+	"kraftkit.sh/internal/cli/kraft/compose/logs"
 	"kraftkit.sh/internal/cli/kraft/compose/ls"
 	"kraftkit.sh/internal/cli/kraft/compose/ps"
+	"kraftkit.sh/internal/cli/kraft/compose/pull"
+	"kraftkit.sh/internal/cli/kraft/compose/restart"
+	"kraftkit.sh/internal/cli/kraft/compose/scale"
 	"kraftkit.sh/internal/cli/kraft/compose/up"
 )
 
 type ComposeOptions struct {
-	Composefile string `long:"file" short:"f" usage:"Set the Compose file."`
+	Composefiles []string `long:"file" short:"f" usage:"Set the Compose file (can be used multiple times, merged in order)"`
+	Profiles     []string `long:"profile" usage:"Enable services of the given profile (can be used multiple times)"`
 }
 
 func NewCmd() *cobra.Command {
@@ -46,9 +53,15 @@ func NewCmd() *cobra.Command {
 	}
 
 	cmd.AddCommand(build.NewCmd())
+	cmd.AddCommand(config.NewCmd())
 	cmd.AddCommand(down.NewCmd())
+	cmd.AddCommand(events.NewCmd())
+	cmd.AddCommand(logs.NewCmd())
 	cmd.AddCommand(ls.NewCmd())
 	cmd.AddCommand(ps.NewCmd())
+	cmd.AddCommand(pull.NewCmd())
+	cmd.AddCommand(restart.NewCmd())
+	cmd.AddCommand(scale.NewCmd())
 	cmd.AddCommand(up.NewCmd())
 
 	return cmd
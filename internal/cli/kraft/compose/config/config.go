@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+
+	"kraftkit.sh/cmdfactory"
+	"kraftkit.sh/compose"
+	"kraftkit.sh/iostreams"
+	"kraftkit.sh/log"
+)
+
+type ConfigOptions struct {
+	Quiet bool `long:"quiet" short:"q" usage:"Only validate the configuration, don't print anything"`
+
+	composefiles []string
+	profiles     []string
+}
+
+func NewCmd() *cobra.Command {
+	cmd, err := cmdfactory.New(&ConfigOptions{}, cobra.Command{
+		Short:   "Validate and print the configuration of the current project",
+		Use:     "config [FLAGS]",
+		Args:    cobra.NoArgs,
+		Aliases: []string{},
+		Long: heredoc.Doc(`
+			Validate and print the configuration of the current project.
+
+			The configuration is printed as YAML after variable interpolation and
+			normalization, exactly as it is used by the other compose subcommands.
+		`),
+		Example: heredoc.Doc(`
+			# Print the resolved configuration of the current project
+			$ kraft compose config
+
+			# Only validate the configuration of the current project
+			$ kraft compose config --quiet
+		`),
+		Annotations: map[string]string{
+			cmdfactory.AnnotationHelpGroup: "compose",
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+func (opts *ConfigOptions) Pre(cmd *cobra.Command, _ []string) error {
+	composefiles, err := cmd.Flags().GetStringSlice("file")
+	if err != nil {
+		return err
+	}
+
+	opts.composefiles = composefiles
+
+	profiles, err := cmd.Flags().GetStringSlice("profile")
+	if err != nil {
+		return err
+	}
+
+	opts.profiles = profiles
+
+	log.G(cmd.Context()).WithField("composefiles", opts.composefiles).Debug("using")
+	return nil
+}
+
+func (opts *ConfigOptions) Run(ctx context.Context, _ []string) error {
+	workdir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	project, err := compose.NewProjectFromComposeFiles(ctx, workdir, opts.composefiles, compose.WithProfiles(opts.profiles...))
+	if err != nil {
+		return err
+	}
+
+	// Serialize the model before validating it, since validation prefixes the
+	// service names with the project name.
+	out, err := project.MarshalYAML()
+	if err != nil {
+		return fmt.Errorf("marshalling project: %w", err)
+	}
+
+	if err := project.Validate(ctx); err != nil {
+		return err
+	}
+
+	if opts.Quiet {
+		return nil
+	}
+
+	_, err = iostreams.G(ctx).Out.Write(out)
+	return err
+}
@@ -7,6 +7,7 @@ package down
 
 import (
 	"context"
+	"fmt"
 	"os"
 
 	"github.com/MakeNowJust/heredoc"
@@ -22,12 +23,17 @@ import (
 
 	machineapi "kraftkit.sh/api/machine/v1alpha1"
 	networkapi "kraftkit.sh/api/network/v1alpha1"
+	volumeapi "kraftkit.sh/api/volume/v1alpha1"
 	mnetwork "kraftkit.sh/machine/network"
 	mplatform "kraftkit.sh/machine/platform"
+	"kraftkit.sh/machine/volume"
 )
 
 type DownOptions struct {
-	composefile string
+	Volumes bool `long:"volumes" short:"v" usage:"Also remove the named volumes declared in the compose project"`
+
+	composefiles []string
+	profiles     []string
 }
 
 func NewCmd() *cobra.Command {
@@ -39,6 +45,9 @@ func NewCmd() *cobra.Command {
 		Example: heredoc.Doc(`
 			# Stop and remove a compose project
 			$ kraft compose down
+
+			# Stop and remove a compose project and its named volumes
+			$ kraft compose down --volumes
 		`),
 		Annotations: map[string]string{
 			cmdfactory.AnnotationHelpGroup: "compose",
@@ -59,11 +68,21 @@ func (opts *DownOptions) Pre(cmd *cobra.Command, _ []string) error {
 
 	cmd.SetContext(ctx)
 
-	if cmd.Flag("file").Changed {
-		opts.composefile = cmd.Flag("file").Value.String()
+	composefiles, err := cmd.Flags().GetStringSlice("file")
+	if err != nil {
+		return err
+	}
+
+	opts.composefiles = composefiles
+
+	profiles, err := cmd.Flags().GetStringSlice("profile")
+	if err != nil {
+		return err
 	}
 
-	log.G(cmd.Context()).WithField("composefile", opts.composefile).Debug("using")
+	opts.profiles = profiles
+
+	log.G(cmd.Context()).WithField("composefiles", opts.composefiles).Debug("using")
 	return nil
 }
 
@@ -72,7 +91,7 @@ func (opts *DownOptions) Run(ctx context.Context, args []string) error {
 	if err != nil {
 		return err
 	}
-	project, err := compose.NewProjectFromComposeFile(ctx, workdir, opts.composefile)
+	project, err := compose.NewProjectFromComposeFiles(ctx, workdir, opts.composefiles, compose.WithProfiles(opts.profiles...))
 	if err != nil {
 		return err
 	}
@@ -91,14 +110,24 @@ func (opts *DownOptions) Run(ctx context.Context, args []string) error {
 		return err
 	}
 
-	for _, service := range project.Services {
-		for _, machine := range machines.Items {
-			if service.Name == machine.Name {
-				if err := removeService(ctx, service); err != nil {
+	// Machines which do not belong to the project, which may still use its
+	// volumes.
+	var others []machineapi.Machine
+
+	for _, machine := range machines.Items {
+		owned := false
+		for _, service := range project.Services {
+			if compose.IsReplicaOf(service, machine.Name) {
+				owned = true
+				if err := removeService(ctx, machine.Name); err != nil {
 					return err
 				}
 			}
 		}
+
+		if !owned {
+			others = append(others, machine)
+		}
 	}
 
 	networkController, err := mnetwork.NewNetworkV1alpha1ServiceIterator(ctx)
@@ -121,14 +150,18 @@ func (opts *DownOptions) Run(ctx context.Context, args []string) error {
 		}
 	}
 
+	if opts.Volumes {
+		return removeVolumes(ctx, project, others)
+	}
+
 	return nil
 }
 
-func removeService(ctx context.Context, service types.ServiceConfig) error {
-	log.G(ctx).Infof("removing service %s...", service.Name)
+func removeService(ctx context.Context, machineName string) error {
+	log.G(ctx).Infof("removing service %s...", machineName)
 	removeOptions := machineremove.RemoveOptions{Platform: "auto"}
 
-	return removeOptions.Run(ctx, []string{service.Name})
+	return removeOptions.Run(ctx, []string{machineName})
 }
 
 func removeNetwork(ctx context.Context, network types.NetworkConfig) error {
@@ -141,3 +174,77 @@ func removeNetwork(ctx context.Context, network types.NetworkConfig) error {
 
 	return removeOptions.Run(ctx, []string{network.Name})
 }
+
+// removeVolumes removes the named volumes which are declared in the project,
+// except for external volumes and volumes which are still attached to any of
+// the provided machines that are not part of the project.
+func removeVolumes(ctx context.Context, project *compose.Project, others []machineapi.Machine) error {
+	for sname, strategy := range volume.Strategies() {
+		controller, err := strategy.NewVolumeV1alpha1(ctx)
+		if err != nil {
+			return fmt.Errorf("could not prepare %s volume service: %w", sname, err)
+		}
+
+		volumes, err := controller.List(ctx, &volumeapi.VolumeList{})
+		if err != nil {
+			return fmt.Errorf("could not list %s volumes: %w", sname, err)
+		}
+
+		for _, vol := range volumes.Items {
+			if !isProjectVolume(project, vol.Name) {
+				continue
+			}
+
+			if machine := attachedTo(others, vol.Name); machine != "" {
+				log.G(ctx).Warnf("not removing volume %s as it is still attached to %s", vol.Name, machine)
+				continue
+			}
+
+			log.G(ctx).Infof("removing volume %s...", vol.Name)
+
+			if _, err := controller.Delete(ctx, &vol); err != nil {
+				return fmt.Errorf("could not remove volume %s: %w", vol.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// isProjectVolume returns whether the volume with the provided name is a named
+// volume declared in the project which is not external.  Only the name under
+// which the volume is created is matched, i.e. its explicit name or otherwise
+// the key prefixed with the name of the project, such that volumes declared
+// under the same key by other projects are left untouched.
+func isProjectVolume(project *compose.Project, name string) bool {
+	for key, vol := range project.Volumes {
+		if vol.External.External {
+			continue
+		}
+
+		scoped := vol.Name
+		if scoped == "" {
+			scoped = project.Name + "_" + key
+		}
+
+		if name == scoped {
+			return true
+		}
+	}
+
+	return false
+}
+
+// attachedTo returns the name of the first of the provided machines to which
+// the volume with the provided name is attached, if any.
+func attachedTo(machines []machineapi.Machine, name string) string {
+	for _, machine := range machines {
+		for _, vol := range machine.Spec.Volumes {
+			if vol.Name == name {
+				return machine.Name
+			}
+		}
+	}
+
+	return ""
+}
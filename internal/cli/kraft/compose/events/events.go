@@ -0,0 +1,330 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/spf13/cobra"
+
+	machineapi "kraftkit.sh/api/machine/v1alpha1"
+	"kraftkit.sh/cmdfactory"
+	"kraftkit.sh/compose"
+	"kraftkit.sh/iostreams"
+	"kraftkit.sh/log"
+	mplatform "kraftkit.sh/machine/platform"
+	"kraftkit.sh/packmanager"
+)
+
+type EventsOptions struct {
+	Interval time.Duration `long:"interval" usage:"How often the state of the services is checked (ms/s/m/h)" default:"1000000000"`
+	JSON     bool          `long:"json" usage:"Print each event as a JSON object on its own line"`
+
+	composefiles []string
+	profiles     []string
+}
+
+// The events which are emitted for the machines of the services.
+const (
+	EventStarting = "starting"
+	EventStarted  = "started"
+	EventHealthy  = "healthy"
+	EventPaused   = "paused"
+	EventStopped  = "stopped"
+	EventFailed   = "failed"
+	EventRemoved  = "removed"
+)
+
+// Event is a change of the lifecycle of a machine of a service.
+type Event struct {
+	Time     time.Time               `json:"time"`
+	Service  string                  `json:"service"`
+	Machine  string                  `json:"machine"`
+	Event    string                  `json:"event"`
+	State    machineapi.MachineState `json:"state,omitempty"`
+	ExitCode *int                    `json:"exit_code,omitempty"`
+}
+
+func NewCmd() *cobra.Command {
+	cmd, err := cmdfactory.New(&EventsOptions{}, cobra.Command{
+		Short: "Stream the lifecycle events of the services of the current project",
+		Use:   "events [FLAGS] [SERVICE...]",
+		Long: heredoc.Doc(`
+			Stream the lifecycle events of the services of the current project.
+
+			The events are derived from the changes of the state of the machines of
+			the services, which are checked every --interval, such that the command
+			can be used alongside 'kraft compose up'.  The current state of each
+			machine is emitted first, followed by the following events until the
+			command is interrupted:
+
+			  starting  the machine was created or is restarting
+			  started   the machine is running
+			  healthy   the machine has been running for the start period of the
+			            health check of its service, if it declares one
+			  paused    the machine was paused or suspended
+			  stopped   the machine exited
+			  failed    the machine failed
+			  removed   the machine was removed
+		`),
+		Example: heredoc.Doc(`
+			# Stream the events of all services in the current project
+			$ kraft compose events
+
+			# Stream the events of a single service as JSON
+			$ kraft compose events --json web
+
+			# Wait until the service web is healthy
+			$ kraft compose events --json web | jq -e 'select(.event == "healthy")' | head -n 1
+		`),
+		Annotations: map[string]string{
+			cmdfactory.AnnotationHelpGroup: "compose",
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+func (opts *EventsOptions) Pre(cmd *cobra.Command, _ []string) error {
+	if opts.Interval <= 0 {
+		return fmt.Errorf("interval must be positive")
+	}
+
+	ctx, err := packmanager.WithDefaultUmbrellaManagerInContext(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	cmd.SetContext(ctx)
+
+	composefiles, err := cmd.Flags().GetStringSlice("file")
+	if err != nil {
+		return err
+	}
+
+	opts.composefiles = composefiles
+
+	profiles, err := cmd.Flags().GetStringSlice("profile")
+	if err != nil {
+		return err
+	}
+
+	opts.profiles = profiles
+
+	log.G(cmd.Context()).WithField("composefiles", opts.composefiles).Debug("using")
+	return nil
+}
+
+// machineStatus is the last known status of a machine of a service.
+type machineStatus struct {
+	service string
+	event   string
+	healthy bool
+}
+
+func (opts *EventsOptions) Run(ctx context.Context, args []string) error {
+	workdir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	project, err := compose.NewProjectFromComposeFiles(ctx, workdir, opts.composefiles, compose.WithProfiles(opts.profiles...))
+	if err != nil {
+		return err
+	}
+
+	if err := project.Validate(ctx); err != nil {
+		return err
+	}
+
+	services := project.Services
+	if len(args) > 0 {
+		services = types.Services{}
+		for _, arg := range args {
+			service, err := project.LookupService(arg)
+			if err != nil {
+				return fmt.Errorf("no such service: %s", arg)
+			}
+
+			services = append(services, service)
+		}
+	}
+
+	controller, err := mplatform.NewMachineV1alpha1ServiceIterator(ctx)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	known := map[string]*machineStatus{}
+
+	for {
+		machines, err := controller.List(ctx, &machineapi.MachineList{})
+		if ctx.Err() != nil {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("could not list machines: %w", err)
+		}
+
+		seen := map[string]bool{}
+
+		for _, machine := range machines.Items {
+			service, ok := serviceOf(services, machine.Name)
+			if !ok {
+				continue
+			}
+
+			seen[machine.Name] = true
+
+			status, ok := known[machine.Name]
+			if !ok {
+				status = &machineStatus{service: service.Name}
+				known[machine.Name] = status
+			}
+
+			if err := opts.observe(ctx, service, machine, status); err != nil {
+				return err
+			}
+		}
+
+		for name, status := range known {
+			if seen[name] {
+				continue
+			}
+
+			if err := opts.emit(ctx, Event{
+				Time:    time.Now(),
+				Service: status.service,
+				Machine: name,
+				Event:   EventRemoved,
+			}); err != nil {
+				return err
+			}
+
+			delete(known, name)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(opts.Interval):
+		}
+	}
+}
+
+// observe emits the events which follow from the current state of the provided
+// machine of a service given its last known status, which is updated.
+func (opts *EventsOptions) observe(ctx context.Context, service types.ServiceConfig, machine machineapi.Machine, status *machineStatus) error {
+	event := Event{
+		Time:    time.Now(),
+		Service: service.Name,
+		Machine: machine.Name,
+		State:   machine.Status.State,
+	}
+
+	switch machine.Status.State {
+	case machineapi.MachineStateCreated, machineapi.MachineStateRestarting:
+		event.Event = EventStarting
+	case machineapi.MachineStateRunning:
+		event.Event = EventStarted
+	case machineapi.MachineStatePaused, machineapi.MachineStateSuspended:
+		event.Event = EventPaused
+	case machineapi.MachineStateExited:
+		event.Event = EventStopped
+		exitCode := machine.Status.ExitCode
+		event.ExitCode = &exitCode
+	case machineapi.MachineStateFailed, machineapi.MachineStateErrored:
+		event.Event = EventFailed
+	default:
+		return nil
+	}
+
+	if event.Event != status.event {
+		if err := opts.emit(ctx, event); err != nil {
+			return err
+		}
+
+		status.event = event.Event
+		status.healthy = false
+	}
+
+	// Since health check commands cannot be executed inside of a unikernel, a
+	// machine is considered healthy once it has been running for the start
+	// period of the health check, as when waiting for dependencies.
+	startPeriod, ok := healthCheckStartPeriod(service)
+	if !ok || status.healthy || event.Event != EventStarted || time.Since(machine.Status.StartedAt) < startPeriod {
+		return nil
+	}
+
+	status.healthy = true
+	event.Time = time.Now()
+	event.Event = EventHealthy
+
+	return opts.emit(ctx, event)
+}
+
+// emit prints the provided event as a single line.
+func (opts *EventsOptions) emit(ctx context.Context, event Event) error {
+	out := iostreams.G(ctx).Out
+
+	if opts.JSON {
+		b, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("serializing event to JSON: %w", err)
+		}
+
+		_, err = fmt.Fprintln(out, string(b))
+		return err
+	}
+
+	line := fmt.Sprintf("%s %s %s %s", event.Time.Format(time.RFC3339), event.Service, event.Machine, event.Event)
+	if event.ExitCode != nil {
+		line += fmt.Sprintf(" (exit code %d)", *event.ExitCode)
+	}
+
+	_, err := fmt.Fprintln(out, line)
+	return err
+}
+
+// serviceOf returns the service of the provided services which the machine
+// with the provided name is a replica of.
+func serviceOf(services types.Services, machineName string) (types.ServiceConfig, bool) {
+	for _, service := range services {
+		if compose.IsReplicaOf(service, machineName) {
+			return service, true
+		}
+	}
+
+	return types.ServiceConfig{}, false
+}
+
+// healthCheckStartPeriod returns the start period of the health check of the
+// provided service, if it declares one which is not disabled.
+func healthCheckStartPeriod(service types.ServiceConfig) (time.Duration, bool) {
+	hc := service.HealthCheck
+	if hc == nil || hc.Disable {
+		return 0, false
+	}
+
+	if hc.StartPeriod == nil {
+		return 0, true
+	}
+
+	return time.Duration(*hc.StartPeriod), true
+}
@@ -0,0 +1,209 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package logs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	composeapi "kraftkit.sh/api/compose/v1"
+	"kraftkit.sh/cmdfactory"
+	"kraftkit.sh/compose"
+	"kraftkit.sh/internal/cli/kraft/logs"
+	"kraftkit.sh/log"
+	"kraftkit.sh/packmanager"
+)
+
+type LogsOptions struct {
+	Follow bool `long:"follow" usage:"Follow log output"`
+	Tail   int  `long:"tail" short:"n" usage:"Lines of recent logs to display per service" default:"-1"`
+
+	composefiles []string
+	profiles     []string
+}
+
+func NewCmd() *cobra.Command {
+	cmd, err := cmdfactory.New(&LogsOptions{}, cobra.Command{
+		Short:   "Print the logs of services in the current project",
+		Use:     "logs [FLAGS] [SERVICE...]",
+		Aliases: []string{},
+		Long:    "Print the logs of services in the current project.",
+		Example: heredoc.Doc(`
+			# Print the logs of all services in the current project
+			$ kraft compose logs
+
+			# Follow the logs of a single service
+			$ kraft compose logs --follow web
+
+			# Print the last 20 lines of each service
+			$ kraft compose logs --tail 20
+		`),
+		Annotations: map[string]string{
+			cmdfactory.AnnotationHelpGroup: "compose",
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+func (opts *LogsOptions) Pre(cmd *cobra.Command, _ []string) error {
+	ctx, err := packmanager.WithDefaultUmbrellaManagerInContext(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	cmd.SetContext(ctx)
+
+	composefiles, err := cmd.Flags().GetStringSlice("file")
+	if err != nil {
+		return err
+	}
+
+	opts.composefiles = composefiles
+
+	profiles, err := cmd.Flags().GetStringSlice("profile")
+	if err != nil {
+		return err
+	}
+
+	opts.profiles = profiles
+
+	log.G(cmd.Context()).WithField("composefiles", opts.composefiles).Debug("using")
+	return nil
+}
+
+func (opts *LogsOptions) Run(ctx context.Context, args []string) error {
+	workdir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	project, err := compose.NewProjectFromComposeFiles(ctx, workdir, opts.composefiles, compose.WithProfiles(opts.profiles...))
+	if err != nil {
+		return err
+	}
+
+	if err := project.Validate(ctx); err != nil {
+		return err
+	}
+
+	controller, err := compose.NewComposeProjectV1(ctx)
+	if err != nil {
+		return err
+	}
+
+	embeddedProject, err := controller.Get(ctx, &composeapi.Compose{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: project.Name,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	services := []types.ServiceConfig{}
+	for _, arg := range args {
+		found := false
+		for _, service := range project.Services {
+			if service.Name == arg || service.Name == project.Name+"-"+arg {
+				services = append(services, service)
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("no such service: %s", arg)
+		}
+	}
+
+	if len(args) == 0 {
+		services = project.Services
+	}
+
+	// Only consider services which have a machine as part of the project,
+	// keyed by the name of each machine.
+	running := map[string]types.ServiceConfig{}
+	for _, service := range services {
+		for _, machine := range embeddedProject.Status.Machines {
+			if compose.IsReplicaOf(service, machine.Name) {
+				running[machine.Name] = service
+			}
+		}
+	}
+
+	if len(running) == 0 {
+		log.G(ctx).Info("no running services")
+		return nil
+	}
+
+	longestName := 0
+	if len(running) > 1 {
+		for name := range running {
+			if len(name) > longestName {
+				longestName = len(name)
+			}
+		}
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var errs []error
+
+	for name, service := range running {
+		wg.Add(1)
+		go func(service types.ServiceConfig, name string) {
+			defer wg.Done()
+
+			// A failure for one service does not stop the logs of the others.
+			if err := opts.logService(ctx, service, name, longestName); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("could not log service %s: %w", name, err))
+				mu.Unlock()
+			}
+		}(service, name)
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// logService prints the logs of the provided machine of a service.  When
+// prefixLength is non-zero, each line is prefixed with the name of the machine
+// padded to the given length.
+func (opts *LogsOptions) logService(ctx context.Context, service types.ServiceConfig, machineName string, prefixLength int) error {
+	var prefix string
+	if prefixLength > 0 {
+		prefix = machineName + strings.Repeat(" ", prefixLength-len(machineName))
+	}
+
+	parts := strings.SplitN(service.Platform, "/", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid platform: %s for service %s", service.Platform, service.Name)
+	}
+
+	logOptions := logs.LogOptions{
+		Follow:   opts.Follow,
+		Platform: parts[0],
+		Prefix:   prefix,
+		Tail:     opts.Tail,
+	}
+
+	return logOptions.Run(ctx, []string{machineName})
+}
@@ -8,6 +8,7 @@ package ls
 import (
 	"context"
 	"path/filepath"
+	"strings"
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/spf13/cobra"
@@ -22,8 +23,9 @@ import (
 )
 
 type LsOptions struct {
-	ShowAll bool   `long:"all" short:"a" usage:"Show all projects (default shows just running)"`
-	Output  string `long:"output" short:"o" usage:"Set output format" default:"table"`
+	Columns []string `long:"columns" usage:"Only show the given comma-separated columns, in the given order"`
+	ShowAll bool     `long:"all" short:"a" usage:"Show all projects (default shows just running)"`
+	Output  string   `long:"output" short:"o" usage:"Set output format" default:"table"`
 }
 
 func NewCmd() *cobra.Command {
@@ -59,6 +61,8 @@ func (opts *LsOptions) Pre(cmd *cobra.Command, _ []string) error {
 }
 
 func (opts *LsOptions) Run(ctx context.Context, args []string) error {
+	ctx = tableprinter.WithColumnsInContext(ctx, opts.Columns)
+
 	controller, err := compose.NewComposeProjectV1(ctx)
 	if err != nil {
 		return err
@@ -105,8 +109,16 @@ func (opts *LsOptions) Run(ctx context.Context, args []string) error {
 		table.AddField(project.Name, nil)
 		table.AddField(status, nil)
 
-		composefile := filepath.Join(project.Spec.Workdir, project.Spec.Composefile)
-		table.AddField(composefile, nil)
+		composefiles := project.Spec.Composefiles
+		if len(composefiles) == 0 {
+			composefiles = []string{project.Spec.Composefile}
+		}
+
+		for i, composefile := range composefiles {
+			composefiles[i] = filepath.Join(project.Spec.Workdir, composefile)
+		}
+
+		table.AddField(strings.Join(composefiles, ","), nil)
 		table.EndRow()
 	}
 
@@ -7,7 +7,10 @@ package ps
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"slices"
+	"strings"
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/spf13/cobra"
@@ -16,15 +19,34 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	composeapi "kraftkit.sh/api/compose/v1"
+	machineapi "kraftkit.sh/api/machine/v1alpha1"
 	pslist "kraftkit.sh/internal/cli/kraft/ps"
+	"kraftkit.sh/iostreams"
 	"kraftkit.sh/log"
 	"kraftkit.sh/packmanager"
 )
 
 type PsOptions struct {
-	ShowAll bool `long:"all" short:"a" usage:"Show all machines (default shows just running)"`
+	Filter   []string `long:"filter" usage:"Filter the list in the form KEY=VALUE, where the only supported key is status"`
+	Services bool     `long:"services" usage:"Only print the names of the services"`
+	ShowAll  bool     `long:"all" short:"a" usage:"Show all machines (default shows just running)"`
 
-	composefile string
+	composefiles []string
+	profiles     []string
+	statuses     []machineapi.MachineState
+}
+
+// machineStates are the accepted values of the `status` filter.
+var machineStates = []machineapi.MachineState{
+	machineapi.MachineStateUnknown,
+	machineapi.MachineStateCreated,
+	machineapi.MachineStateFailed,
+	machineapi.MachineStateRestarting,
+	machineapi.MachineStateRunning,
+	machineapi.MachineStatePaused,
+	machineapi.MachineStateSuspended,
+	machineapi.MachineStateExited,
+	machineapi.MachineStateErrored,
 }
 
 func NewCmd() *cobra.Command {
@@ -37,6 +59,9 @@ func NewCmd() *cobra.Command {
 		Example: heredoc.Doc(`
 			# List running services of current project
 			$ kraft compose ps
+
+			# List the names of the services which have exited
+			$ kraft compose ps --services --filter status=exited
 		`),
 		Annotations: map[string]string{
 			cmdfactory.AnnotationHelpGroup: "compose",
@@ -57,11 +82,45 @@ func (opts *PsOptions) Pre(cmd *cobra.Command, _ []string) error {
 
 	cmd.SetContext(ctx)
 
-	if cmd.Flag("file").Changed {
-		opts.composefile = cmd.Flag("file").Value.String()
+	composefiles, err := cmd.Flags().GetStringSlice("file")
+	if err != nil {
+		return err
+	}
+
+	opts.composefiles = composefiles
+
+	profiles, err := cmd.Flags().GetStringSlice("profile")
+	if err != nil {
+		return err
 	}
 
-	log.G(cmd.Context()).WithField("composefile", opts.composefile).Debug("using")
+	opts.profiles = profiles
+
+	for _, filter := range opts.Filter {
+		key, value, ok := strings.Cut(filter, "=")
+		if !ok {
+			return fmt.Errorf("invalid filter '%s': expected KEY=VALUE", filter)
+		}
+
+		switch key {
+		case "status":
+			status := machineapi.MachineState(value)
+			if !slices.Contains(machineStates, status) {
+				return fmt.Errorf("invalid status '%s': expected one of %v", value, machineStates)
+			}
+
+			opts.statuses = append(opts.statuses, status)
+		default:
+			return fmt.Errorf("invalid filter '%s': unknown key '%s'", filter, key)
+		}
+	}
+
+	// Filtering by status is meaningless if only running machines are listed.
+	if len(opts.statuses) > 0 {
+		opts.ShowAll = true
+	}
+
+	log.G(cmd.Context()).WithField("composefiles", opts.composefiles).Debug("using")
 	return nil
 }
 
@@ -70,7 +129,7 @@ func (opts *PsOptions) Run(ctx context.Context, args []string) error {
 	if err != nil {
 		return err
 	}
-	project, err := compose.NewProjectFromComposeFile(ctx, workdir, opts.composefile)
+	project, err := compose.NewProjectFromComposeFiles(ctx, workdir, opts.composefiles, compose.WithProfiles(opts.profiles...))
 	if err != nil {
 		return err
 	}
@@ -103,14 +162,43 @@ func (opts *PsOptions) Run(ctx context.Context, args []string) error {
 		return err
 	}
 
+	// Only list machines of services which are enabled by the active profiles.
 	filteredPsTable := []pslist.PsEntry{}
+	services := []string{}
 	for _, psEntry := range psTable {
+		if len(opts.statuses) > 0 && !slices.Contains(opts.statuses, psEntry.State) {
+			continue
+		}
+
+		serviceName := ""
+		for _, service := range project.Services {
+			if compose.IsReplicaOf(service, psEntry.Name) {
+				serviceName = service.Name
+				break
+			}
+		}
+		if serviceName == "" {
+			continue
+		}
+
 		for _, machine := range embeddedProject.Status.Machines {
 			if psEntry.Name == machine.Name {
 				filteredPsTable = append(filteredPsTable, psEntry)
+
+				if !slices.Contains(services, serviceName) {
+					services = append(services, serviceName)
+				}
 			}
 		}
 	}
 
+	if opts.Services {
+		for _, service := range services {
+			fmt.Fprintln(iostreams.G(ctx).Out, service)
+		}
+
+		return nil
+	}
+
 	return pslistOptions.PrintPsTable(ctx, filteredPsTable)
 }
@@ -0,0 +1,203 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package pull
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/spf13/cobra"
+
+	"kraftkit.sh/cmdfactory"
+	"kraftkit.sh/compose"
+	"kraftkit.sh/internal/cli/kraft/pkg/pull"
+	"kraftkit.sh/log"
+	"kraftkit.sh/packmanager"
+	"kraftkit.sh/unikraft"
+)
+
+type PullOptions struct {
+	ForcePull          bool `long:"force-pull" usage:"Force pulling packages even if they are available locally"`
+	IgnorePullFailures bool `long:"ignore-pull-failures" usage:"Continue with the remaining services if pulling a service fails"`
+
+	composefiles []string
+	profiles     []string
+}
+
+func NewCmd() *cobra.Command {
+	cmd, err := cmdfactory.New(&PullOptions{}, cobra.Command{
+		Short:   "Pull the images of services in the current project",
+		Use:     "pull [FLAGS] [SERVICE...]",
+		Aliases: []string{},
+		Long: heredoc.Doc(`
+			Pull the images of services in the current project without starting them.
+
+			Services with an image have it pulled into the local package catalog,
+			unless it is already present.  Services with a build context have the
+			dependencies of their Kraftfile pulled instead.
+		`),
+		Example: heredoc.Doc(`
+			# Pull the images of all services in the current project
+			$ kraft compose pull
+
+			# Pull the image of a single service, even if it is available locally
+			$ kraft compose pull --force-pull web
+
+			# Pull the images of all services, skipping those which fail
+			$ kraft compose pull --ignore-pull-failures
+		`),
+		Annotations: map[string]string{
+			cmdfactory.AnnotationHelpGroup: "compose",
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+func (opts *PullOptions) Pre(cmd *cobra.Command, _ []string) error {
+	ctx, err := packmanager.WithDefaultUmbrellaManagerInContext(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	cmd.SetContext(ctx)
+
+	composefiles, err := cmd.Flags().GetStringSlice("file")
+	if err != nil {
+		return err
+	}
+
+	opts.composefiles = composefiles
+
+	profiles, err := cmd.Flags().GetStringSlice("profile")
+	if err != nil {
+		return err
+	}
+
+	opts.profiles = profiles
+
+	log.G(cmd.Context()).WithField("composefiles", opts.composefiles).Debug("using")
+	return nil
+}
+
+func (opts *PullOptions) Run(ctx context.Context, args []string) error {
+	workdir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	project, err := compose.NewProjectFromComposeFiles(ctx, workdir, opts.composefiles, compose.WithProfiles(opts.profiles...))
+	if err != nil {
+		return err
+	}
+
+	if err := project.Validate(ctx); err != nil {
+		return err
+	}
+
+	services := []types.ServiceConfig{}
+	for _, arg := range args {
+		service, err := project.LookupService(arg)
+		if err != nil {
+			return fmt.Errorf("no such service: %s", arg)
+		}
+
+		services = append(services, service)
+	}
+
+	if len(args) == 0 {
+		services = project.Services
+	}
+
+	for _, service := range services {
+		if err := opts.pullService(ctx, service); err != nil {
+			if !opts.IgnorePullFailures {
+				return fmt.Errorf("pulling service %s: %w", service.Name, err)
+			}
+
+			log.G(ctx).
+				WithError(err).
+				Warnf("could not pull service %s", service.Name)
+		}
+	}
+
+	return nil
+}
+
+// pullService pulls the image of the provided service, or the dependencies of
+// its build context if it has no image.
+func (opts *PullOptions) pullService(ctx context.Context, service types.ServiceConfig) error {
+	plat, arch, err := platArchFromService(service)
+	if err != nil {
+		return err
+	}
+
+	if service.Image == "" {
+		if service.Build == nil {
+			log.G(ctx).Debugf("service %s has neither an image nor a build context, skipping", service.Name)
+			return nil
+		}
+
+		log.G(ctx).Infof("pulling dependencies of service %s...", service.Name)
+
+		pullOptions := pull.PullOptions{
+			Architecture: arch,
+			Platform:     plat,
+			Update:       opts.ForcePull,
+			Workdir:      service.Build.Context,
+		}
+
+		return pullOptions.Run(ctx, []string{service.Build.Context})
+	}
+
+	imageName, imageVersion, ok := strings.Cut(service.Image, ":")
+	if !ok {
+		imageVersion = "latest"
+	}
+
+	if !opts.ForcePull {
+		packages, err := packmanager.G(ctx).Catalog(ctx,
+			packmanager.WithArchitecture(arch),
+			packmanager.WithName(imageName),
+			packmanager.WithPlatform(plat),
+			packmanager.WithTypes(unikraft.ComponentTypeApp),
+			packmanager.WithVersion(imageVersion))
+		if err != nil {
+			return err
+		}
+
+		if len(packages) != 0 {
+			log.G(ctx).Infof("service %s is already available locally", service.Name)
+			return nil
+		}
+	}
+
+	log.G(ctx).Infof("pulling service %s...", service.Name)
+
+	pullOptions := pull.PullOptions{
+		Architecture: arch,
+		Platform:     plat,
+	}
+
+	return pullOptions.Run(ctx, []string{imageName + ":" + imageVersion})
+}
+
+func platArchFromService(service types.ServiceConfig) (string, string, error) {
+	// The service platform should be in the form <platform>/<arch>
+	parts := strings.SplitN(service.Platform, "/", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("invalid platform: %s for service %s", service.Platform, service.Name)
+	}
+
+	return parts[0], parts[1], nil
+}
@@ -0,0 +1,217 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package restart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/compose-spec/compose-go/types"
+	"github.com/spf13/cobra"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	composeapi "kraftkit.sh/api/compose/v1"
+	machineapi "kraftkit.sh/api/machine/v1alpha1"
+	"kraftkit.sh/cmdfactory"
+	"kraftkit.sh/compose"
+	"kraftkit.sh/log"
+	mplatform "kraftkit.sh/machine/platform"
+	"kraftkit.sh/packmanager"
+)
+
+type RestartOptions struct {
+	Timeout time.Duration `long:"timeout" short:"t" usage:"Time to wait for each service to stop before giving up" default:"10000000000"`
+
+	composefiles []string
+	profiles     []string
+}
+
+func NewCmd() *cobra.Command {
+	cmd, err := cmdfactory.New(&RestartOptions{}, cobra.Command{
+		Short:   "Restart services of the current project",
+		Use:     "restart [FLAGS] [SERVICE...]",
+		Aliases: []string{},
+		Long: heredoc.Doc(`
+			Restart services of the current project.
+
+			Services are stopped in reverse dependency order and started again in
+			dependency order.  If no services are given, all services of the project
+			are restarted.
+		`),
+		Example: heredoc.Doc(`
+			# Restart all services of the current project
+			$ kraft compose restart
+
+			# Restart a single service
+			$ kraft compose restart web
+
+			# Allow services up to 30 seconds to stop
+			$ kraft compose restart --timeout 30s
+		`),
+		Annotations: map[string]string{
+			cmdfactory.AnnotationHelpGroup: "compose",
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+func (opts *RestartOptions) Pre(cmd *cobra.Command, _ []string) error {
+	ctx, err := packmanager.WithDefaultUmbrellaManagerInContext(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	cmd.SetContext(ctx)
+
+	composefiles, err := cmd.Flags().GetStringSlice("file")
+	if err != nil {
+		return err
+	}
+
+	opts.composefiles = composefiles
+
+	profiles, err := cmd.Flags().GetStringSlice("profile")
+	if err != nil {
+		return err
+	}
+
+	opts.profiles = profiles
+
+	if opts.Timeout <= 0 {
+		return fmt.Errorf("timeout must be greater than zero")
+	}
+
+	log.G(cmd.Context()).WithField("composefiles", opts.composefiles).Debug("using")
+	return nil
+}
+
+func (opts *RestartOptions) Run(ctx context.Context, args []string) error {
+	workdir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	project, err := compose.NewProjectFromComposeFiles(ctx, workdir, opts.composefiles, compose.WithProfiles(opts.profiles...))
+	if err != nil {
+		return err
+	}
+
+	if err := project.Validate(ctx); err != nil {
+		return err
+	}
+
+	waves, err := project.ServiceWaves(args...)
+	if err != nil {
+		return err
+	}
+
+	composeController, err := compose.NewComposeProjectV1(ctx)
+	if err != nil {
+		return err
+	}
+
+	embeddedProject, err := composeController.Get(ctx, &composeapi.Compose{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: project.Name,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	machineController, err := mplatform.NewMachineV1alpha1ServiceIterator(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Only restart services which have a machine as part of the project.
+	machines := map[string][]*machineapi.Machine{}
+	for _, wave := range waves {
+		for _, service := range wave {
+			for _, meta := range embeddedProject.Status.Machines {
+				if !compose.IsReplicaOf(service, meta.Name) {
+					continue
+				}
+
+				machine, err := machineController.Get(ctx, &machineapi.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: meta.Name,
+					},
+				})
+				if err != nil {
+					return fmt.Errorf("getting machine of service %s: %w", meta.Name, err)
+				}
+
+				machines[service.Name] = append(machines[service.Name], machine)
+			}
+		}
+	}
+
+	if len(machines) == 0 {
+		log.G(ctx).Info("no services to restart")
+		return nil
+	}
+
+	for i := len(waves) - 1; i >= 0; i-- {
+		if err := opts.stopWave(ctx, machineController, waves[i], machines); err != nil {
+			return err
+		}
+	}
+
+	for _, wave := range waves {
+		for _, service := range wave {
+			for _, machine := range machines[service.Name] {
+				log.G(ctx).Infof("starting service %s...", machine.Name)
+
+				if _, err := machineController.Start(ctx, machine); err != nil {
+					return fmt.Errorf("starting service %s: %w", machine.Name, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// stopWave stops the machines of the provided services, allowing each of them
+// up to the configured timeout to drain.
+func (opts *RestartOptions) stopWave(ctx context.Context, controller machineapi.MachineService, wave types.Services, machines map[string][]*machineapi.Machine) error {
+	var errs []error
+
+	for _, service := range wave {
+		for i, machine := range machines[service.Name] {
+			if machine.Status.State == machineapi.MachineStateExited {
+				continue
+			}
+
+			log.G(ctx).Infof("stopping service %s...", machine.Name)
+
+			stopCtx, cancel := context.WithTimeout(ctx, opts.Timeout)
+			stopped, err := controller.Stop(stopCtx, machine)
+			cancel()
+
+			if errors.Is(err, context.DeadlineExceeded) {
+				errs = append(errs, fmt.Errorf("service %s did not stop within %s", machine.Name, opts.Timeout))
+				continue
+			} else if err != nil {
+				errs = append(errs, fmt.Errorf("stopping service %s: %w", machine.Name, err))
+				continue
+			}
+
+			machines[service.Name][i] = stopped
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,227 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package scale
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/MakeNowJust/heredoc"
+	"github.com/spf13/cobra"
+
+	"kraftkit.sh/cmdfactory"
+	"kraftkit.sh/compose"
+	"kraftkit.sh/internal/cli/kraft/compose/up"
+	"kraftkit.sh/internal/cli/kraft/remove"
+	"kraftkit.sh/log"
+	"kraftkit.sh/packmanager"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	composeapi "kraftkit.sh/api/compose/v1"
+	machineapi "kraftkit.sh/api/machine/v1alpha1"
+	mplatform "kraftkit.sh/machine/platform"
+)
+
+type ScaleOptions struct {
+	composefiles []string
+	profiles     []string
+	replicas     map[string]int
+}
+
+func NewCmd() *cobra.Command {
+	cmd, err := cmdfactory.New(&ScaleOptions{}, cobra.Command{
+		Short:   "Scale services of a compose project",
+		Use:     "scale [FLAGS] SERVICE=N [SERVICE=N...]",
+		Args:    cobra.MinimumNArgs(1),
+		Aliases: []string{},
+		Long: heredoc.Doc(`
+			Scale services of a running compose project.
+
+			Machines are created or removed until each of the named services has
+			the requested number of replicas, without editing the compose file.
+			Replicas are named in the same way as by 'kraft compose up', such that
+			they are tracked by 'kraft compose ps' and 'kraft compose down'.
+		`),
+		Example: heredoc.Doc(`
+			# Scale the web service to three replicas
+			$ kraft compose scale web=3
+
+			# Scale the web service to two and the worker service to four replicas
+			$ kraft compose scale web=2 worker=4
+		`),
+		Annotations: map[string]string{
+			cmdfactory.AnnotationHelpGroup: "compose",
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return cmd
+}
+
+func (opts *ScaleOptions) Pre(cmd *cobra.Command, args []string) error {
+	ctx, err := packmanager.WithDefaultUmbrellaManagerInContext(cmd.Context())
+	if err != nil {
+		return err
+	}
+
+	cmd.SetContext(ctx)
+
+	composefiles, err := cmd.Flags().GetStringSlice("file")
+	if err != nil {
+		return err
+	}
+
+	opts.composefiles = composefiles
+
+	profiles, err := cmd.Flags().GetStringSlice("profile")
+	if err != nil {
+		return err
+	}
+
+	opts.profiles = profiles
+
+	opts.replicas = map[string]int{}
+	for _, arg := range args {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			return fmt.Errorf("invalid scale '%s': expected SERVICE=N", arg)
+		}
+
+		replicas, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid scale '%s': %w", arg, err)
+		}
+
+		opts.replicas[name] = replicas
+	}
+
+	log.G(cmd.Context()).WithField("composefiles", opts.composefiles).Debug("using")
+	return nil
+}
+
+func (opts *ScaleOptions) Run(ctx context.Context, _ []string) error {
+	workdir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	project, err := compose.NewProjectFromComposeFiles(ctx, workdir, opts.composefiles, compose.WithProfiles(opts.profiles...))
+	if err != nil {
+		return err
+	}
+
+	if err := project.Validate(ctx); err != nil {
+		return err
+	}
+
+	for name, replicas := range opts.replicas {
+		if err := project.SetServiceReplicas(name, replicas); err != nil {
+			return err
+		}
+	}
+
+	if err := project.AssignIPs(ctx); err != nil {
+		return err
+	}
+
+	composeController, err := compose.NewComposeProjectV1(ctx)
+	if err != nil {
+		return err
+	}
+
+	embeddedProject, err := composeController.Get(ctx, &composeapi.Compose{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: project.Name,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if embeddedProject == nil {
+		return fmt.Errorf("project %s is not running: use 'kraft compose up' first", project.Name)
+	}
+
+	machineController, err := mplatform.NewMachineV1alpha1ServiceIterator(ctx)
+	if err != nil {
+		return err
+	}
+
+	machines, err := machineController.List(ctx, &machineapi.MachineList{})
+	if err != nil {
+		return err
+	}
+
+	// Machines which have been removed or (re)started, whose entries in the
+	// status of the project are replaced.
+	replaced := map[string]bool{}
+	projectMachines := []metav1.ObjectMeta{}
+
+	for name := range opts.replicas {
+		service, err := project.LookupService(name)
+		if err != nil {
+			return err
+		}
+
+		wanted := map[string]bool{}
+		for i := 1; i <= compose.ServiceReplicas(service); i++ {
+			wanted[compose.ReplicaName(service, i)] = true
+		}
+
+		for _, machine := range machines.Items {
+			if !compose.IsReplicaOf(service, machine.Name) || wanted[machine.Name] {
+				continue
+			}
+
+			log.G(ctx).Infof("removing service %s...", machine.Name)
+
+			rmOpts := remove.RemoveOptions{
+				Platform: machine.Spec.Platform,
+			}
+
+			if err := rmOpts.Run(ctx, []string{machine.Name}); err != nil {
+				return err
+			}
+
+			replaced[machine.Name] = true
+		}
+
+		started, err := up.StartReplicas(ctx, machineController, project, service, machines, up.ServiceBuildOptions{})
+		if err != nil {
+			return err
+		}
+
+		for _, machine := range started {
+			replaced[machine.Name] = true
+		}
+
+		projectMachines = append(projectMachines, started...)
+	}
+
+	for _, machine := range embeddedProject.Status.Machines {
+		if !replaced[machine.Name] {
+			projectMachines = append(projectMachines, machine)
+		}
+	}
+
+	_, err = composeController.Update(ctx, &composeapi.Compose{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: project.Name,
+		},
+		Spec: embeddedProject.Spec,
+		Status: composeapi.ComposeStatus{
+			Machines: projectMachines,
+			Networks: embeddedProject.Status.Networks,
+		},
+	})
+
+	return err
+}
@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package up
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/compose-spec/compose-go/types"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	machineapi "kraftkit.sh/api/machine/v1alpha1"
+	"kraftkit.sh/compose"
+	"kraftkit.sh/config"
+	kraftutils "kraftkit.sh/internal/cli/kraft/utils"
+	"kraftkit.sh/log"
+)
+
+// startWave starts the services of the provided wave, none of which depend on
+// each other, using at most opts.Concurrency concurrent workers.  When
+// attached to a terminal, the progress is rendered as a process tree.  A
+// failure to start one service does not prevent the others of the wave from
+// being started; all failures are instead returned together, such that the
+// services of later waves are not started.
+func (opts *UpOptions) startWave(ctx context.Context, machineController machineapi.MachineService, project *compose.Project, wave types.Services, machines *machineapi.MachineList, buildOpts ServiceBuildOptions) ([]metav1.ObjectMeta, error) {
+	if opts.Concurrency <= 1 || len(wave) <= 1 {
+		var started []metav1.ObjectMeta
+
+		for _, service := range wave {
+			replicas, err := StartReplicas(ctx, machineController, project, service, machines, buildOpts)
+			if err != nil {
+				return nil, err
+			}
+
+			started = append(started, replicas...)
+		}
+
+		return started, nil
+	}
+
+	var started []metav1.ObjectMeta
+	var mu sync.Mutex
+
+	// The builds and machines started on behalf of each service log into its
+	// item of the process tree, or as plain lines, rather than rendering
+	// process trees of their own.
+	_, err := kraftutils.ForEachWithProgress(ctx, kraftutils.ProgressOptions[types.ServiceConfig]{
+		Verb:     "Starting",
+		Parallel: opts.Concurrency,
+		Name:     func(service types.ServiceConfig) string { return service.Name },
+		Done: func(ctx context.Context, service types.ServiceConfig, _ int, err error) {
+			if err == nil {
+				log.G(ctx).Infof("started service %s", service.Name)
+			}
+		},
+	}, wave, func(ctx context.Context, service types.ServiceConfig) error {
+		replicas, err := StartReplicas(withoutRenderer(ctx), machineController, project, service, machines, buildOpts)
+		if err != nil {
+			return fmt.Errorf("service %s: %w", service.Name, err)
+		}
+
+		mu.Lock()
+		started = append(started, replicas...)
+		mu.Unlock()
+
+		return nil
+	})
+
+	return started, err
+}
+
+// withoutRenderer returns a copy of the provided context whose configuration
+// uses the basic logger, such that the builds and machines started on behalf
+// of a service log into its item of the process tree rather than rendering
+// process trees of their own.
+func withoutRenderer(ctx context.Context) context.Context {
+	cfgm := *config.M[config.KraftKit](ctx)
+	cfg := *cfgm.Config
+	cfg.Log.Type = log.LoggerTypeToString(log.BASIC)
+	cfgm.Config = &cfg
+
+	return config.WithConfigManager(ctx, &cfgm)
+}
@@ -0,0 +1,159 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package up
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/compose-spec/compose-go/types"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	machineapi "kraftkit.sh/api/machine/v1alpha1"
+	"kraftkit.sh/compose"
+	"kraftkit.sh/log"
+)
+
+const (
+	// dependencyPollInterval is the interval at which the state of a dependency
+	// is checked whilst waiting for it.
+	dependencyPollInterval = 500 * time.Millisecond
+
+	// The defaults of the Compose specification for health checks.
+	defaultHealthCheckInterval = 30 * time.Second
+	defaultHealthCheckRetries  = 3
+)
+
+// waitForDependencies blocks until all dependencies of the provided service
+// which declare a `service_healthy` or `service_completed_successfully`
+// condition have satisfied it.
+func waitForDependencies(ctx context.Context, controller machineapi.MachineService, project *compose.Project, service types.ServiceConfig) error {
+	for name, dependency := range service.DependsOn {
+		switch dependency.Condition {
+		case types.ServiceConditionHealthy, types.ServiceConditionCompletedSuccessfully:
+		default:
+			continue
+		}
+
+		dep, err := project.LookupService(name)
+		if err != nil {
+			return err
+		}
+
+		log.G(ctx).
+			WithField("condition", dependency.Condition).
+			Infof("service %s waiting for %s...", service.Name, dep.Name)
+
+		if dependency.Condition == types.ServiceConditionHealthy {
+			err = waitForHealthy(ctx, controller, dep)
+		} else {
+			err = waitForCompletion(ctx, controller, dep)
+		}
+		if err != nil {
+			return fmt.Errorf("dependency %s of service %s: %w", dep.Name, service.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// waitForHealthy waits until the machine of the provided service is deemed
+// healthy.  Since health check commands cannot be executed inside of a
+// unikernel, a machine is considered healthy once it has been running for the
+// health check's start period.  The machine must become healthy within the
+// start period plus the health check's interval multiplied by its retries.
+func waitForHealthy(ctx context.Context, controller machineapi.MachineService, service types.ServiceConfig) error {
+	startPeriod := time.Duration(0)
+	interval := defaultHealthCheckInterval
+	retries := uint64(defaultHealthCheckRetries)
+
+	if hc := service.HealthCheck; hc != nil && !hc.Disable {
+		if hc.StartPeriod != nil {
+			startPeriod = time.Duration(*hc.StartPeriod)
+		}
+		if hc.Interval != nil {
+			interval = time.Duration(*hc.Interval)
+		}
+		if hc.Retries != nil {
+			retries = *hc.Retries
+		}
+	}
+
+	timeout := startPeriod + interval*time.Duration(retries)
+	deadline := time.Now().Add(timeout)
+
+	var runningSince time.Time
+
+	for {
+		machine, err := controller.Get(ctx, &machineapi.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: service.Name,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		switch machine.Status.State {
+		case machineapi.MachineStateRunning:
+			if runningSince.IsZero() {
+				runningSince = time.Now()
+			}
+			if time.Since(runningSince) >= startPeriod {
+				return nil
+			}
+
+		case machineapi.MachineStateExited, machineapi.MachineStateFailed:
+			return fmt.Errorf("machine is %s", machine.Status.State)
+
+		default:
+			runningSince = time.Time{}
+		}
+
+		if time.Now().After(deadline) {
+			return fmt.Errorf("did not become healthy within %s", timeout)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(dependencyPollInterval):
+		}
+	}
+}
+
+// waitForCompletion waits until the machine of the provided service has exited
+// successfully.
+func waitForCompletion(ctx context.Context, controller machineapi.MachineService, service types.ServiceConfig) error {
+	for {
+		machine, err := controller.Get(ctx, &machineapi.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: service.Name,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		switch machine.Status.State {
+		case machineapi.MachineStateExited:
+			if machine.Status.ExitCode != 0 {
+				return fmt.Errorf("exited with code %d", machine.Status.ExitCode)
+			}
+			return nil
+
+		case machineapi.MachineStateFailed:
+			return fmt.Errorf("machine failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(dependencyPollInterval):
+		}
+	}
+}
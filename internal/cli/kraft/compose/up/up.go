@@ -9,6 +9,8 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -25,6 +27,7 @@ import (
 	"kraftkit.sh/internal/cli/kraft/pkg/pull"
 	"kraftkit.sh/internal/cli/kraft/remove"
 	"kraftkit.sh/internal/cli/kraft/run"
+	kraftutils "kraftkit.sh/internal/cli/kraft/utils"
 	"kraftkit.sh/log"
 	"kraftkit.sh/machine/network"
 	"kraftkit.sh/packmanager"
@@ -38,7 +41,16 @@ import (
 )
 
 type UpOptions struct {
-	composefile string
+	Build       bool     `long:"build" usage:"Build the services with a build context before starting them, even if they are already built, replacing their running replicas"`
+	Concurrency int      `long:"concurrency" usage:"Number of services which do not depend on each other to start concurrently" default:"1"`
+	ForcePull   bool     `long:"force-pull" usage:"Force pulling packages before building the services"`
+	NoCache     bool     `long:"no-cache" usage:"Force a rebuild of the services even if existing intermediate artifacts already exist"`
+	Replicas    []string `long:"replicas" usage:"Override the number of replicas of a service in the form SERVICE=N"`
+
+	composefiles []string
+	profiles     []string
+	memory       map[string]int64
+	replicas     map[string]int
 }
 
 func NewCmd() *cobra.Command {
@@ -47,10 +59,42 @@ func NewCmd() *cobra.Command {
 		Use:     "up [FLAGS]",
 		Args:    cobra.NoArgs,
 		Aliases: []string{},
-		Long:    "Run a compose project.",
+		Long: heredoc.Doc(`
+			Run a compose project.
+
+			Services are started in the order implied by their depends_on
+			declarations.  Dependencies with the service_healthy condition are
+			considered healthy once their machine has been running for the health
+			check's start period.  With --concurrency, up to the given number of
+			services whose dependencies have all been started are built and started
+			at the same time.
+
+			The memory of each service is taken from its mem_limit or
+			deploy.resources.limits.memory and the number of machines to run from
+			its deploy.replicas.  Both can be overridden per service with the
+			--memory and --replicas flags.  Replicas after the first are named
+			after the service with a -2, -3, ... suffix.
+
+			Services without an image are built before they are started.  With
+			--build, services which have an image and a build context are rebuilt
+			and repackaged before they are started too.  Services which are already
+			running are left untouched.
+		`),
 		Example: heredoc.Doc(`
 			# Run a compose project
 			$ kraft compose up
+
+			# Run a compose project with 512MiB of memory for the web service
+			$ kraft compose up --memory web=512
+
+			# Run a compose project with three replicas of the web service
+			$ kraft compose up --replicas web=3
+
+			# Rebuild the services from scratch before running the compose project
+			$ kraft compose up --build --no-cache
+
+			# Start up to four independent services at a time
+			$ kraft compose up --concurrency 4
 		`),
 		Annotations: map[string]string{
 			cmdfactory.AnnotationHelpGroup: "compose",
@@ -60,6 +104,12 @@ func NewCmd() *cobra.Command {
 		panic(err)
 	}
 
+	cmd.Flags().StringArray(
+		"memory",
+		nil,
+		"Override the memory of a service in the form SERVICE=SIZE, "+kraftutils.SizeUsage,
+	)
+
 	return cmd
 }
 
@@ -71,11 +121,61 @@ func (opts *UpOptions) Pre(cmd *cobra.Command, _ []string) error {
 
 	cmd.SetContext(ctx)
 
-	if cmd.Flag("file").Changed {
-		opts.composefile = cmd.Flag("file").Value.String()
+	composefiles, err := cmd.Flags().GetStringSlice("file")
+	if err != nil {
+		return err
+	}
+
+	opts.composefiles = composefiles
+
+	profiles, err := cmd.Flags().GetStringSlice("profile")
+	if err != nil {
+		return err
 	}
 
-	log.G(cmd.Context()).WithField("composefile", opts.composefile).Debug("using")
+	opts.profiles = profiles
+
+	if opts.Concurrency < 1 {
+		return fmt.Errorf("concurrency must be at least 1")
+	}
+
+	memory, err := cmd.Flags().GetStringArray("memory")
+	if err != nil {
+		return err
+	}
+
+	// Sizes are parsed as by `kraft cloud deploy --memory`.
+	opts.memory = map[string]int64{}
+	for _, override := range memory {
+		name, value, ok := strings.Cut(override, "=")
+		if !ok {
+			return fmt.Errorf("invalid memory override '%s': expected SERVICE=SIZE", override)
+		}
+
+		mb, err := kraftutils.ParseSizeMB(value)
+		if err != nil {
+			return fmt.Errorf("invalid memory override '%s': %w", override, err)
+		}
+
+		opts.memory[name] = int64(mb) * 1024 * 1024
+	}
+
+	opts.replicas = map[string]int{}
+	for _, override := range opts.Replicas {
+		name, value, ok := strings.Cut(override, "=")
+		if !ok {
+			return fmt.Errorf("invalid replicas override '%s': expected SERVICE=N", override)
+		}
+
+		replicas, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid replicas override '%s': %w", override, err)
+		}
+
+		opts.replicas[name] = replicas
+	}
+
+	log.G(cmd.Context()).WithField("composefiles", opts.composefiles).Debug("using")
 	return nil
 }
 
@@ -85,7 +185,7 @@ func (opts *UpOptions) Run(ctx context.Context, args []string) error {
 		return err
 	}
 
-	project, err := compose.NewProjectFromComposeFile(ctx, workdir, opts.composefile)
+	project, err := compose.NewProjectFromComposeFiles(ctx, workdir, opts.composefiles, compose.WithProfiles(opts.profiles...))
 	if err != nil {
 		return err
 	}
@@ -94,6 +194,25 @@ func (opts *UpOptions) Run(ctx context.Context, args []string) error {
 		return err
 	}
 
+	for name, bytes := range opts.memory {
+		if err := project.SetServiceMemory(name, bytes); err != nil {
+			return err
+		}
+	}
+
+	for name, replicas := range opts.replicas {
+		if err := project.SetServiceReplicas(name, replicas); err != nil {
+			return err
+		}
+	}
+
+	// Determine the order in which services are started early, such that any
+	// dependency cycles are reported before anything is created.
+	waves, err := project.ServiceWaves()
+	if err != nil {
+		return err
+	}
+
 	if err := project.AssignIPs(ctx); err != nil {
 		return err
 	}
@@ -199,47 +318,17 @@ func (opts *UpOptions) Run(ctx context.Context, args []string) error {
 		return err
 	}
 
-	for _, service := range project.Services {
-		alreadyRunning := false
-		for _, machine := range machines.Items {
-			if service.Name == machine.Name {
-				if machine.Status.State == machineapi.MachineStateRunning {
-					alreadyRunning = true
-				} else {
-					rmOpts := remove.RemoveOptions{
-						Platform: machine.Spec.Platform,
-					}
-
-					if err := rmOpts.Run(ctx, []string{service.Name}); err != nil {
-						return err
-					}
-				}
-				break
-			}
-		}
-		if alreadyRunning {
-			continue
-		}
-		if service.Image == "" {
-			if err := buildService(ctx, service); err != nil {
-				return err
-			}
-		} else {
-			if err := ensureServiceIsPackaged(ctx, service); err != nil {
-				return err
-			}
-		}
-
-		if err := runService(ctx, project, service); err != nil {
-			log.G(ctx).WithError(err).Errorf("failed to run service %s", service.Name)
-		}
+	buildOpts := ServiceBuildOptions{
+		Rebuild:   opts.Build,
+		ForcePull: opts.ForcePull,
+		NoCache:   opts.NoCache,
+	}
 
-		if machine, err := machineController.Get(ctx, &machineapi.Machine{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: service.Name,
-			},
-		}); err == nil && machine.Status.State == machineapi.MachineStateRunning {
-			projectMachines = append(projectMachines, machine.ObjectMeta)
+	for _, wave := range waves {
+		started, err := opts.startWave(ctx, machineController, project, wave, machines, buildOpts)
+		projectMachines = mergeMachines(projectMachines, started)
+		if err != nil {
+			return err
 		}
 	}
 
@@ -248,8 +337,9 @@ func (opts *UpOptions) Run(ctx context.Context, args []string) error {
 			Name: project.Name,
 		},
 		Spec: composeapi.ComposeSpec{
-			Composefile: project.ComposeFiles[0],
-			Workdir:     project.WorkingDir,
+			Composefile:  project.ComposeFiles[0],
+			Composefiles: project.ComposeFiles,
+			Workdir:      project.WorkingDir,
 		},
 		Status: composeapi.ComposeStatus{
 			Machines: projectMachines,
@@ -263,19 +353,22 @@ func (opts *UpOptions) Run(ctx context.Context, args []string) error {
 
 	longestName := 0
 	for _, service := range project.Services {
-		if len(service.Name) > longestName {
-			longestName = len(service.Name)
+		name := compose.ReplicaName(service, compose.ServiceReplicas(service))
+		if len(name) > longestName {
+			longestName = len(name)
 		}
 	}
 	for i := range project.Services {
-		wg.Add(1)
-		go func(service types.ServiceConfig) {
-			defer wg.Done()
+		for replica := 1; replica <= compose.ServiceReplicas(project.Services[i]); replica++ {
+			wg.Add(1)
+			go func(service types.ServiceConfig, name string) {
+				defer wg.Done()
 
-			if err := logService(ctx, service, longestName); err != nil {
-				log.G(ctx).WithError(err).Errorf("failed to log service %s", service.Name)
-			}
-		}(project.Services[i])
+				if err := logService(ctx, service, name, longestName); err != nil {
+					log.G(ctx).WithError(err).Errorf("failed to log service %s", name)
+				}
+			}(project.Services[i], compose.ReplicaName(project.Services[i], replica))
+		}
 	}
 
 	wg.Wait()
@@ -283,6 +376,130 @@ func (opts *UpOptions) Run(ctx context.Context, args []string) error {
 	return nil
 }
 
+// mergeMachines adds the started machines to the machines of the project,
+// replacing those of the same name which were replaced by a rebuild.
+func mergeMachines(machines, started []metav1.ObjectMeta) []metav1.ObjectMeta {
+	merged := make([]metav1.ObjectMeta, 0, len(machines)+len(started))
+	for _, machine := range machines {
+		if !slices.ContainsFunc(started, func(s metav1.ObjectMeta) bool {
+			return s.Name == machine.Name
+		}) {
+			merged = append(merged, machine)
+		}
+	}
+
+	return append(merged, started...)
+}
+
+// ServiceBuildOptions configures how StartReplicas builds the services.
+type ServiceBuildOptions struct {
+	// Rebuild, and repackage, services with a build context even if they were
+	// already built or packaged.
+	Rebuild bool
+
+	// ForcePull and NoCache are passed on to each build.
+	ForcePull bool
+	NoCache   bool
+}
+
+// StartReplicas starts the replicas of the provided service which are not
+// already running amongst the provided machines, building or pulling the
+// service first if necessary.  When the service is rebuilt, its running
+// replicas are replaced once the build succeeded.  The machines which were
+// started are returned.
+func StartReplicas(ctx context.Context, machineController machineapi.MachineService, project *compose.Project, service types.ServiceConfig, machines *machineapi.MachineList, buildOpts ServiceBuildOptions) ([]metav1.ObjectMeta, error) {
+	rebuild := buildOpts.Rebuild && service.Build != nil
+
+	// Only start the replicas which are not already running, unless they are
+	// replaced by a rebuild.
+	var pending []string
+	var running []machineapi.Machine
+	for i := 1; i <= compose.ServiceReplicas(service); i++ {
+		name := compose.ReplicaName(service, i)
+
+		alreadyRunning := false
+		for _, machine := range machines.Items {
+			if name == machine.Name {
+				if machine.Status.State == machineapi.MachineStateRunning {
+					alreadyRunning = true
+					running = append(running, machine)
+				} else {
+					rmOpts := remove.RemoveOptions{
+						Platform: machine.Spec.Platform,
+					}
+
+					if err := rmOpts.Run(ctx, []string{name}); err != nil {
+						return nil, err
+					}
+				}
+				break
+			}
+		}
+		if !alreadyRunning {
+			pending = append(pending, name)
+		}
+	}
+	if len(pending) == 0 && (!rebuild || len(running) == 0) {
+		return nil, nil
+	}
+	if service.Image == "" {
+		if err := buildService(ctx, service, buildOpts); err != nil {
+			return nil, err
+		}
+	} else if rebuild {
+		if err := buildService(ctx, service, buildOpts); err != nil {
+			return nil, err
+		}
+
+		if err := pkgService(ctx, service); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := ensureServiceIsPackaged(ctx, service, buildOpts); err != nil {
+			return nil, err
+		}
+	}
+
+	// The running replicas are only removed once the service was rebuilt, such
+	// that a failed build leaves them untouched.
+	if rebuild {
+		for _, machine := range running {
+			log.G(ctx).Infof("replacing %s with the rebuilt service", machine.Name)
+
+			rmOpts := remove.RemoveOptions{
+				Platform: machine.Spec.Platform,
+			}
+
+			if err := rmOpts.Run(ctx, []string{machine.Name}); err != nil {
+				return nil, err
+			}
+
+			pending = append(pending, machine.Name)
+		}
+	}
+
+	if err := waitForDependencies(ctx, machineController, project, service); err != nil {
+		return nil, err
+	}
+
+	var started []metav1.ObjectMeta
+	for _, name := range pending {
+		if err := runService(ctx, project, service, name); err != nil {
+			log.G(ctx).WithError(err).Errorf("failed to run service %s", name)
+		}
+
+		if machine, err := machineController.Get(ctx, &machineapi.Machine{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+		}); err == nil && machine.Status.State == machineapi.MachineStateRunning {
+			started = append(started, machine.ObjectMeta)
+		}
+	}
+
+	return started, nil
+}
+
 func platArchFromService(service types.ServiceConfig) (string, string, error) {
 	// The service platform should be in the form <platform>/<arch>
 
@@ -295,7 +512,7 @@ func platArchFromService(service types.ServiceConfig) (string, string, error) {
 	return parts[0], parts[1], nil
 }
 
-func ensureServiceIsPackaged(ctx context.Context, service types.ServiceConfig) error {
+func ensureServiceIsPackaged(ctx context.Context, service types.ServiceConfig, buildOpts ServiceBuildOptions) error {
 	plat, arch, err := platArchFromService(service)
 	if err != nil {
 		return err
@@ -350,14 +567,14 @@ func ensureServiceIsPackaged(ctx context.Context, service types.ServiceConfig) e
 	}
 
 	// Otherwise, we need to build and package it
-	if err := buildService(ctx, service); err != nil {
+	if err := buildService(ctx, service, buildOpts); err != nil {
 		return err
 	}
 
 	return pkgService(ctx, service)
 }
 
-func buildService(ctx context.Context, service types.ServiceConfig) error {
+func buildService(ctx context.Context, service types.ServiceConfig, buildOpts ServiceBuildOptions) error {
 	if service.Build == nil {
 		return fmt.Errorf("service %s has no build context", service.Name)
 	}
@@ -369,7 +586,12 @@ func buildService(ctx context.Context, service types.ServiceConfig) error {
 
 	log.G(ctx).Infof("building service %s...", service.Name)
 
-	buildOptions := build.BuildOptions{Platform: plat, Architecture: arch}
+	buildOptions := build.BuildOptions{
+		Architecture: arch,
+		ForcePull:    buildOpts.ForcePull,
+		NoCache:      buildOpts.NoCache,
+		Platform:     plat,
+	}
 
 	return buildOptions.Run(ctx, []string{service.Build.Context})
 }
@@ -393,29 +615,40 @@ func pkgService(ctx context.Context, service types.ServiceConfig) error {
 	return pkgOptions.Run(ctx, []string{service.Build.Context})
 }
 
-func runService(ctx context.Context, project *compose.Project, service types.ServiceConfig) error {
+// runService runs the machine with the provided name as a replica of the
+// service.
+func runService(ctx context.Context, project *compose.Project, service types.ServiceConfig, machineName string) error {
 	// The service should be packaged at this point
 	plat, arch, err := platArchFromService(service)
 	if err != nil {
 		return err
 	}
 
-	log.G(ctx).Infof("running service %s...", service.Name)
+	log.G(ctx).Infof("running service %s...", machineName)
 
 	networks := []string{}
 	for name, network := range service.Networks {
-		networkArg := fmt.Sprintf("%s:%s", project.Networks[name].Name, network.Ipv4Address)
+		// Only the first replica can be assigned the address of the service;
+		// any further replicas are assigned a free address instead.
+		networkArg := project.Networks[name].Name
+		if machineName == service.Name {
+			networkArg = fmt.Sprintf("%s:%s", networkArg, network.Ipv4Address)
+		}
 		networks = append(networks, networkArg)
 	}
 
 	runOptions := run.RunOptions{
 		Architecture: arch,
 		Detach:       true,
-		Name:         service.Name,
+		Name:         machineName,
 		Networks:     networks,
 		Platform:     plat,
 	}
 
+	if memory := compose.ServiceMemory(service); memory > 0 {
+		runOptions.Memory = strconv.FormatInt(memory, 10)
+	}
+
 	if service.Image != "" {
 		return runOptions.Run(ctx, []string{service.Image})
 	}
@@ -423,8 +656,8 @@ func runService(ctx context.Context, project *compose.Project, service types.Ser
 	return runOptions.Run(ctx, []string{service.Build.Context})
 }
 
-func logService(ctx context.Context, service types.ServiceConfig, prefixLength int) error {
-	prefix := service.Name + strings.Repeat(" ", prefixLength-len(service.Name))
+func logService(ctx context.Context, service types.ServiceConfig, machineName string, prefixLength int) error {
+	prefix := machineName + strings.Repeat(" ", prefixLength-len(machineName))
 
 	plat, _, err := platArchFromService(service)
 	if err != nil {
@@ -435,7 +668,8 @@ func logService(ctx context.Context, service types.ServiceConfig, prefixLength i
 		Follow:   true,
 		Platform: plat,
 		Prefix:   prefix,
+		Tail:     -1,
 	}
 
-	return logOptions.Run(ctx, []string{service.Name})
+	return logOptions.Run(ctx, []string{machineName})
 }
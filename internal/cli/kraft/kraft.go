@@ -121,6 +121,29 @@ func NewCmd() *cobra.Command {
 	return cmd
 }
 
+// PersistentPre disables all styling when requested via --no-color or when
+// the color scheme would not be used anyway, i.e. NO_COLOR is set or standard
+// output is not a terminal, such that commands which consult the configuration
+// rather than the color scheme also print clean output.  This happens only once
+// all flags of the invoked subcommand have been parsed and before any of them
+// renders output.
+func (k *KraftOptions) PersistentPre(cmd *cobra.Command, _ []string) error {
+	ctx := cmd.Context()
+
+	if !config.G[config.KraftKit](ctx).NoColor && iostreams.G(ctx).ColorEnabled() {
+		return nil
+	}
+
+	iostreams.G(ctx).SetColorEnabled(false)
+	config.G[config.KraftKit](ctx).NoColor = true
+
+	if formatter, ok := log.G(ctx).Logger.Formatter.(*log.TextFormatter); ok {
+		formatter.DisableColors = true
+	}
+
+	return nil
+}
+
 func (k *KraftOptions) Run(_ context.Context, args []string) error {
 	return pflag.ErrHelp
 }
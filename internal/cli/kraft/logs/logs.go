@@ -5,6 +5,7 @@
 package logs
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"fmt"
@@ -27,6 +28,7 @@ type LogOptions struct {
 	Platform   string `noattribute:"true"`
 	Prefix     string `long:"prefix" usage:"Prefix each log line with the given string"`
 	PrefixName bool   `long:"prefix-name" usage:"Prefix each log line with the machine name"`
+	Tail       int    `long:"tail" short:"n" usage:"Lines of recent logs to display" default:"-1"`
 }
 
 func NewCmd() *cobra.Command {
@@ -50,6 +52,9 @@ func NewCmd() *cobra.Command {
 
 			# Fetch the logs of a unikernel and prefix each line with the given string
 			$ kraft logs --prefix "log: "
+
+			# Fetch the last 10 lines of the logs of a unikernel
+			$ kraft logs --tail 10 my-machine
 		`),
 		Annotations: map[string]string{
 			cmdfactory.AnnotationHelpGroup: "run",
@@ -136,14 +141,43 @@ func (opts *LogOptions) Run(ctx context.Context, args []string) error {
 		opts.Prefix = machine.Name
 	}
 
+	consumer, err := NewColorfulConsumer(iostreams.G(ctx), !config.G[config.KraftKit](ctx).NoColor, opts.Prefix)
+	if err != nil {
+		return err
+	}
+
 	if opts.Follow && machine.Status.State == machineapi.MachineStateRunning {
-		consumer, err := NewColorfulConsumer(iostreams.G(ctx), !config.G[config.KraftKit](ctx).NoColor, opts.Prefix)
+		var logConsumer LogConsumer = consumer
+
+		if opts.Tail >= 0 {
+			lines, err := logLines(machine.Status.LogFile)
+			if err != nil {
+				return err
+			}
+
+			logConsumer = &skipConsumer{
+				consumer: consumer,
+				skip:     max(0, len(lines)-opts.Tail),
+			}
+		}
+
+		if err = FollowLogs(ctx, machine, controller, logConsumer); err != nil {
+			return err
+		}
+	} else if opts.Tail >= 0 || opts.Prefix != "" {
+		lines, err := logLines(machine.Status.LogFile)
 		if err != nil {
 			return err
 		}
 
-		if err = FollowLogs(ctx, machine, controller, consumer); err != nil {
-			return err
+		if opts.Tail >= 0 && len(lines) > opts.Tail {
+			lines = lines[len(lines)-opts.Tail:]
+		}
+
+		for _, line := range lines {
+			if err := consumer.Consume(line); err != nil {
+				return err
+			}
 		}
 	} else {
 		fd, err := os.Open(machine.Status.LogFile)
@@ -159,6 +193,50 @@ func (opts *LogOptions) Run(ctx context.Context, args []string) error {
 	return nil
 }
 
+// logLines returns all the lines, including their line endings, which are
+// present in the provided log file.
+func logLines(logFile string) ([]string, error) {
+	fd, err := os.Open(logFile)
+	if err != nil {
+		return nil, err
+	}
+
+	defer fd.Close()
+
+	var lines []string
+
+	reader := bufio.NewReader(fd)
+	for {
+		line, err := reader.ReadString('\n')
+		if len(line) > 0 {
+			lines = append(lines, line)
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	return lines, nil
+}
+
+// skipConsumer is a LogConsumer which discards the first lines it receives
+// before passing all subsequent lines on to the wrapped consumer.
+type skipConsumer struct {
+	consumer LogConsumer
+	skip     int
+}
+
+func (c *skipConsumer) Consume(line string) error {
+	if c.skip > 0 {
+		c.skip--
+		return nil
+	}
+
+	return c.consumer.Consume(line)
+}
+
 // FollowLogs tracks the logs generated by a machine and prints them to the context out stream.
 func FollowLogs(ctx context.Context, machine *machineapi.Machine, controller machineapi.MachineService, consumer LogConsumer) error {
 	ctx, cancel := context.WithCancel(ctx)
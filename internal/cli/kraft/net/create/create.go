@@ -11,7 +11,6 @@ import (
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/spf13/cobra"
-	"github.com/vishvananda/netlink"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	networkapi "kraftkit.sh/api/network/v1alpha1"
@@ -22,7 +21,9 @@ import (
 
 type CreateOptions struct {
 	Driver  string `noattribute:"true"`
-	Network string `long:"network" short:"n" usage:"Set the gateway IP address and the subnet of the network in CIDR format."`
+	Gateway string `long:"gateway" usage:"Set the gateway IP address of the network, which must be within --subnet (default is its first address)"`
+	Network string `long:"network" short:"n" usage:"Set the gateway IP address and the subnet of the network in CIDR format, or the subnet alone to use its first address as the gateway."`
+	Subnet  string `long:"subnet" usage:"Set the subnet of the network in CIDR format, e.g. 10.0.0.0/24"`
 }
 
 // Create a new local machine network.
@@ -44,6 +45,18 @@ func NewCmd() *cobra.Command {
 		Example: heredoc.Doc(`
 			# Create a new machine network
 			$ kraft network create my-network --network 133.37.0.1/12
+
+			# Create a new machine network whose gateway is the first address of the subnet
+			$ kraft network create my-network --network 10.0.0.0/24
+
+			# Create a new machine network given a netmask instead of a prefix length
+			$ kraft network create my-network --network 10.0.0.1/255.255.255.0
+
+			# Create a new machine network given its subnet and gateway separately
+			$ kraft network create my-network --subnet 10.0.0.0/24 --gateway 10.0.0.254
+
+			# Create a new machine network with a specific driver
+			$ kraft network --driver bridge create my-network --subnet 10.0.0.0/24
 		`),
 		Annotations: map[string]string{
 			cmdfactory.AnnotationHelpGroup: "net",
@@ -59,6 +72,14 @@ func NewCmd() *cobra.Command {
 func (opts *CreateOptions) Pre(cmd *cobra.Command, _ []string) error {
 	opts.Driver = cmd.Flag("driver").Value.String()
 
+	if opts.Network != "" && (opts.Subnet != "" || opts.Gateway != "") {
+		return fmt.Errorf("cannot use --network together with --subnet or --gateway")
+	}
+
+	if opts.Gateway != "" && opts.Subnet == "" {
+		return fmt.Errorf("cannot use --gateway without --subnet")
+	}
+
 	return nil
 }
 
@@ -75,7 +96,7 @@ func (opts *CreateOptions) Run(ctx context.Context, args []string) error {
 		return err
 	}
 
-	if opts.Network == "" {
+	if opts.Network == "" && opts.Subnet == "" {
 		existingNetworks, err := controller.List(ctx, &networkapi.NetworkList{})
 		if err != nil {
 			return err
@@ -89,7 +110,12 @@ func (opts *CreateOptions) Run(ctx context.Context, args []string) error {
 		opts.Network = freeNetwork.String()
 	}
 
-	addr, err := netlink.ParseAddr(opts.Network)
+	var addr *net.IPNet
+	if opts.Subnet != "" {
+		addr, err = network.ParseSubnet(opts.Subnet, opts.Gateway)
+	} else {
+		addr, err = network.ParseCIDR(opts.Network)
+	}
 	if err != nil {
 		return err
 	}
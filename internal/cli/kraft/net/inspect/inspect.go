@@ -8,6 +8,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/spf13/cobra"
@@ -15,12 +16,15 @@ import (
 
 	networkapi "kraftkit.sh/api/network/v1alpha1"
 	"kraftkit.sh/cmdfactory"
+	"kraftkit.sh/internal/tableprinter"
 	"kraftkit.sh/iostreams"
+	"kraftkit.sh/log"
 	"kraftkit.sh/machine/network"
 )
 
 type InspectOptions struct {
 	Driver string `noattribute:"true"`
+	Output string `long:"output" short:"o" usage:"Set output format. Options: table,yaml,json,list" default:"json"`
 }
 
 func NewCmd() *cobra.Command {
@@ -33,6 +37,9 @@ func NewCmd() *cobra.Command {
 		Example: heredoc.Doc(`
 			# Inspect a machine network
 			$ kraft network inspect my-network
+
+			# Inspect a machine network and its interfaces as a table
+			$ kraft network inspect -o table my-network
 		`),
 		Annotations: map[string]string{
 			cmdfactory.AnnotationHelpGroup: "net",
@@ -72,12 +79,75 @@ func (opts *InspectOptions) Run(ctx context.Context, args []string) error {
 		return err
 	}
 
-	ret, err := json.Marshal(network)
+	if opts.Output == "json" {
+		ret, err := json.Marshal(network)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(iostreams.G(ctx).Out, "%s\n", ret)
+
+		return nil
+	}
+
+	err = iostreams.G(ctx).StartPager()
+	if err != nil {
+		log.G(ctx).Errorf("error starting pager: %v", err)
+	}
+
+	defer iostreams.G(ctx).StopPager()
+
+	cs := iostreams.G(ctx).ColorScheme()
+
+	table, err := tableprinter.NewTablePrinter(ctx,
+		tableprinter.WithMaxWidth(iostreams.G(ctx).TerminalWidth()),
+		tableprinter.WithOutputFormatFromString(opts.Output),
+	)
 	if err != nil {
 		return err
 	}
 
-	fmt.Fprintf(iostreams.G(ctx).Out, "%s\n", ret)
+	addr := &net.IPNet{
+		IP:   net.ParseIP(network.Spec.Gateway),
+		Mask: net.IPMask(net.ParseIP(network.Spec.Netmask)),
+	}
+
+	// Header row
+	table.AddField("ID", cs.Bold)
+	table.AddField("NAME", cs.Bold)
+	table.AddField("DRIVER", cs.Bold)
+	table.AddField("BRIDGE", cs.Bold)
+	table.AddField("NETWORK", cs.Bold)
+	table.AddField("GATEWAY", cs.Bold)
+	table.AddField("NETMASK", cs.Bold)
+	table.AddField("STATUS", cs.Bold)
+	table.AddField("INTERFACE", cs.Bold)
+	table.AddField("MAC", cs.Bold)
+	table.AddField("ADDRESS", cs.Bold)
+	table.AddField("HOSTNAME", cs.Bold)
+	table.EndRow()
 
-	return nil
+	interfaces := network.Spec.Interfaces
+	if len(interfaces) == 0 {
+		// Always output at least one row for the network itself.
+		interfaces = []networkapi.NetworkInterfaceTemplateSpec{{}}
+	}
+
+	for _, iface := range interfaces {
+		table.AddField(string(network.UID), nil)
+		table.AddField(network.Name, nil)
+		table.AddField(opts.Driver, nil)
+		table.AddField(network.Spec.IfName, nil)
+		table.AddField(addr.String(), nil)
+		table.AddField(network.Spec.Gateway, nil)
+		table.AddField(network.Spec.Netmask, nil)
+		table.AddField(network.Status.State.String(), nil)
+		table.AddField(iface.Spec.IfName, nil)
+		table.AddField(iface.Spec.MacAddress, nil)
+		table.AddField(iface.Spec.CIDR, nil)
+		table.AddField(iface.Spec.Hostname, nil)
+		table.EndRow()
+	}
+
+	return table.Render(iostreams.G(ctx).Out)
 }
@@ -5,25 +5,74 @@
 package list
 
 import (
+	"bytes"
+	"cmp"
 	"context"
 	"fmt"
-	"net"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/spf13/cobra"
 
+	machineapi "kraftkit.sh/api/machine/v1alpha1"
 	networkapi "kraftkit.sh/api/network/v1alpha1"
 	"kraftkit.sh/cmdfactory"
+	"kraftkit.sh/internal/cli/kraft/utils"
 	"kraftkit.sh/internal/tableprinter"
 	"kraftkit.sh/iostreams"
 	"kraftkit.sh/log"
 	"kraftkit.sh/machine/network"
+	mplatform "kraftkit.sh/machine/platform"
 )
 
 type ListOptions struct {
-	Driver string `noattribute:"true"`
-	Long   bool   `long:"long" short:"l" usage:"Show more information"`
-	Output string `long:"output" short:"o" usage:"Set output format. Options: table,yaml,json,list" default:"table"`
+	Columns  []string      `long:"columns" usage:"Only show the given comma-separated columns, in the given order"`
+	Driver   string        `noattribute:"true"`
+	Filter   []string      `long:"filter" short:"f" usage:"Filter output based on conditions provided (name=, status=, driver=)"`
+	Format   string        `long:"format" usage:"Set the notation of the network. Options: cidr,netmask" default:"cidr"`
+	Interval time.Duration `long:"interval" usage:"Interval between refreshes when using --watch (ms/s/m/h)" default:"2000000000"`
+	Long     bool          `long:"long" short:"l" usage:"Show more information"`
+	Output   string        `long:"output" short:"o" usage:"Set output format. Options: table,wide,yaml,json,jsonl,list" default:"table"`
+	Quiet    bool          `long:"quiet" short:"q" usage:"Only display network names, one per line"`
+	Since    string        `long:"since" usage:"Only show networks created after the given RFC3339 timestamp or relative duration (e.g. 10m)"`
+	Sort     string        `long:"sort" usage:"Sort the networks by a field in the form FIELD[:asc|desc] (name, network, driver, inuse, status)"`
+	Until    string        `long:"until" usage:"Only show networks created before the given RFC3339 timestamp or relative duration (e.g. 10m)"`
+	Watch    bool          `long:"watch" short:"w" usage:"Refresh the list in place until interrupted"`
+
+	filters   [][2]string
+	since     time.Time
+	sortOrder *tableprinter.SortOrder
+	until     time.Time
+}
+
+// filterKeys are the supported predicates of the `--filter` flag.
+var filterKeys = []string{"name", "status", "driver"}
+
+type netTable struct {
+	id      string
+	name    string
+	network string
+	driver  string
+	inUse   int
+	status  networkapi.NetworkState
+	created string
+}
+
+// sortFields are the supported fields of the `--sort` flag.
+var sortFields = map[string]func(a, b netTable) int{
+	"name":    func(a, b netTable) int { return cmp.Compare(a.name, b.name) },
+	"network": func(a, b netTable) int { return cmp.Compare(a.network, b.network) },
+	"driver":  func(a, b netTable) int { return cmp.Compare(a.driver, b.driver) },
+	"inuse":   func(a, b netTable) int { return cmp.Compare(a.inUse, b.inUse) },
+	"status":  func(a, b netTable) int { return cmp.Compare(a.status.String(), b.status.String()) },
 }
 
 func NewCmd() *cobra.Command {
@@ -44,6 +93,30 @@ func NewCmd() *cobra.Command {
 
 			# List all machine networks with all information
 			$ kraft network list -l
+
+			# List all machine networks with every available field, untruncated
+			$ kraft network list -o wide
+
+			# List only the machine networks of the bridge driver
+			$ kraft network list --driver bridge
+
+			# List only the machine networks which are up
+			$ kraft network list --filter status=up
+
+			# List all machine networks showing their gateway and netmask
+			$ kraft network list --format netmask
+
+			# List all machine networks sorted by name in descending order
+			$ kraft network list --sort name:desc
+
+			# List the machine networks created within the last hour
+			$ kraft network list --since 1h
+
+			# Remove all machine networks which are down
+			$ kraft network list --filter status=down -q | xargs -n1 kraft network remove
+
+			# Watch the machine networks, refreshing every 5 seconds
+			$ kraft network list --watch --interval 5s
 		`),
 		Annotations: map[string]string{
 			cmdfactory.AnnotationHelpGroup: "net",
@@ -57,59 +130,247 @@ func NewCmd() *cobra.Command {
 }
 
 func (opts *ListOptions) Pre(cmd *cobra.Command, _ []string) error {
-	opts.Driver = cmd.Flag("driver").Value.String()
-	return nil
-}
+	// Only use a specific driver if one has been explicitly requested,
+	// otherwise list the networks of all available drivers.
+	if cmd.Flag("driver").Changed {
+		opts.Driver = cmd.Flag("driver").Value.String()
+	}
 
-func (opts *ListOptions) Run(ctx context.Context, _ []string) error {
-	var err error
+	if opts.Format != "cidr" && opts.Format != "netmask" {
+		return fmt.Errorf("invalid format '%s': expected one of cidr, netmask", opts.Format)
+	}
+
+	for _, filter := range opts.Filter {
+		key, value, ok := strings.Cut(filter, "=")
+		if !ok || !slices.Contains(filterKeys, key) {
+			return fmt.Errorf("invalid filter '%s': expected KEY=VALUE where KEY is one of %v", filter, filterKeys)
+		}
 
-	strategy, ok := network.Strategies()[opts.Driver]
-	if !ok {
-		return fmt.Errorf("unsupported network driver strategy: %s", opts.Driver)
+		opts.filters = append(opts.filters, [2]string{key, value})
 	}
 
-	controller, err := strategy.NewNetworkV1alpha1(ctx)
-	if err != nil {
-		return err
+	if opts.Watch && opts.Interval < time.Millisecond {
+		return fmt.Errorf("watch interval must be at least 1ms")
 	}
 
-	networks, err := controller.List(ctx, &networkapi.NetworkList{})
+	var err error
+	opts.since, opts.until, err = utils.ParseTimeRange(opts.Since, opts.Until, time.Now())
 	if err != nil {
 		return err
 	}
 
-	type netTable struct {
-		id      string
-		name    string
-		network string
+	opts.sortOrder, err = tableprinter.ParseSortOrder(opts.Sort, sortFields)
+
+	return err
+}
+
+// matches returns whether the provided network satisfies all the filters
+// provided via `--filter`, and was created within `--since` and `--until`.
+func (opts *ListOptions) matches(driver string, network networkapi.Network) bool {
+	for _, filter := range opts.filters {
+		key, value := filter[0], filter[1]
+		switch key {
+		case "name":
+			if network.Name != value {
+				return false
+			}
+		case "status":
+			if network.Status.State.String() != value {
+				return false
+			}
+		case "driver":
+			if driver != value {
+				return false
+			}
+		}
+	}
+
+	if !opts.since.IsZero() || !opts.until.IsZero() {
+		if network.CreationTimestamp.IsZero() || !utils.InTimeRange(network.CreationTimestamp.Time, opts.since, opts.until) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (opts *ListOptions) Run(ctx context.Context, _ []string) error {
+	ctx = tableprinter.WithColumnsInContext(ctx, opts.Columns)
+
+	if !opts.Watch {
+		return opts.list(ctx, iostreams.G(ctx).Out, true)
+	}
+
+	// Re-rendering in place only makes sense for output which is meant to be
+	// read by a person.
+	if !iostreams.G(ctx).IsStdoutTTY() || opts.Quiet || (opts.Output != "table" && opts.Output != "wide" && opts.Output != "list") {
+		log.G(ctx).Debug("not watching as the output is not interactive")
+		return opts.list(ctx, iostreams.G(ctx).Out, true)
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Clear the screen only once, such that subsequent renders overwrite the
+	// previous one in place rather than blanking the terminal in between.
+	fmt.Fprint(iostreams.G(ctx).Out, "\033[H\033[2J")
+
+	for {
+		var frame bytes.Buffer
+		fmt.Fprintf(&frame, "Every %s: kraft network list (%s)\n\n", opts.Interval, time.Now().Format(time.TimeOnly))
+
+		if err := opts.list(ctx, &frame, false); err != nil {
+			return err
+		}
+
+		// Render the complete frame at once: move the cursor home, clear what
+		// remains of each overwritten line and finally everything below the
+		// frame, e.g. rows of networks which have since been removed.
+		fmt.Fprint(iostreams.G(ctx).Out, "\033[H"+
+			strings.ReplaceAll(frame.String(), "\n", "\033[K\n")+
+			"\033[J",
+		)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(opts.Interval):
+		}
+	}
+}
+
+// list lists the networks once to the provided writer, optionally through the
+// pager.
+func (opts *ListOptions) list(ctx context.Context, out io.Writer, pager bool) error {
+	var err error
+
+	// The wide output format shows every available field, including those
+	// otherwise only shown with --long.
+	wide := opts.Output == string(tableprinter.OutputFormatWide)
+	long := opts.Long || wide
+
+	drivers := []string{opts.Driver}
+	if opts.Driver == "" {
+		drivers = network.DriverNames()
+		sort.Strings(drivers)
+	}
+
+	type driverNetwork struct {
 		driver  string
-		status  networkapi.NetworkState
+		network networkapi.Network
+	}
+
+	var networks []driverNetwork
+
+	for _, driver := range drivers {
+		strategy, ok := network.Strategies()[driver]
+		if !ok {
+			return fmt.Errorf("unsupported network driver strategy: %s", driver)
+		}
+
+		controller, err := strategy.NewNetworkV1alpha1(ctx)
+		if err != nil && opts.Driver == "" {
+			log.G(ctx).
+				WithField("driver", driver).
+				Debugf("skipping network driver: %v", err)
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		list, err := controller.List(ctx, &networkapi.NetworkList{})
+		if err != nil && opts.Driver == "" {
+			log.G(ctx).
+				WithField("driver", driver).
+				Debugf("could not list networks: %v", err)
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		for _, network := range list.Items {
+			if !opts.matches(driver, network) {
+				continue
+			}
+
+			networks = append(networks, driverNetwork{
+				driver:  driver,
+				network: network,
+			})
+		}
+	}
+
+	// Only query the attached machines when showing all information to avoid
+	// additional lookups otherwise.
+	inUse := map[string]int{}
+	if long && !opts.Quiet {
+		machineController, err := mplatform.NewMachineV1alpha1ServiceIterator(ctx)
+		if err != nil {
+			return err
+		}
+
+		machines, err := machineController.List(ctx, &machineapi.MachineList{})
+		if err != nil {
+			return err
+		}
+
+		for _, machine := range machines.Items {
+			for _, network := range machine.Spec.Networks {
+				inUse[network.IfName]++
+			}
+		}
 	}
 
 	var items []netTable
 
-	for _, network := range networks.Items {
-		addr := &net.IPNet{
-			IP:   net.ParseIP(network.Spec.Gateway),
-			Mask: net.IPMask(net.ParseIP(network.Spec.Netmask)),
+	for _, item := range networks {
+		spec := item.network.Spec
+
+		addr := network.FormatNetmask(spec.Gateway, spec.Netmask)
+		if opts.Format == "cidr" {
+			addr = network.FormatCIDR(spec.Gateway, spec.Netmask)
 		}
+
+		network := item.network
+
+		var created string
+		if !network.CreationTimestamp.IsZero() {
+			created = network.CreationTimestamp.Format(time.RFC3339)
+		}
+
 		items = append(items, netTable{
 			id:      string(network.UID),
 			name:    network.Name,
-			network: addr.String(),
-			driver:  opts.Driver,
+			network: addr,
+			driver:  item.driver,
+			inUse:   inUse[network.Name],
 			status:  network.Status.State,
+			created: created,
 		})
 
 	}
 
-	err = iostreams.G(ctx).StartPager()
-	if err != nil {
-		log.G(ctx).Errorf("error starting pager: %v", err)
+	tableprinter.Sort(items, opts.sortOrder, sortFields)
+
+	if opts.Quiet {
+		for _, item := range items {
+			fmt.Fprintln(out, item.name)
+		}
+
+		return nil
 	}
 
-	defer iostreams.G(ctx).StopPager()
+	if pager {
+		err = iostreams.G(ctx).StartPager()
+		if err != nil {
+			log.G(ctx).Errorf("error starting pager: %v", err)
+		}
+
+		defer iostreams.G(ctx).StopPager()
+
+		// The pager replaces the standard output.
+		out = iostreams.G(ctx).Out
+	}
 
 	cs := iostreams.G(ctx).ColorScheme()
 
@@ -122,25 +383,37 @@ func (opts *ListOptions) Run(ctx context.Context, _ []string) error {
 	}
 
 	// Header row
-	if opts.Long {
+	if long {
 		table.AddField("MACHINE ID", cs.Bold)
 	}
 	table.AddField("NAME", cs.Bold)
 	table.AddField("NETWORK", cs.Bold)
 	table.AddField("DRIVER", cs.Bold)
+	if long {
+		table.AddField("INUSE", cs.Bold)
+	}
 	table.AddField("STATUS", cs.Bold)
+	if wide {
+		table.AddField("CREATED", cs.Bold)
+	}
 	table.EndRow()
 
 	for _, item := range items {
-		if opts.Long {
+		if long {
 			table.AddField(item.id, nil)
 		}
 		table.AddField(item.name, nil)
 		table.AddField(item.network, nil)
 		table.AddField(item.driver, nil)
+		if long {
+			table.AddField(strconv.Itoa(item.inUse), nil)
+		}
 		table.AddField(item.status.String(), nil)
+		if wide {
+			table.AddField(item.created, nil)
+		}
 		table.EndRow()
 	}
 
-	return table.Render(iostreams.G(ctx).Out)
+	return table.Render(out)
 }
@@ -6,7 +6,10 @@ package remove
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/MakeNowJust/heredoc"
 	"github.com/spf13/cobra"
@@ -15,17 +18,25 @@ import (
 	machineapi "kraftkit.sh/api/machine/v1alpha1"
 	networkapi "kraftkit.sh/api/network/v1alpha1"
 	"kraftkit.sh/cmdfactory"
+	"kraftkit.sh/config"
 	"kraftkit.sh/iostreams"
 	"kraftkit.sh/log"
 	"kraftkit.sh/machine/network"
 	mplatform "kraftkit.sh/machine/platform"
+	"kraftkit.sh/tui/confirm"
 )
 
 type RemoveOptions struct {
 	Driver string `noattribute:"true"`
 	Force  bool   `long:"force" short:"f" usage:"Force removal of the network" default:"false"`
+	Prune  bool   `long:"prune" usage:"Remove all networks which are not in use by any machine"`
+	Yes    bool   `long:"yes" short:"y" usage:"Do not ask for confirmation before pruning networks"`
 }
 
+// prunedNetworksExcluded are the networks which are never removed by
+// `--prune`, as they are the ones used by default, e.g. by `kraft run`.
+var prunedNetworksExcluded = []string{"kraft0"}
+
 // Remove a local machine network.
 func Remove(ctx context.Context, opts *RemoveOptions, args ...string) error {
 	if opts == nil {
@@ -38,13 +49,27 @@ func Remove(ctx context.Context, opts *RemoveOptions, args ...string) error {
 func NewCmd() *cobra.Command {
 	cmd, err := cmdfactory.New(&RemoveOptions{}, cobra.Command{
 		Short:   "Remove a network",
-		Use:     "remove",
+		Use:     "remove [FLAGS] NETWORK",
 		Aliases: []string{"rm", "delete", "del"},
-		Args:    cobra.ExactArgs(1),
-		Long:    "Remove a network.",
+		Args:    cobra.MaximumNArgs(1),
+		Long: heredoc.Doc(`
+			Remove a network.
+
+			With --prune, all networks of the driver which are not in use by any
+			machine are removed instead, except for the default network kraft0.
+		`),
 		Example: heredoc.Doc(`
 			# Remove a network
 			$ kraft network remove my-network
+
+			# Remove a network created with a specific driver
+			$ kraft network --driver bridge remove my-network
+
+			# Remove all networks which are not in use
+			$ kraft network remove --prune
+
+			# Remove all networks which are not in use without asking for confirmation
+			$ kraft network remove --prune -y
 		`),
 		Annotations: map[string]string{
 			cmdfactory.AnnotationHelpGroup: "net",
@@ -63,7 +88,9 @@ func (opts *RemoveOptions) Pre(cmd *cobra.Command, _ []string) error {
 }
 
 func (opts *RemoveOptions) Run(ctx context.Context, args []string) error {
-	if len(args) != 1 {
+	if opts.Prune && len(args) > 0 {
+		return fmt.Errorf("cannot specify a network when using --prune")
+	} else if !opts.Prune && len(args) != 1 {
 		return fmt.Errorf("expected exactly one network to remove, got %d", len(args))
 	}
 
@@ -89,6 +116,10 @@ func (opts *RemoveOptions) Run(ctx context.Context, args []string) error {
 		return err
 	}
 
+	if opts.Prune {
+		return opts.prune(ctx, controller, machines)
+	}
+
 	for _, machine := range machines.Items {
 		for _, network := range machine.Spec.Networks {
 			if network.IfName == args[0] {
@@ -124,3 +155,69 @@ func (opts *RemoveOptions) Run(ctx context.Context, args []string) error {
 
 	return nil
 }
+
+// prune removes all networks which are not attached to any of the provided
+// machines, after asking for confirmation unless `--yes` is set.
+func (opts *RemoveOptions) prune(ctx context.Context, controller networkapi.NetworkService, machines *machineapi.MachineList) error {
+	networks, err := controller.List(ctx, &networkapi.NetworkList{})
+	if err != nil {
+		return err
+	}
+
+	inUse := map[string]int{}
+	for _, machine := range machines.Items {
+		for _, network := range machine.Spec.Networks {
+			inUse[network.IfName]++
+		}
+	}
+
+	var unused []string
+	for _, network := range networks.Items {
+		if inUse[network.Name] > 0 || slices.Contains(prunedNetworksExcluded, network.Name) {
+			continue
+		}
+
+		unused = append(unused, network.Name)
+	}
+
+	if len(unused) == 0 {
+		log.G(ctx).Info("no unused networks to remove")
+		return nil
+	}
+
+	if !opts.Yes && !config.G[config.KraftKit](ctx).NoPrompt && iostreams.G(ctx).IsStdinTTY() {
+		proceed, err := confirm.NewConfirmWithDefault(
+			fmt.Sprintf("This will remove %d unused network(s): %s. Continue?", len(unused), strings.Join(unused, ", ")),
+			false,
+		)
+		if err != nil {
+			return err
+		}
+
+		if !proceed {
+			return nil
+		}
+	}
+
+	var errs []error
+	var removed int
+
+	for _, name := range unused {
+		if _, err := controller.Delete(ctx, &networkapi.Network{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+			},
+		}); err != nil {
+			errs = append(errs, fmt.Errorf("could not remove network %s: %w", name, err))
+			continue
+		}
+
+		removed++
+
+		fmt.Fprintln(iostreams.G(ctx).Out, name)
+	}
+
+	log.G(ctx).Infof("removed %d of %d unused network(s)", removed, len(unused))
+
+	return errors.Join(errs...)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"kraftkit.sh/config"
+	"kraftkit.sh/internal/tableprinter"
 	"kraftkit.sh/tui/processtree"
 	"kraftkit.sh/unikraft/app"
 
@@ -29,21 +30,22 @@ import (
 )
 
 type ListOptions struct {
-	All       bool   `long:"all" usage:"Show everything"`
-	Arch      string `long:"arch" usage:"Set a specific arhitecture to list for"`
-	Kraftfile string `long:"kraftfile" short:"K" usage:"Set an alternative path of the Kraftfile"`
-	Limit     int    `long:"limit" short:"l" usage:"Set the maximum number of results" default:"50"`
-	Local     bool   `long:"local" usage:"Show local packages only"`
-	NoLimit   bool   `long:"no-limit" usage:"Do not limit the number of items to print"`
-	Output    string `long:"output" short:"o" usage:"Set output format. Options: table,yaml,json,list" default:"table"`
-	Plat      string `long:"plat" usage:"Set a specific platform to list for"`
-	Remote    bool   `long:"remote" short:"u" usage:"Show remote packages only"`
-	ShowApps  bool   `long:"apps" short:"" usage:"Show applications"`
-	ShowArchs bool   `long:"archs" short:"M" usage:"Show architectures"`
-	ShowCore  bool   `long:"core" short:"C" usage:"Show Unikraft core versions"`
-	ShowLibs  bool   `long:"libs" short:"L" usage:"Show libraries"`
-	ShowPlats bool   `long:"plats" short:"P" usage:"Show platforms"`
-	Update    bool   `long:"update" short:"U" usage:"Update package indexes before listing"`
+	All       bool     `long:"all" usage:"Show everything"`
+	Arch      string   `long:"arch" usage:"Set a specific arhitecture to list for"`
+	Columns   []string `long:"columns" usage:"Only show the given comma-separated columns, in the given order"`
+	Kraftfile string   `long:"kraftfile" short:"K" usage:"Set an alternative path of the Kraftfile"`
+	Limit     int      `long:"limit" short:"l" usage:"Set the maximum number of results" default:"50"`
+	Local     bool     `long:"local" usage:"Show local packages only"`
+	NoLimit   bool     `long:"no-limit" usage:"Do not limit the number of items to print"`
+	Output    string   `long:"output" short:"o" usage:"Set output format. Options: table,yaml,json,jsonl,list" default:"table"`
+	Plat      string   `long:"plat" usage:"Set a specific platform to list for"`
+	Remote    bool     `long:"remote" short:"u" usage:"Show remote packages only"`
+	ShowApps  bool     `long:"apps" short:"" usage:"Show applications"`
+	ShowArchs bool     `long:"archs" short:"M" usage:"Show architectures"`
+	ShowCore  bool     `long:"core" short:"C" usage:"Show Unikraft core versions"`
+	ShowLibs  bool     `long:"libs" short:"L" usage:"Show libraries"`
+	ShowPlats bool     `long:"plats" short:"P" usage:"Show platforms"`
+	Update    bool     `long:"update" short:"U" usage:"Update package indexes before listing"`
 }
 
 func NewCmd() *cobra.Command {
@@ -89,6 +91,8 @@ func (opts *ListOptions) Pre(cmd *cobra.Command, _ []string) error {
 }
 
 func (opts *ListOptions) Run(ctx context.Context, args []string) error {
+	ctx = tableprinter.WithColumnsInContext(ctx, opts.Columns)
+
 	var err error
 
 	workdir := ""
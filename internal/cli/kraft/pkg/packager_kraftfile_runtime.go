@@ -13,6 +13,7 @@ import (
 
 	"github.com/mattn/go-shellwords"
 	"kraftkit.sh/config"
+	"kraftkit.sh/internal/cli/kraft/build"
 	"kraftkit.sh/internal/cli/kraft/utils"
 	"kraftkit.sh/log"
 	"kraftkit.sh/pack"
@@ -127,18 +128,27 @@ func (p *packagerKraftfileRuntime) Pack(ctx context.Context, opts *PkgOptions, a
 					packmanager.WithKConfig(kconfigs),
 				)
 
-				packs, err = opts.pm.Catalog(ctx, append(qopts, packmanager.WithRemote(false))...)
-				if err != nil {
-					return fmt.Errorf("could not query catalog: %w", err)
-				} else if len(packs) == 0 {
-					// Try again with a remote update request.  Save this to qopts in case we
-					// need to call `Catalog` again.
-					packs, err = opts.pm.Catalog(ctx, append(qopts, packmanager.WithRemote(true))...)
+				// The 'always' policy skips the local catalog such that the latest
+				// version of the runtime is used.
+				if opts.PullPolicy != build.PullPolicyAlways {
+					packs, err = opts.pm.Catalog(ctx, append(qopts, packmanager.WithRemote(false))...)
 					if err != nil {
 						return fmt.Errorf("could not query catalog: %w", err)
+					} else if len(packs) > 0 {
+						return nil
 					}
 				}
 
+				if opts.PullPolicy == build.PullPolicyNever {
+					return fmt.Errorf("runtime is not available locally and --pull-policy=%s", build.PullPolicyNever)
+				}
+
+				// Try again with a remote update request.
+				packs, err = opts.pm.Catalog(ctx, append(qopts, packmanager.WithRemote(true))...)
+				if err != nil {
+					return fmt.Errorf("could not query catalog: %w", err)
+				}
+
 				return nil
 			},
 		),
@@ -227,7 +237,7 @@ func (p *packagerKraftfileRuntime) Pack(ctx context.Context, opts *PkgOptions, a
 		}()
 	}
 
-	if !pulled && !opts.NoPull {
+	if (!pulled || opts.PullPolicy == build.PullPolicyAlways) && !opts.NoPull {
 		paramodel, err := paraprogress.NewParaProgress(
 			ctx,
 			[]*paraprogress.Process{paraprogress.NewProcess(
@@ -13,6 +13,8 @@ import (
 	"github.com/spf13/cobra"
 
 	"kraftkit.sh/config"
+	"kraftkit.sh/internal/cli/kraft/build"
+	"kraftkit.sh/internal/cli/kraft/utils"
 	"kraftkit.sh/log"
 	"kraftkit.sh/machine/platform"
 	"kraftkit.sh/pack"
@@ -47,16 +49,24 @@ type PkgOptions struct {
 	Output       string                    `local:"true" long:"output" short:"o" usage:"Save the package at the following output"`
 	Platform     string                    `local:"true" long:"plat" short:"p" usage:"Filter the creation of the package by platform of known targets"`
 	Project      app.Application           `noattribute:"true"`
+	PullPolicy   build.PullPolicy          `noattribute:"true"`
 	Push         bool                      `local:"true" long:"push" short:"P" usage:"Push the package on if successfully packaged"`
 	Rootfs       string                    `local:"true" long:"rootfs" usage:"Specify a path to use as root file system (can be volume or initramfs)"`
 	Strategy     packmanager.MergeStrategy `noattribute:"true"`
 	Target       string                    `local:"true" long:"target" short:"t" usage:"Package a particular known target"`
+	Timings      utils.StepTimings         `noattribute:"true"`
 	Workdir      string                    `local:"true" long:"workdir" short:"w" usage:"Set an alternative working directory (default is cwd)"`
 
 	packopts []packmanager.PackOption
 	pm       packmanager.PackageManager
 }
 
+// The steps of packaging whose duration is recorded in PkgOptions.Timings.
+const (
+	StepPackage = utils.StepPackage
+	StepPush    = "push"
+)
+
 // Pkg a Unikraft project.
 func Pkg(ctx context.Context, opts *PkgOptions, args ...string) ([]pack.Package, error) {
 	var err error
@@ -161,8 +171,11 @@ func Pkg(ctx context.Context, opts *PkgOptions, args ...string) ([]pack.Package,
 
 	log.G(ctx).WithField("packager", pkgr.String()).Debug("using")
 
-	packs, err := pkgr.Pack(ctx, opts, args...)
-	if err != nil {
+	var packs []pack.Package
+	if err := opts.Timings.Time(StepPackage, func() (err error) {
+		packs, err = pkgr.Pack(ctx, opts, args...)
+		return err
+	}); err != nil {
 		return nil, fmt.Errorf("could not package: %w", err)
 	}
 
@@ -193,7 +206,7 @@ func Pkg(ctx context.Context, opts *PkgOptions, args ...string) ([]pack.Package,
 			return packs, err
 		}
 
-		if err := model.Start(); err != nil {
+		if err := opts.Timings.Time(StepPush, model.Start); err != nil {
 			return packs, err
 		}
 	}
@@ -5,9 +5,11 @@
 package ps
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	machineapi "kraftkit.sh/api/machine/v1alpha1"
 	"kraftkit.sh/cmdfactory"
@@ -23,12 +25,16 @@ import (
 )
 
 type PsOptions struct {
-	Architecture string `long:"arch" short:"m" usage:"Filter the list by architecture"`
-	Long         bool   `long:"long" short:"l" usage:"Show more information"`
+	Architecture string   `long:"arch" short:"m" usage:"Filter the list by architecture"`
+	Columns      []string `long:"columns" usage:"Only show the given comma-separated columns, in the given order"`
+	Long         bool     `long:"long" short:"l" usage:"Show more information"`
 	platform     string
 	Quiet        bool   `long:"quiet" short:"q" usage:"Only display machine IDs"`
 	ShowAll      bool   `long:"all" short:"a" usage:"Show all machines (default shows just running)"`
-	Output       string `long:"output" short:"o" usage:"Set output format. Options: table,yaml,json,list" default:"table"`
+	Output       string `long:"output" short:"o" usage:"Set output format. Options: table,wide,yaml,json,jsonl,list" default:"table"`
+	Sort         string `long:"sort" usage:"Sort the machines by a field in the form FIELD[:asc|desc] (name, kernel, created, status, plat)"`
+
+	sortOrder *tableprinter.SortOrder
 }
 
 const (
@@ -51,6 +57,12 @@ func NewCmd() *cobra.Command {
 
 			# List all unikernels with more information
 			$ kraft ps --long
+
+			# List all unikernels with every available field, untruncated
+			$ kraft ps --all -o wide
+
+			# List all unikernels, the most recently created first
+			$ kraft ps --all --sort created:desc
 		`),
 		Annotations: map[string]string{
 			cmdfactory.AnnotationHelpGroup: "run",
@@ -75,7 +87,11 @@ func NewCmd() *cobra.Command {
 
 func (opts *PsOptions) Pre(cmd *cobra.Command, _ []string) error {
 	opts.platform = cmd.Flag("plat").Value.String()
-	return nil
+
+	var err error
+	opts.sortOrder, err = tableprinter.ParseSortOrder(opts.Sort, sortFields)
+
+	return err
 }
 
 type PsEntry struct {
@@ -91,6 +107,17 @@ type PsEntry struct {
 	Arch    string
 	Plat    string
 	IPs     []string
+
+	createdAt time.Time
+}
+
+// sortFields are the supported fields of the `--sort` flag.
+var sortFields = map[string]func(a, b PsEntry) int{
+	"name":    func(a, b PsEntry) int { return cmp.Compare(a.Name, b.Name) },
+	"kernel":  func(a, b PsEntry) int { return cmp.Compare(a.Kernel, b.Kernel) },
+	"created": func(a, b PsEntry) int { return a.createdAt.Compare(b.createdAt) },
+	"status":  func(a, b PsEntry) int { return cmp.Compare(a.State.String(), b.State.String()) },
+	"plat":    func(a, b PsEntry) int { return cmp.Compare(a.Plat, b.Plat) },
 }
 
 type colorFunc func(string) string
@@ -121,11 +148,15 @@ var (
 )
 
 func (opts *PsOptions) Run(ctx context.Context, _ []string) error {
+	ctx = tableprinter.WithColumnsInContext(ctx, opts.Columns)
+
 	items, err := opts.PsTable(ctx)
 	if err != nil {
 		return err
 	}
 
+	tableprinter.Sort(items, opts.sortOrder, sortFields)
+
 	return opts.PrintPsTable(ctx, items)
 }
 
@@ -184,6 +215,8 @@ func (opts *PsOptions) PsTable(ctx context.Context) ([]PsEntry, error) {
 			Pid:     machine.Status.Pid,
 			Plat:    machine.Spec.Platform,
 			IPs:     []string{},
+
+			createdAt: machine.ObjectMeta.CreationTimestamp.Time,
 		}
 
 		if machine.Status.State == machineapi.MachineStateRunning {
@@ -220,8 +253,12 @@ func (opts *PsOptions) PrintPsTable(ctx context.Context, items []PsEntry) error
 		return err
 	}
 
+	// The wide output format shows every available field, including those
+	// otherwise only shown with --long.
+	long := opts.Long || opts.Output == string(tableprinter.OutputFormatWide)
+
 	// Header row
-	if opts.Long {
+	if long {
 		table.AddField("MACHINE ID", cs.Bold)
 	}
 	table.AddField("NAME", cs.Bold)
@@ -231,12 +268,12 @@ func (opts *PsOptions) PrintPsTable(ctx context.Context, items []PsEntry) error
 	table.AddField("STATUS", cs.Bold)
 	table.AddField("MEM", cs.Bold)
 	table.AddField("PORTS", cs.Bold)
-	if opts.Long {
+	if long {
 		table.AddField("IP", cs.Bold)
 		table.AddField("PID", cs.Bold)
 	}
 	table.AddField("PLAT", cs.Bold)
-	if opts.Long {
+	if long {
 		table.AddField("ARCH", cs.Bold)
 	}
 	table.EndRow()
@@ -246,7 +283,7 @@ func (opts *PsOptions) PrintPsTable(ctx context.Context, items []PsEntry) error
 	}
 
 	for _, item := range items {
-		if opts.Long {
+		if long {
 			table.AddField(item.ID, nil)
 		}
 		table.AddField(item.Name, nil)
@@ -256,14 +293,14 @@ func (opts *PsOptions) PrintPsTable(ctx context.Context, items []PsEntry) error
 		table.AddField(item.State.String(), machineStateColor[item.State])
 		table.AddField(item.Mem, nil)
 		table.AddField(item.Ports, nil)
-		if opts.Long {
+		if long {
 			table.AddField(strings.Join(item.IPs, ","), nil)
 			table.AddField(fmt.Sprintf("%d", item.Pid), nil)
 			table.AddField(item.Plat, nil)
 		} else {
 			table.AddField(fmt.Sprintf("%s/%s", item.Plat, item.Arch), nil)
 		}
-		if opts.Long {
+		if long {
 			table.AddField(item.Arch, nil)
 		}
 		table.EndRow()
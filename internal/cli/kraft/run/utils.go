@@ -326,6 +326,7 @@ func (opts *RunOptions) prepareRootfs(ctx context.Context, machine *machineapi.M
 			"rootfs-cache",
 		)),
 		initrd.WithArchitecture(machine.Spec.Architecture),
+		initrd.WithIgnoreFile(initrd.FindIgnoreFile(opts.workdir)),
 	)
 	if err != nil {
 		return fmt.Errorf("could not prepare initramfs: %w", err)
@@ -0,0 +1,151 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"kraftkit.sh/config"
+	"kraftkit.sh/iostreams"
+	"kraftkit.sh/log"
+	"kraftkit.sh/tui/processtree"
+)
+
+// ProgressOptions configures how ForEachWithProgress processes items of type T
+// and reports its progress.
+type ProgressOptions[T any] struct {
+	// Verb is shown alongside the process tree, e.g. "Starting".
+	Verb string
+
+	// Parallel is the maximum number of items processed concurrently.
+	Parallel int
+
+	// Name returns the label of the provided item.
+	Name func(T) string
+
+	// HideOnSuccess hides the items of the process tree which succeeded.
+	HideOnSuccess bool
+
+	// Done is optionally called once each item has been processed when the
+	// progress is not rendered, with the number of items processed so far.
+	Done func(ctx context.Context, item T, n int, err error)
+}
+
+// ForEachWithProgress calls fn for each of the provided items using at most
+// opts.Parallel concurrent workers.  When attached to a terminal, progress is
+// rendered as a process tree; otherwise, opts.Done reports each completion.  A
+// failure for one item does not prevent the remaining items from being
+// processed; the number of items which succeeded is returned alongside all
+// failures.
+func ForEachWithProgress[T any](ctx context.Context, opts ProgressOptions[T], items []T, fn func(context.Context, T) error) (int, error) {
+	parallel := opts.Parallel
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	var errs []error
+	var mu sync.Mutex
+	sem := make(chan struct{}, parallel)
+	done := 0
+
+	process := func(ctx context.Context, item T) error {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			err := fmt.Errorf("%s: %w", opts.Name(item), ctx.Err())
+			mu.Lock()
+			errs = append(errs, err)
+			done++
+			mu.Unlock()
+			return err
+		}
+		defer func() { <-sem }()
+
+		err := fn(ctx, item)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		done++
+
+		return err
+	}
+
+	if !RenderProgress(ctx) {
+		var wg sync.WaitGroup
+		wg.Add(len(items))
+
+		for _, item := range items {
+			go func(item T) {
+				defer wg.Done()
+
+				err := process(ctx, item)
+				if opts.Done == nil {
+					return
+				}
+
+				mu.Lock()
+				n := done
+				mu.Unlock()
+
+				opts.Done(ctx, item, n, err)
+			}(item)
+		}
+
+		wg.Wait()
+
+		return len(items) - len(errs), errors.Join(errs...)
+	}
+
+	treeItems := make([]*processtree.ProcessTreeItem, 0, len(items))
+	for _, item := range items {
+		item := item
+		treeItems = append(treeItems, processtree.NewProcessTreeItem(
+			opts.Name(item),
+			"",
+			func(ctx context.Context) error {
+				return process(ctx, item)
+			},
+		))
+	}
+
+	model, err := processtree.NewProcessTree(
+		ctx,
+		[]processtree.ProcessTreeOption{
+			processtree.IsParallel(true),
+			processtree.WithRenderer(false),
+			processtree.WithFailFast(false),
+			processtree.WithHideOnSuccess(opts.HideOnSuccess),
+			processtree.WithVerb(opts.Verb),
+		},
+		treeItems...,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	// Failures of individual items are collected above, such that only
+	// failures of the process tree itself are of interest here.
+	if err := model.Start(); err != nil && len(errs) == 0 {
+		return 0, err
+	}
+
+	return len(items) - len(errs), errors.Join(errs...)
+}
+
+// RenderProgress returns whether progress can be rendered interactively.
+func RenderProgress(ctx context.Context) bool {
+	return iostreams.G(ctx).IsStdoutTTY() &&
+		!config.G[config.KraftKit](ctx).NoPrompt &&
+		log.LoggerTypeFromString(config.G[config.KraftKit](ctx).Log.Type) == log.FANCY
+}
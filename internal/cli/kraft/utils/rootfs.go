@@ -20,7 +20,9 @@ import (
 )
 
 // BuildRootfs generates a rootfs based on the provided working directory and
-// the rootfs entrypoint for the provided target(s).
+// the rootfs entrypoint for the provided target(s).  When the rootfs is a
+// directory, the paths matched by the `.kraftignore` (or otherwise the
+// `.dockerignore`) file of the working directory are excluded.
 func BuildRootfs(ctx context.Context, workdir, rootfs string, targets ...target.Target) (string, error) {
 	if rootfs == "" {
 		return "", nil
@@ -49,6 +51,7 @@ func BuildRootfs(ctx context.Context, workdir, rootfs string, targets ...target.
 				"rootfs-cache",
 			)),
 			initrd.WithArchitecture(arch),
+			initrd.WithIgnoreFile(initrd.FindIgnoreFile(workdir)),
 		)
 		if err != nil {
 			return "", fmt.Errorf("could not initialize initramfs builder: %w", err)
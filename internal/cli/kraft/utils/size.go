@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package utils
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// SizeUsage is the description of the units accepted by ParseSizeMB, to be
+// appended to the usage of flags which accept a size.
+const SizeUsage = "in MiB unless a unit is given (K, M or G, optionally followed by i or iB; all units are binary)"
+
+// sizeUnits are the units accepted by ParseSizeMB alongside their size in
+// KiB.  Longer suffixes are listed first such that they are matched first.
+var sizeUnits = []struct {
+	suffix string
+	kib    int64
+}{
+	{"KiB", 1},
+	{"MiB", 1024},
+	{"GiB", 1024 * 1024},
+	{"Ki", 1},
+	{"Mi", 1024},
+	{"Gi", 1024 * 1024},
+	{"KB", 1},
+	{"MB", 1024},
+	{"GB", 1024 * 1024},
+	{"K", 1},
+	{"M", 1024},
+	{"G", 1024 * 1024},
+}
+
+// ParseSizeMB parses a human-readable size, e.g. "512", "512M", "512Mi",
+// "512MiB" or "1.5G", and returns it in mebibytes.  Sizes without a unit are
+// interpreted as mebibytes, and the K, M and G units are binary regardless of
+// whether they carry an i, in line with the memory sizes of Docker and compose
+// files.  The size must be a whole number of mebibytes and at least 1MiB.
+func ParseSizeMB(size string) (int, error) {
+	size = strings.TrimSpace(size)
+
+	num := size
+	kib := int64(1024)
+	for _, unit := range sizeUnits {
+		if trimmed, ok := strings.CutSuffix(size, unit.suffix); ok {
+			num = trimmed
+			kib = unit.kib
+			break
+		}
+	}
+
+	value, err := strconv.ParseFloat(num, 64)
+	if err != nil || value <= 0 || math.IsInf(value, 0) || math.IsNaN(value) {
+		return 0, fmt.Errorf("invalid size '%s': expected a positive number optionally followed by K, M or G (or Ki, KiB, KB, ...)", size)
+	}
+
+	mb := value * float64(kib) / 1024
+	if mb < 1 {
+		return 0, fmt.Errorf("invalid size '%s': must be at least 1MiB", size)
+	}
+
+	if mb != math.Trunc(mb) {
+		return 0, fmt.Errorf("invalid size '%s': must be a whole number of MiB", size)
+	}
+
+	if mb > math.MaxInt32 {
+		return 0, fmt.Errorf("invalid size '%s': too large", size)
+	}
+
+	return int(mb), nil
+}
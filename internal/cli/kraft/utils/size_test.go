@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package utils
+
+import "testing"
+
+func TestParseSizeMB(t *testing.T) {
+	tests := []struct {
+		size     string
+		expected int
+		err      bool
+	}{
+		{size: "512", expected: 512},
+		{size: " 512 ", expected: 512},
+		{size: "512Mi", expected: 512},
+		{size: "512MiB", expected: 512},
+		{size: "2048Ki", expected: 2},
+		{size: "1024KiB", expected: 1},
+		{size: "1Gi", expected: 1024},
+		{size: "1GiB", expected: 1024},
+		{size: "1.5Gi", expected: 1536},
+		{size: "512M", expected: 512},
+		{size: "512MB", expected: 512},
+		{size: "2048K", expected: 2},
+		{size: "1024KB", expected: 1},
+		{size: "1G", expected: 1024},
+		{size: "2GB", expected: 2048},
+		{size: "512B", err: true},
+		{size: "1i", err: true},
+		{size: "1K", err: true},
+		{size: "1KB", err: true},
+		{size: "512m", err: true},
+		{size: "1Ti", err: true},
+		{size: "512mi", err: true},
+		{size: "512Ki", err: true},
+		{size: "1.5", err: true},
+		{size: "1.5Mi", err: true},
+		{size: "0", err: true},
+		{size: "-1", err: true},
+		{size: "Mi", err: true},
+		{size: "", err: true},
+		{size: "Inf", err: true},
+		{size: "NaN", err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.size, func(t *testing.T) {
+			actual, err := ParseSizeMB(tt.size)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("ParseSizeMB(%q) = %d, expected an error", tt.size, actual)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSizeMB(%q) returned unexpected error: %v", tt.size, err)
+			}
+
+			if actual != tt.expected {
+				t.Errorf("ParseSizeMB(%q) = %d, expected %d", tt.size, actual, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package utils
+
+import (
+	"fmt"
+	"time"
+)
+
+// ParseTime parses either an RFC3339 timestamp, e.g. `2024-01-02T15:04:05Z`,
+// or a duration relative to the provided time, e.g. `10m` for ten minutes
+// before it.
+func ParseTime(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time '%s': expected an RFC3339 timestamp or a duration", s)
+	}
+
+	if d < 0 {
+		return time.Time{}, fmt.Errorf("invalid time '%s': duration cannot be negative", s)
+	}
+
+	return now.Add(-d), nil
+}
+
+// ParseTimeRange parses the values of the `--since` and `--until` flags with
+// ParseTime, relative to the provided time.  Either may be empty, in which case
+// the corresponding bound is the zero time.
+func ParseTimeRange(since, until string, now time.Time) (time.Time, time.Time, error) {
+	var from, to time.Time
+	var err error
+
+	if since != "" {
+		if from, err = ParseTime(since, now); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+
+	if until != "" {
+		if to, err = ParseTime(until, now); err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--until (%s) is before --since (%s)", to.Format(time.RFC3339), from.Format(time.RFC3339))
+	}
+
+	return from, to, nil
+}
+
+// InTimeRange returns whether the provided time is within the bounds returned
+// by ParseTimeRange, where a zero bound is unbounded.
+func InTimeRange(t, from, to time.Time) bool {
+	return (from.IsZero() || !t.Before(from)) && (to.IsZero() || !t.After(to))
+}
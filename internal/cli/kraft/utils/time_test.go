@@ -0,0 +1,83 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeRange(t *testing.T) {
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		since  string
+		until  string
+		from   time.Time
+		to     time.Time
+		hasErr bool
+	}{
+		{name: "unbounded"},
+		{name: "relative since", since: "15m", from: now.Add(-15 * time.Minute)},
+		{name: "absolute until", until: "2024-01-02T12:00:00Z", to: time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC)},
+		{name: "mixed", since: "2024-01-01T00:00:00Z", until: "1h", from: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), to: now.Add(-time.Hour)},
+		{name: "zero duration", since: "0s", from: now},
+		{name: "negative duration", since: "-1h", hasErr: true},
+		{name: "invalid", until: "yesterday", hasErr: true},
+		{name: "until before since", since: "1h", until: "2h", hasErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := ParseTimeRange(tt.since, tt.until, now)
+			if tt.hasErr {
+				if err == nil {
+					t.Errorf("ParseTimeRange(%q, %q) succeeded, expected an error", tt.since, tt.until)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTimeRange(%q, %q): %v", tt.since, tt.until, err)
+			}
+
+			if !from.Equal(tt.from) {
+				t.Errorf("ParseTimeRange(%q, %q) since = %s, expected %s", tt.since, tt.until, from, tt.from)
+			}
+			if !to.Equal(tt.to) {
+				t.Errorf("ParseTimeRange(%q, %q) until = %s, expected %s", tt.since, tt.until, to, tt.to)
+			}
+		})
+	}
+}
+
+func TestInTimeRange(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		t        time.Time
+		from     time.Time
+		to       time.Time
+		expected bool
+	}{
+		{"unbounded", from, time.Time{}, time.Time{}, true},
+		{"within", from.Add(time.Hour), from, to, true},
+		{"at since", from, from, to, true},
+		{"at until", to, from, to, true},
+		{"before since", from.Add(-time.Second), from, time.Time{}, false},
+		{"after until", to.Add(time.Second), time.Time{}, to, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := InTimeRange(tt.t, tt.from, tt.to); actual != tt.expected {
+				t.Errorf("InTimeRange(%s, %s, %s) = %t, expected %t", tt.t, tt.from, tt.to, actual, tt.expected)
+			}
+		})
+	}
+}
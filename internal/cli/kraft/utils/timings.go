@@ -0,0 +1,31 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package utils
+
+import "time"
+
+// StepPackage is the step during which the root filesystem and the package of
+// a project are produced.  It is shared by the build, which generates the root
+// filesystem, and the packaging of a project.
+const StepPackage = "package"
+
+// StepTimings records the time taken by each of the steps of an operation,
+// e.g. the build of a project.  A nil StepTimings records nothing.
+type StepTimings map[string]time.Duration
+
+// Time calls fn and adds the time it took to the duration of the provided
+// step, such that a step which is performed more than once is accounted for
+// as a whole.
+func (timings StepTimings) Time(step string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+
+	if timings != nil {
+		timings[step] += time.Since(start)
+	}
+
+	return err
+}
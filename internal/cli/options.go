@@ -76,7 +76,7 @@ func WithDefaultLogger() CliOption {
 				formatter.TimestampFormat = ">"
 			}
 
-			formatter.DisableColors = iostreams.EnvColorDisabled()
+			formatter.DisableColors = copts.ConfigManager.Config.NoColor || iostreams.EnvColorDisabled()
 			formatter.ForceColors = iostreams.EnvColorForced()
 
 			logger.Formatter = formatter
@@ -102,7 +102,7 @@ func WithDefaultLogger() CliOption {
 				formatter.TimestampFormat = ">"
 			}
 
-			formatter.DisableColors = iostreams.EnvColorDisabled()
+			formatter.DisableColors = copts.ConfigManager.Config.NoColor || iostreams.EnvColorDisabled()
 			formatter.ForceColors = iostreams.EnvColorForced()
 
 			logger.Formatter = formatter
@@ -0,0 +1,89 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file expect in compliance with the License.
+package tableprinter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+type columnsKey struct{}
+
+// WithColumnsInContext returns a context in which all tables created via
+// NewTablePrinter only show the provided columns, in the given order.  This
+// allows commands to select the columns of tables which are created by shared
+// printing utilities.
+func WithColumnsInContext(ctx context.Context, columns []string) context.Context {
+	return context.WithValue(ctx, columnsKey{}, columns)
+}
+
+// columnsFromContext returns the columns set via WithColumnsInContext, if any.
+func columnsFromContext(ctx context.Context) []string {
+	if ctx == nil {
+		return nil
+	}
+
+	columns, _ := ctx.Value(columnsKey{}).([]string)
+	return columns
+}
+
+// ColumnName returns the name by which the column with the provided header is
+// selected, i.e. the lowercase header with spaces replaced by hyphens, e.g.
+// `private-fqdn` for `PRIVATE FQDN`.
+func ColumnName(header string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(header)), " ", "-")
+}
+
+// selectColumns only keeps the columns of the table which were selected via
+// WithColumns, in the given order.  Column names are matched
+// case-insensitively and underscores are equivalent to hyphens.
+func (printer *TablePrinter) selectColumns() error {
+	if len(printer.columns) == 0 || len(printer.rows) == 0 {
+		return nil
+	}
+
+	header := printer.rows[0]
+	names := make([]string, len(header))
+	for i, field := range header {
+		names[i] = ColumnName(field.text)
+	}
+
+	indices := make([]int, 0, len(printer.columns))
+	for _, column := range printer.columns {
+		name := strings.ReplaceAll(ColumnName(column), "_", "-")
+
+		index := -1
+		for i := range names {
+			if names[i] == name {
+				index = i
+				break
+			}
+		}
+
+		if index < 0 {
+			return fmt.Errorf("unknown column '%s': expected one of %s", column, strings.Join(names, ", "))
+		}
+
+		indices = append(indices, index)
+	}
+
+	for r, row := range printer.rows {
+		if len(row) == 0 {
+			continue
+		}
+
+		selected := make([]TableField, 0, len(indices))
+		for _, index := range indices {
+			if index < len(row) {
+				selected = append(selected, row[index])
+			}
+		}
+
+		printer.rows[r] = selected
+	}
+
+	return nil
+}
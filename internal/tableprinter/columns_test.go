@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package tableprinter
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestColumns(t *testing.T) {
+	tests := []struct {
+		name     string
+		columns  []string
+		expected string
+		err      bool
+	}{
+		{
+			name:     "unset shows all columns",
+			columns:  nil,
+			expected: "NAME   CREATED AT  STATUS\nhello  today       up\n",
+		},
+		{
+			name:     "selected columns in the given order",
+			columns:  []string{"status", "name"},
+			expected: "STATUS  NAME\nup      hello\n",
+		},
+		{
+			name:     "case-insensitive with underscores or hyphens",
+			columns:  []string{"Created_At", "created-at"},
+			expected: "CREATED AT  CREATED AT\ntoday       today\n",
+		},
+		{
+			name:    "unknown column",
+			columns: []string{"name", "size"},
+			err:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := WithColumnsInContext(context.Background(), tt.columns)
+
+			tp, err := NewTablePrinter(ctx, WithMaxWidth(80))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			tp.AddField("NAME", nil)
+			tp.AddField("CREATED AT", nil)
+			tp.AddField("STATUS", nil)
+			tp.EndRow()
+			tp.AddField("hello", nil)
+			tp.AddField("today", nil)
+			tp.AddField("up", nil)
+			tp.EndRow()
+
+			buf := bytes.Buffer{}
+			err = tp.Render(&buf)
+			if tt.err {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if buf.String() != tt.expected {
+				t.Errorf("expected: %q, got: %q", tt.expected, buf.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file expect in compliance with the License.
+package tableprinter
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// GoTemplatePrefix is the prefix of an output format which renders each item
+// with a Go template, e.g. `go-template={{.Name}}`.  Commands which print
+// structured results evaluate the template over them, such that their fields
+// are accessible by their Go names, e.g. `{{.PrivateFQDN}}`.  Commands which
+// only print a table evaluate it over each row, whose fields are accessible
+// by the same style of name derived from the column header, see
+// GoTemplateKey.
+const GoTemplatePrefix = "go-template="
+
+// goTemplateInitialisms are the words of a column header which are kept in
+// uppercase by GoTemplateKey, following the naming of Go fields.
+var goTemplateInitialisms = []string{"API", "CPU", "DNS", "FQDN", "HTTP", "ID", "IP", "OS", "TLS", "URL", "UUID"}
+
+// IsGoTemplate returns whether the provided output format is a Go template.
+func IsGoTemplate(format string) bool {
+	return strings.HasPrefix(format, GoTemplatePrefix)
+}
+
+// ParseGoTemplate parses the template of the provided output format.  A nil
+// template is returned if the output format is not a Go template.
+func ParseGoTemplate(format string) (*template.Template, error) {
+	if !IsGoTemplate(format) {
+		return nil, nil
+	}
+
+	tmpl, err := template.New("output").
+		Option("missingkey=error").
+		Parse(strings.TrimPrefix(format, GoTemplatePrefix))
+	if err != nil {
+		return nil, fmt.Errorf("invalid output template: %w", err)
+	}
+
+	return tmpl, nil
+}
+
+// GoTemplateKey returns the name by which the values of the provided column
+// are accessible in a Go template, which is its header in the style of a Go
+// field name, e.g. "PRIVATE FQDN" becomes "PrivateFQDN".
+func GoTemplateKey(header string) string {
+	var key strings.Builder
+
+	for _, word := range strings.FieldsFunc(header, func(r rune) bool {
+		return r == ' ' || r == '_' || r == '-'
+	}) {
+		upper := strings.ToUpper(word)
+		if slices.Contains(goTemplateInitialisms, upper) {
+			key.WriteString(upper)
+		} else {
+			key.WriteString(upper[:1] + strings.ToLower(upper[1:]))
+		}
+	}
+
+	return key.String()
+}
+
+// renderGoTemplate renders each row with the template, where the values of
+// the row are accessible by GoTemplateKey of their column header, e.g.
+// `{{.PrivateFQDN}}`.
+func (printer *TablePrinter) renderGoTemplate(w io.Writer) error {
+	header := printer.rows[0]
+
+	for i, row := range printer.rows {
+		if i == 0 || len(row) == 0 {
+			continue
+		}
+
+		m := make(map[string]string)
+		for j, column := range row {
+			m[GoTemplateKey(header[j].text)] = column.text
+		}
+
+		if err := printer.template.Execute(w, m); err != nil {
+			return fmt.Errorf("could not execute output template: %w", err)
+		}
+
+		if _, err := fmt.Fprintln(w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
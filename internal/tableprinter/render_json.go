@@ -12,6 +12,38 @@ import (
 )
 
 func (printer *TablePrinter) renderJSON(w io.Writer) error {
+	b, err := json.Marshal(printer.jsonRows())
+	if err != nil {
+		return err
+	}
+
+	if _, err = fmt.Fprint(w, string(b)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// renderJSONLines renders each row as a standalone JSON object on its own
+// line, also known as JSON Lines or newline-delimited JSON.
+func (printer *TablePrinter) renderJSONLines(w io.Writer) error {
+	for _, row := range printer.jsonRows() {
+		b, err := json.Marshal(row)
+		if err != nil {
+			return err
+		}
+
+		if _, err = fmt.Fprintln(w, string(b)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// jsonRows returns each row as a map of the lowercase column headers to the
+// values of the row.
+func (printer *TablePrinter) jsonRows() []map[string]string {
 	header := printer.rows[0]
 	var rows []map[string]string
 
@@ -30,14 +62,5 @@ func (printer *TablePrinter) renderJSON(w io.Writer) error {
 		}
 	}
 
-	b, err := json.Marshal(rows)
-	if err != nil {
-		return err
-	}
-
-	if _, err = fmt.Fprint(w, string(b)); err != nil {
-		return err
-	}
-
-	return nil
+	return rows
 }
@@ -10,62 +10,35 @@ import (
 	"strings"
 )
 
-func (printer *TablePrinter) renderList(w io.Writer) error {
-	maxWidth := 0
-	headers := printer.rows[0]
-
-	for _, field := range headers {
-		if len(field.text) > maxWidth {
-			maxWidth = len(field.text)
-		}
-	}
-
-	for i := 1; i < len(printer.rows); i++ {
-		row := printer.rows[i]
-
-		for col, field := range row {
-			header := headers[col]
-			headerText := strings.ToLower(header.text)
-
-			if header.color != nil {
-				headerText = header.color(headerText)
-			}
+// ListEmptyField is printed in place of empty fields by the list output
+// format, such that every line has the same number of fields.
+const ListEmptyField = "-"
 
-			// pad value with spaces on the right
-			if _, err := fmt.Fprint(w, strings.Repeat(" ", maxWidth-len(header.text)+1)); err != nil {
-				return err
-			}
-
-			_, err := fmt.Fprintf(w, "%s: ", headerText)
-			if err != nil {
-				return err
-			}
-
-			for i, value := range strings.Split(field.text, ", ") {
-				if i > 0 {
-					if _, err := fmt.Fprint(w, strings.Repeat(" ", maxWidth+3)); err != nil {
-						return err
-					}
-				}
+// listEscape is prepended to the fields which would otherwise be mistaken for
+// ListEmptyField, or which already start with it.
+const listEscape = `\`
 
-				if field.color != nil {
-					value = field.color(value)
-				}
-
-				if _, err := fmt.Fprint(w, value); err != nil {
-					return err
-				}
+// renderList renders each row, except for the header, as a single line whose
+// fields are separated by tabs, in the order of the columns.  See
+// OutputFormatList.
+func (printer *TablePrinter) renderList(w io.Writer) error {
+	for _, row := range printer.rows[1:] {
+		if len(row) == 0 {
+			continue
+		}
 
-				if _, err := fmt.Fprint(w, "\n"); err != nil {
-					return err
-				}
+		fields := make([]string, len(row))
+		for i, field := range row {
+			fields[i] = strings.Join(strings.Fields(field.text), " ")
+			if fields[i] == "" {
+				fields[i] = ListEmptyField
+			} else if fields[i] == ListEmptyField || strings.HasPrefix(fields[i], listEscape) {
+				fields[i] = listEscape + fields[i]
 			}
 		}
 
-		if i < len(printer.rows)-1 {
-			if _, err := fmt.Fprint(w, "\n"); err != nil {
-				return err
-			}
+		if _, err := fmt.Fprintln(w, strings.Join(fields, "\t")); err != nil {
+			return err
 		}
 	}
 
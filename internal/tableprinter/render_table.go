@@ -14,6 +14,12 @@ func (printer *TablePrinter) renderTable(w io.Writer) error {
 	numCols := len(printer.rows[0])
 	colWidths := printer.calculateColumnWidths(len(printer.delimeter))
 
+	// The wide format shows every field in full, regardless of the width of
+	// the terminal.
+	if printer.format == OutputFormatWide {
+		colWidths = printer.maxColumnWidths()
+	}
+
 	for _, row := range printer.rows {
 		for col, field := range row {
 			if col > 0 {
@@ -0,0 +1,76 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package tableprinter
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// SortOrder describes the order of the rows of a listing, as requested via a
+// `--sort FIELD[:asc|desc]` flag.
+type SortOrder struct {
+	Field      string
+	Descending bool
+}
+
+// ParseSortOrder parses a sort order in the form FIELD[:asc|desc], where FIELD
+// must be one of the keys of cmps.  An empty string results in a nil order,
+// i.e. the rows are kept in the order in which they were collected.
+func ParseSortOrder[T any](s string, cmps map[string]func(a, b T) int) (*SortOrder, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	field, direction, _ := strings.Cut(s, ":")
+	field = strings.ToLower(field)
+
+	if _, ok := cmps[field]; !ok {
+		fields := make([]string, 0, len(cmps))
+		for field := range cmps {
+			fields = append(fields, field)
+		}
+
+		sort.Strings(fields)
+
+		return nil, fmt.Errorf("cannot sort by '%s': expected one of %s", field, strings.Join(fields, ", "))
+	}
+
+	order := SortOrder{Field: field}
+
+	switch strings.ToLower(direction) {
+	case "", "asc":
+	case "desc":
+		order.Descending = true
+	default:
+		return nil, fmt.Errorf("invalid sort direction '%s': expected asc or desc", direction)
+	}
+
+	return &order, nil
+}
+
+// Sort stably sorts the provided items with the comparison function of the
+// field of the order.  The items are left untouched if the order is nil.
+func Sort[T any](items []T, order *SortOrder, cmps map[string]func(a, b T) int) {
+	if order == nil {
+		return
+	}
+
+	cmp, ok := cmps[order.Field]
+	if !ok {
+		return
+	}
+
+	slices.SortStableFunc(items, func(a, b T) int {
+		if order.Descending {
+			return cmp(b, a)
+		}
+
+		return cmp(a, b)
+	})
+}
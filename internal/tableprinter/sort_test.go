@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package tableprinter
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+)
+
+type sortItem struct {
+	name string
+	size int
+}
+
+var sortItemFields = map[string]func(a, b sortItem) int{
+	"name": func(a, b sortItem) int { return cmp.Compare(a.name, b.name) },
+	"size": func(a, b sortItem) int { return cmp.Compare(a.size, b.size) },
+}
+
+func TestSort(t *testing.T) {
+	tests := []struct {
+		name     string
+		order    string
+		expected []string
+	}{
+		{
+			name:     "unset keeps insertion order",
+			order:    "",
+			expected: []string{"b", "c", "a", "d"},
+		},
+		{
+			name:     "ascending by default",
+			order:    "name",
+			expected: []string{"a", "b", "c", "d"},
+		},
+		{
+			name:     "descending",
+			order:    "name:desc",
+			expected: []string{"d", "c", "b", "a"},
+		},
+		{
+			name:     "stable for equal keys",
+			order:    "SIZE:asc",
+			expected: []string{"b", "a", "c", "d"},
+		},
+		{
+			name:     "stable for equal keys in descending order",
+			order:    "size:desc",
+			expected: []string{"d", "c", "b", "a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := []sortItem{{"b", 1}, {"c", 2}, {"a", 1}, {"d", 3}}
+
+			order, err := ParseSortOrder(tt.order, sortItemFields)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			Sort(items, order, sortItemFields)
+
+			var names []string
+			for _, item := range items {
+				names = append(names, item.name)
+			}
+
+			if !slices.Equal(names, tt.expected) {
+				t.Errorf("expected %v, got %v", tt.expected, names)
+			}
+		})
+	}
+}
+
+func TestParseSortOrderInvalid(t *testing.T) {
+	for _, order := range []string{"unknown", "name:sideways"} {
+		if _, err := ParseSortOrder(order, sortItemFields); err == nil {
+			t.Errorf("expected an error for '%s'", order)
+		}
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"io"
 	"sort"
 	"strings"
+	"text/template"
 
 	"kraftkit.sh/internal/text"
 )
@@ -37,8 +38,22 @@ type TableOutputFormat string
 const (
 	OutputFormatTable = TableOutputFormat("table")
 	OutputFormatJSON  = TableOutputFormat("json")
+	OutputFormatJSONL = TableOutputFormat("jsonl")
 	OutputFormatYAML  = TableOutputFormat("yaml")
-	OutputFormatList  = TableOutputFormat("list")
+	OutputFormatWide  = TableOutputFormat("wide")
+
+	// OutputFormatList is meant to be parsed by tools such as awk and cut.  Each
+	// item is printed on a single line without a header, with its fields in the
+	// order of the columns of the table and separated by a tab.  Whitespace
+	// within a field is collapsed into single spaces, such that a field never
+	// contains a tab but may contain spaces: the fields must be split on tabs
+	// only.  Empty fields are printed as ListEmptyField and fields which equal
+	// it or start with a backslash are prefixed with a backslash, such that
+	// every field can be told apart.  Unlike the table format, no field is
+	// truncated.
+	OutputFormatList = TableOutputFormat("list")
+
+	OutputFormatGoTemplate = TableOutputFormat("go-template")
 
 	DefaultDelimeter = "  "
 )
@@ -58,6 +73,8 @@ type TablePrinter struct {
 	maxWidth     int
 	delimeter    string
 	truncateFunc func(int, string) string
+	template     *template.Template
+	columns      []string
 }
 
 // NewTablePrinter returns a pointer instance of TablePrinter struct.
@@ -66,6 +83,7 @@ func NewTablePrinter(ctx context.Context, topts ...TablePrinterOption) (*TablePr
 		format:       OutputFormatTable,
 		delimeter:    DefaultDelimeter,
 		truncateFunc: text.Truncate,
+		columns:      columnsFromContext(ctx),
 	}
 
 	for _, tpo := range topts {
@@ -104,18 +122,40 @@ func (printer *TablePrinter) Render(w io.Writer) error {
 		return nil
 	}
 
+	if err := printer.selectColumns(); err != nil {
+		return err
+	}
+
 	switch printer.format {
 	case OutputFormatList:
 		return printer.renderList(w)
 	case OutputFormatJSON:
 		return printer.renderJSON(w)
+	case OutputFormatJSONL:
+		return printer.renderJSONLines(w)
 	case OutputFormatYAML:
 		return printer.renderYAML(w)
+	case OutputFormatGoTemplate:
+		return printer.renderGoTemplate(w)
 	default:
 		return printer.renderTable(w)
 	}
 }
 
+// maxColumnWidths returns the width of the widest field of each column.
+func (printer *TablePrinter) maxColumnWidths() []int {
+	widths := make([]int, len(printer.rows[0]))
+	for _, row := range printer.rows {
+		for col, field := range row {
+			if w := field.DisplayWidth(); w > widths[col] {
+				widths[col] = w
+			}
+		}
+	}
+
+	return widths
+}
+
 func (printer *TablePrinter) calculateColumnWidths(delimSize int) []int {
 	numCols := len(printer.rows[0])
 	allColWidths := make([][]int, numCols)
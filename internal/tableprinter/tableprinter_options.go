@@ -21,6 +21,19 @@ func WithOutputFormatFromString(format string) TablePrinterOption {
 		if format == "" {
 			return fmt.Errorf("unsupported table printer format: %s", format)
 		}
+
+		if IsGoTemplate(format) {
+			tmpl, err := ParseGoTemplate(format)
+			if err != nil {
+				return err
+			}
+
+			opts.format = OutputFormatGoTemplate
+			opts.template = tmpl
+
+			return nil
+		}
+
 		opts.format = TableOutputFormat(format)
 		return nil
 	}
@@ -52,3 +65,13 @@ func WithMaxWidth(maxWidth int) TablePrinterOption {
 		return nil
 	}
 }
+
+// WithColumns returns a function func(opts *TablePrinter)
+// that sets `columns` in TablePrinter pointer instance, such that only the
+// columns with the provided names are rendered in the given order.
+func WithColumns(columns ...string) TablePrinterOption {
+	return func(opts *TablePrinter) error {
+		opts.columns = columns
+		return nil
+	}
+}
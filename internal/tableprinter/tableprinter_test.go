@@ -25,6 +25,7 @@ package tableprinter
 
 import (
 	"bytes"
+	"context"
 	"testing"
 
 	"kraftkit.sh/internal/text"
@@ -56,3 +57,152 @@ func Test_TablePrinter_OutputFormatTable(t *testing.T) {
 		t.Errorf("expected: %q, got: %q", expected, buf.String())
 	}
 }
+
+func Test_TablePrinter_OutputFormatJSONL(t *testing.T) {
+	buf := bytes.Buffer{}
+	tp := &TablePrinter{
+		format:       OutputFormatJSONL,
+		delimeter:    DefaultDelimeter,
+		truncateFunc: text.Truncate,
+	}
+
+	tp.AddField("NAME", nil)
+	tp.AddField("STATUS", nil)
+	tp.EndRow()
+	tp.AddField("hello", nil)
+	tp.AddField("up", nil)
+	tp.EndRow()
+	tp.AddField("world", nil)
+	tp.AddField("down", nil)
+	tp.EndRow()
+
+	err := tp.Render(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "{\"name\":\"hello\",\"status\":\"up\"}\n{\"name\":\"world\",\"status\":\"down\"}\n"
+	if buf.String() != expected {
+		t.Errorf("expected: %q, got: %q", expected, buf.String())
+	}
+}
+
+func Test_TablePrinter_OutputFormatList(t *testing.T) {
+	buf := bytes.Buffer{}
+	tp := &TablePrinter{
+		format:       OutputFormatList,
+		delimeter:    DefaultDelimeter,
+		truncateFunc: text.Truncate,
+	}
+
+	tp.AddField("NAME", nil)
+	tp.AddField("FQDN", nil)
+	tp.AddField("ARGS", nil)
+	tp.EndRow()
+	tp.AddField("hello", nil)
+	tp.AddField("", nil)
+	tp.AddField("-p\t8080  --verbose", nil)
+	tp.EndRow()
+	tp.AddField("world", nil)
+	tp.AddField("world.example.com", nil)
+	tp.AddField("", nil)
+	tp.EndRow()
+	tp.AddField("escaped", nil)
+	tp.AddField("-", nil)
+	tp.AddField(`\n`, nil)
+	tp.EndRow()
+
+	if err := tp.Render(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "hello\t-\t-p 8080 --verbose\nworld\tworld.example.com\t-\nescaped\t\\-\t\\\\n\n"
+	if buf.String() != expected {
+		t.Errorf("expected: %q, got: %q", expected, buf.String())
+	}
+}
+
+func Test_TablePrinter_OutputFormatGoTemplate(t *testing.T) {
+	buf := bytes.Buffer{}
+	tp, err := NewTablePrinter(context.Background(),
+		WithOutputFormatFromString("go-template={{.Name}} is {{.PrivateStatus}} at {{.PrivateIP}}"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tp.AddField("NAME", nil)
+	tp.AddField("PRIVATE STATUS", nil)
+	tp.AddField("PRIVATE IP", nil)
+	tp.EndRow()
+	tp.AddField("hello", nil)
+	tp.AddField("up", nil)
+	tp.AddField("10.0.0.1", nil)
+	tp.EndRow()
+	tp.AddField("world", nil)
+	tp.AddField("down", nil)
+	tp.AddField("10.0.0.2", nil)
+	tp.EndRow()
+
+	if err := tp.Render(&buf); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "hello is up at 10.0.0.1\nworld is down at 10.0.0.2\n"
+	if buf.String() != expected {
+		t.Errorf("expected: %q, got: %q", expected, buf.String())
+	}
+}
+
+func TestGoTemplateKey(t *testing.T) {
+	tests := map[string]string{
+		"NAME":          "Name",
+		"FQDN":          "FQDN",
+		"PRIVATE FQDN":  "PrivateFQDN",
+		"CREATED AT":    "CreatedAt",
+		"SERVICE GROUP": "ServiceGroup",
+		"MACHINE ID":    "MachineID",
+		"boot_time":     "BootTime",
+	}
+
+	for header, expected := range tests {
+		if actual := GoTemplateKey(header); actual != expected {
+			t.Errorf("GoTemplateKey(%q) = %q, expected %q", header, actual, expected)
+		}
+	}
+}
+
+func Test_TablePrinter_OutputFormatGoTemplateInvalid(t *testing.T) {
+	if _, err := NewTablePrinter(context.Background(),
+		WithOutputFormatFromString("go-template={{.Name"),
+	); err == nil {
+		t.Errorf("expected error for invalid template")
+	}
+}
+
+func Test_TablePrinter_OutputFormatWide(t *testing.T) {
+	buf := bytes.Buffer{}
+	tp := &TablePrinter{
+		maxWidth:     5,
+		format:       OutputFormatWide,
+		delimeter:    DefaultDelimeter,
+		truncateFunc: text.Truncate,
+	}
+
+	tp.AddField("1", nil)
+	tp.AddField("hello", nil)
+	tp.EndRow()
+	tp.AddField("22", nil)
+	tp.AddField("world", nil)
+	tp.EndRow()
+
+	err := tp.Render(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := "1   hello\n22  world\n"
+	if buf.String() != expected {
+		t.Errorf("expected: %q, got: %q", expected, buf.String())
+	}
+}
@@ -37,12 +37,22 @@ func FindFreeNetwork(pool NetworkPool, existingNetworks *networkapi.NetworkList)
 	convertedNetworks := []net.IPNet{}
 
 	for _, network := range existingNetworks.Items {
-		maskBytes := net.ParseIP(network.Spec.Netmask).To4()
-		mask := net.IPv4Mask(maskBytes[0], maskBytes[1], maskBytes[2], maskBytes[3])
+		// The pool only consists of IPv4 networks, so IPv6 networks can never
+		// intersect with a candidate.
+		gateway := net.ParseIP(network.Spec.Gateway).To4()
+		if gateway == nil {
+			continue
+		}
+
+		mask := ParseNetmask(gateway, network.Spec.Netmask)
+		if mask == nil {
+			continue
+		}
+
 		// Setup IP address for bridge.
 		convertedNetworks = append(convertedNetworks,
 			net.IPNet{
-				IP:   net.ParseIP(network.Spec.Gateway),
+				IP:   gateway,
 				Mask: mask,
 			})
 	}
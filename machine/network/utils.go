@@ -5,9 +5,216 @@
 
 package network
 
-import "net"
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
 
 // NetworksIntersect returns whether two networks have any common IP addresses.
 func NetworksIntersect(a, b net.IPNet) bool {
 	return a.Contains(b.IP) || b.Contains(a.IP)
 }
+
+// ParseCIDR parses a network given either in CIDR notation, e.g. 10.0.0.1/24,
+// or with a dotted netmask, e.g. 10.0.0.1/255.255.255.0.  The IP of the
+// returned network is the address of its gateway.  When the network address
+// itself is provided, e.g. 10.0.0.0/24, the first host address of the network
+// is used as the gateway instead.
+func ParseCIDR(s string) (*net.IPNet, error) {
+	addr, suffix, ok := strings.Cut(s, "/")
+	if !ok {
+		return nil, fmt.Errorf("invalid network '%s': expected ADDRESS/PREFIX or ADDRESS/NETMASK", s)
+	}
+
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return nil, fmt.Errorf("invalid network '%s': invalid address '%s'", s, addr)
+	}
+
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+
+	bits := len(ip) * 8
+
+	var mask net.IPMask
+	if prefix, err := strconv.Atoi(suffix); err == nil {
+		if prefix < 0 || prefix > bits {
+			return nil, fmt.Errorf("invalid network '%s': prefix length must be between 0 and %d", s, bits)
+		}
+
+		mask = net.CIDRMask(prefix, bits)
+	} else {
+		netmask := net.ParseIP(suffix).To4()
+		if netmask == nil || len(ip) != net.IPv4len {
+			return nil, fmt.Errorf("invalid network '%s': invalid netmask '%s'", s, suffix)
+		}
+
+		mask = net.IPMask(netmask)
+		if _, size := mask.Size(); size == 0 {
+			return nil, fmt.Errorf("invalid network '%s': netmask '%s' is not contiguous", s, suffix)
+		}
+	}
+
+	// At least the gateway and one further host must fit in the network.
+	if ones, _ := mask.Size(); ones > bits-2 {
+		return nil, fmt.Errorf("invalid network '%s': network is too small", s)
+	}
+
+	if ip.Equal(ip.Mask(mask)) {
+		gateway := make(net.IP, len(ip))
+		copy(gateway, ip)
+		gateway[len(gateway)-1]++
+		ip = gateway
+	}
+
+	return &net.IPNet{
+		IP:   ip,
+		Mask: mask,
+	}, nil
+}
+
+// ParseSubnet parses the subnet of a network in CIDR notation, e.g.
+// 10.0.0.0/24, together with the address of its gateway.  If no gateway is
+// provided, the first address of the subnet is used.  The returned network
+// carries the gateway as its address.
+func ParseSubnet(subnet, gateway string) (*net.IPNet, error) {
+	ip, ipnet, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return nil, fmt.Errorf("invalid subnet '%s': %w", subnet, err)
+	}
+
+	if !ip.Equal(ipnet.IP) {
+		return nil, fmt.Errorf("invalid subnet '%s': host bits are set, did you mean %s?", subnet, ipnet)
+	}
+
+	// Default to the first address of the subnet, which also validates its
+	// size.
+	addr, err := ParseCIDR(subnet)
+	if err != nil {
+		return nil, err
+	}
+
+	if gateway == "" {
+		return addr, nil
+	}
+
+	gw := net.ParseIP(gateway)
+	if gw == nil {
+		return nil, fmt.Errorf("invalid gateway '%s'", gateway)
+	}
+
+	if gw4 := gw.To4(); gw4 != nil {
+		gw = gw4
+	}
+
+	if len(gw) != len(ipnet.IP) || !ipnet.Contains(gw) {
+		return nil, fmt.Errorf("gateway %s is not within subnet %s", gateway, ipnet)
+	}
+
+	if gw.Equal(ipnet.IP) {
+		return nil, fmt.Errorf("gateway %s cannot be the address of subnet %s", gateway, ipnet)
+	}
+
+	if len(gw) == net.IPv4len {
+		broadcast := make(net.IP, len(gw))
+		for i := range gw {
+			broadcast[i] = ipnet.IP[i] | ^ipnet.Mask[i]
+		}
+
+		if gw.Equal(broadcast) {
+			return nil, fmt.Errorf("gateway %s cannot be the broadcast address of subnet %s", gateway, ipnet)
+		}
+	}
+
+	return &net.IPNet{
+		IP:   gw,
+		Mask: ipnet.Mask,
+	}, nil
+}
+
+// ParseNetmask parses the netmask of a network with the provided gateway.  The
+// netmask is given either in address notation, e.g. 255.255.255.0 or
+// ffff:ffff:ffff:ffff::, or as a prefix length, e.g. 64.  The returned mask
+// matches the address family of the gateway, or is nil if it is invalid.
+func ParseNetmask(gateway net.IP, netmask string) net.IPMask {
+	bits := net.IPv6len * 8
+	if gateway.To4() != nil {
+		bits = net.IPv4len * 8
+	}
+
+	if prefix, err := strconv.Atoi(netmask); err == nil {
+		if prefix < 0 || prefix > bits {
+			return nil
+		}
+
+		return net.CIDRMask(prefix, bits)
+	}
+
+	ip := net.ParseIP(netmask)
+	if bits == net.IPv4len*8 {
+		ip = ip.To4()
+	}
+
+	if ip == nil {
+		return nil
+	}
+
+	mask := net.IPMask(ip)
+	if _, size := mask.Size(); size == 0 {
+		return nil
+	}
+
+	return mask
+}
+
+// FormatCIDR returns the network with the provided gateway and netmask in CIDR
+// notation, e.g. 10.0.0.0/24 for the gateway 10.0.0.1 and the netmask
+// 255.255.255.0 or fd00::/64 for the gateway fd00::1 and the netmask
+// ffff:ffff:ffff:ffff::.  An empty string is returned if either is invalid.
+func FormatCIDR(gateway, netmask string) string {
+	ip := net.ParseIP(gateway)
+	if ip == nil {
+		return ""
+	}
+
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+
+	mask := ParseNetmask(ip, netmask)
+	if mask == nil {
+		return ""
+	}
+
+	return (&net.IPNet{
+		IP:   ip.Mask(mask),
+		Mask: mask,
+	}).String()
+}
+
+// FormatNetmask returns the provided gateway together with its netmask, e.g.
+// 10.0.0.1/255.255.255.0.  Since dotted netmasks are unusual for IPv6, the
+// prefix length is used for IPv6 networks instead, e.g. fd00::1/64.  An empty
+// string is returned if either is invalid.
+func FormatNetmask(gateway, netmask string) string {
+	ip := net.ParseIP(gateway)
+	if ip == nil {
+		return ""
+	}
+
+	mask := ParseNetmask(ip, netmask)
+	if mask == nil {
+		return ""
+	}
+
+	if ip.To4() != nil {
+		return fmt.Sprintf("%s/%s", ip, net.IP(mask))
+	}
+
+	ones, _ := mask.Size()
+
+	return fmt.Sprintf("%s/%d", ip, ones)
+}
@@ -202,6 +202,23 @@ func WithJobs(jobs int) MakeOption {
 	}
 }
 
+// ResolveJobs returns the number of jobs to allow at once given the number of
+// jobs requested explicitly, where zero means unset, and whether the build may
+// be parallelized (i.e. neither --no-fast nor --no-parallel were set).  A build
+// which may not be parallelized is always capped to a single job, otherwise
+// the requested number of jobs is used or, if unset, one job per CPU.
+func ResolveJobs(jobs int, fast bool) int {
+	if !fast {
+		return 1
+	}
+
+	if jobs > 0 {
+		return jobs
+	}
+
+	return runtime.NumCPU()
+}
+
 // Allow N jobs at once; infinite jobs with no arg.  Equivalent to calling the
 // flags -j|--jobs with a value
 func WithMaxJobs(maxJobs bool) MakeOption {
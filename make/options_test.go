@@ -0,0 +1,59 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+
+package make
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestResolveJobs(t *testing.T) {
+	tests := []struct {
+		name     string
+		jobs     int
+		fast     bool
+		expected int
+	}{
+		{
+			name:     "one job per cpu by default",
+			jobs:     0,
+			fast:     true,
+			expected: runtime.NumCPU(),
+		},
+		{
+			name:     "explicit jobs",
+			jobs:     3,
+			fast:     true,
+			expected: 3,
+		},
+		{
+			name:     "no-fast caps to a single job",
+			jobs:     0,
+			fast:     false,
+			expected: 1,
+		},
+		{
+			name:     "no-fast caps explicit jobs to a single job",
+			jobs:     8,
+			fast:     false,
+			expected: 1,
+		},
+		{
+			name:     "negative jobs are treated as unset",
+			jobs:     -1,
+			fast:     true,
+			expected: runtime.NumCPU(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ResolveJobs(tt.jobs, tt.fast); got != tt.expected {
+				t.Errorf("ResolveJobs(%d, %t) = %d, expected %d", tt.jobs, tt.fast, got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,310 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+package oci
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/containerd/containerd/errdefs"
+	"github.com/containerd/containerd/images"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/layout"
+	"github.com/google/go-containerregistry/pkg/v1/tarball"
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+
+	"kraftkit.sh/archive"
+	"kraftkit.sh/internal/version"
+	"kraftkit.sh/log"
+	"kraftkit.sh/oci/handler"
+	"kraftkit.sh/pack"
+	"kraftkit.sh/packmanager"
+)
+
+var _ packmanager.Importer = (*ociManager)(nil)
+
+// Import implements packmanager.Importer.  Both OCI image layout archives and
+// archives in the format of `docker save` are supported.  The images of the
+// archive are stored via the manager's handler under a new index with the
+// provided name, such that the returned package can be pushed like any other.
+func (manager *ociManager) Import(ctx context.Context, path, fullref string) (pack.Package, error) {
+	ref, err := name.ParseReference(fullref,
+		name.WithDefaultRegistry(""),
+		name.WithDefaultTag(DefaultTag),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, handle, err := manager.handle(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tmpdir, err := os.MkdirTemp("", "kraftkit-oci-import-*")
+	if err != nil {
+		return nil, fmt.Errorf("could not create temporary directory: %w", err)
+	}
+
+	defer os.RemoveAll(tmpdir)
+
+	imgs, err := imagesFromArchive(path, tmpdir)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(imgs) == 0 {
+		return nil, fmt.Errorf("archive does not contain any image")
+	}
+
+	index := &ocispec.Index{
+		MediaType: ocispec.MediaTypeImageIndex,
+		Versioned: specs.Versioned{
+			SchemaVersion: 2,
+		},
+		Annotations: map[string]string{
+			ocispec.AnnotationRefName: ref.Context().String(),
+			ocispec.AnnotationCreated: time.Now().UTC().Format(time.RFC3339),
+			AnnotationKraftKitVersion: version.Version(),
+
+			// containerd compatibility annotations
+			images.AnnotationImageName: ref.String(),
+		},
+	}
+
+	for _, img := range imgs {
+		desc, err := saveImage(ctx, handle, ref.Name(), img)
+		if err != nil {
+			return nil, err
+		}
+
+		index.Manifests = append(index.Manifests, desc)
+	}
+
+	indexJson, err := json.Marshal(index)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal index: %w", err)
+	}
+
+	indexDesc := content.NewDescriptorFromBytes(
+		ocispec.MediaTypeImageIndex,
+		indexJson,
+	)
+	indexDesc.Annotations = index.Annotations
+
+	// Remove the old index
+	if err := handle.DeleteIndex(ctx, ref.Name(), false); err != nil {
+		return nil, fmt.Errorf("failed to remove old index: %w", err)
+	}
+
+	log.G(ctx).
+		WithField("ref", ref.Name()).
+		WithField("digest", indexDesc.Digest.String()).
+		Debug("saving imported index")
+
+	if err := handle.SaveDescriptor(
+		ctx,
+		ref.Name(),
+		indexDesc,
+		bytes.NewReader(indexJson),
+		nil,
+	); err != nil && !errors.Is(err, errdefs.ErrAlreadyExists) {
+		return nil, fmt.Errorf("failed to save index: %w", err)
+	}
+
+	return NewPackageFromOCIManifestDigest(ctx, handle, ref.Name(), manager.auths, index.Manifests[0].Digest)
+}
+
+// imagesFromArchive returns the images contained in the archive at the
+// provided path, extracting it to the provided directory if it is an OCI
+// image layout.
+func imagesFromArchive(path, dir string) ([]v1.Image, error) {
+	fi, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer fi.Close()
+
+	if err := archive.Untar(fi, dir); err != nil {
+		return nil, fmt.Errorf("could not extract image archive: %w", err)
+	}
+
+	// An OCI image layout always contains an index at its root.
+	if _, err := os.Stat(filepath.Join(dir, "index.json")); err != nil {
+		img, err := tarball.ImageFromPath(path, nil)
+		if err != nil {
+			return nil, fmt.Errorf("could not read image archive: %w", err)
+		}
+
+		return []v1.Image{img}, nil
+	}
+
+	index, err := layout.ImageIndexFromPath(dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not read OCI image layout: %w", err)
+	}
+
+	return imagesFromIndex(index)
+}
+
+// imagesFromIndex returns the images of the provided index, including those
+// of nested indexes.
+func imagesFromIndex(index v1.ImageIndex) ([]v1.Image, error) {
+	manifest, err := index.IndexManifest()
+	if err != nil {
+		return nil, fmt.Errorf("could not get index manifest: %w", err)
+	}
+
+	var imgs []v1.Image
+	for _, desc := range manifest.Manifests {
+		switch {
+		case desc.MediaType.IsImage():
+			img, err := index.Image(desc.Digest)
+			if err != nil {
+				return nil, err
+			}
+
+			imgs = append(imgs, img)
+
+		case desc.MediaType.IsIndex():
+			child, err := index.ImageIndex(desc.Digest)
+			if err != nil {
+				return nil, err
+			}
+
+			childImgs, err := imagesFromIndex(child)
+			if err != nil {
+				return nil, err
+			}
+
+			imgs = append(imgs, childImgs...)
+		}
+	}
+
+	return imgs, nil
+}
+
+// saveImage stores the manifest, config and layers of the provided image via
+// the provided handler and returns the descriptor of its manifest.
+func saveImage(ctx context.Context, handle handler.Handler, ref string, img v1.Image) (ocispec.Descriptor, error) {
+	manifest, err := img.Manifest()
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("could not get manifest: %w", err)
+	}
+
+	manifestJson, err := img.RawManifest()
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("could not get manifest: %w", err)
+	}
+
+	config, err := img.ConfigFile()
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("could not parse config: %w", err)
+	}
+
+	manifestDesc := content.NewDescriptorFromBytes(
+		string(manifest.MediaType),
+		manifestJson,
+	)
+	manifestDesc.Platform = &ocispec.Platform{
+		Architecture: config.Architecture,
+		OS:           config.OS,
+		OSVersion:    config.OSVersion,
+		OSFeatures:   config.OSFeatures,
+	}
+
+	log.G(ctx).
+		WithField("ref", ref).
+		WithField("digest", manifestDesc.Digest.String()).
+		Debug("saving imported manifest")
+
+	if err := handle.SaveDescriptor(
+		ctx,
+		ref,
+		manifestDesc,
+		bytes.NewReader(manifestJson),
+		nil,
+	); err != nil && !errors.Is(err, errdefs.ErrAlreadyExists) {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to save manifest: %w", err)
+	}
+
+	// As with Manifest.Save, the config and layers are saved after the manifest
+	// which references them, such that containerd's garbage collector does not
+	// remove them in the meantime.
+	configJson, err := img.RawConfigFile()
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("could not get config: %w", err)
+	}
+
+	if err := handle.SaveDescriptor(
+		ctx,
+		"",
+		FromGoogleV1DescriptorToOCISpec(manifest.Config)[0],
+		bytes.NewReader(configJson),
+		nil,
+	); err != nil && !errors.Is(err, errdefs.ErrAlreadyExists) {
+		return ocispec.Descriptor{}, fmt.Errorf("failed to save config: %w", err)
+	}
+
+	layers, err := img.Layers()
+	if err != nil {
+		return ocispec.Descriptor{}, fmt.Errorf("could not get layers: %w", err)
+	}
+
+	for _, layer := range layers {
+		desc, err := layerDescriptor(layer)
+		if err != nil {
+			return ocispec.Descriptor{}, err
+		}
+
+		reader, err := layer.Compressed()
+		if err != nil {
+			return ocispec.Descriptor{}, fmt.Errorf("could not read layer: %w", err)
+		}
+
+		err = handle.SaveDescriptor(ctx, "", desc, reader, nil)
+		reader.Close()
+		if err != nil && !errors.Is(err, errdefs.ErrAlreadyExists) {
+			return ocispec.Descriptor{}, fmt.Errorf("failed to save layer: %w", err)
+		}
+	}
+
+	return manifestDesc, nil
+}
+
+// layerDescriptor returns the descriptor of the provided layer.
+func layerDescriptor(layer v1.Layer) (ocispec.Descriptor, error) {
+	dgst, err := layer.Digest()
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	size, err := layer.Size()
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	mediaType, err := layer.MediaType()
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	return ocispec.Descriptor{
+		MediaType: string(mediaType),
+		Digest:    digest.Digest(dgst.String()),
+		Size:      size,
+	}, nil
+}
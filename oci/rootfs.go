@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: BSD-3-Clause
+// Copyright (c) 2024, Unikraft GmbH and The KraftKit Authors.
+// Licensed under the BSD-3-Clause License (the "License").
+// You may not use this file except in compliance with the License.
+package oci
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+
+	"kraftkit.sh/initrd"
+	"kraftkit.sh/internal/version"
+	"kraftkit.sh/log"
+	"kraftkit.sh/oci/simpleauth"
+	"kraftkit.sh/packmanager"
+)
+
+var _ packmanager.RootfsPuller = (*ociManager)(nil)
+
+// PullRootfs implements packmanager.RootfsPuller.  Unlike packages, the image
+// need not have been built by KraftKit, such that the image is retrieved for
+// Linux on the provided architecture, using the credentials of the manager.
+func (manager *ociManager) PullRootfs(ctx context.Context, fullref, arch string) (string, error) {
+	ref, err := name.ParseReference(fullref)
+	if err != nil {
+		return "", fmt.Errorf("could not parse image reference: %w", err)
+	}
+
+	auths := manager.auths
+	if auths == nil {
+		auths, err = defaultAuths(ctx)
+		if err != nil {
+			return "", fmt.Errorf("could not gather authentication details")
+		}
+	}
+
+	ropts := []remote.Option{
+		remote.WithContext(ctx),
+		remote.WithUserAgent(version.UserAgent()),
+		remote.WithPlatform(v1.Platform{
+			OS:           "linux",
+			Architecture: ociArchitecture(arch),
+		}),
+	}
+
+	// Annoyingly convert between regtypes and authn.
+	if auth, ok := auths[ref.Context().RegistryStr()]; ok {
+		ropts = append(ropts,
+			remote.WithAuth(&simpleauth.SimpleAuthenticator{
+				Auth: &authn.AuthConfig{
+					Username: auth.User,
+					Password: auth.Token,
+				},
+			}),
+		)
+
+		if !auth.VerifySSL {
+			transport := remote.DefaultTransport.(*http.Transport).Clone()
+			transport.TLSClientConfig = &tls.Config{
+				InsecureSkipVerify: true,
+			}
+
+			ropts = append(ropts, remote.WithTransport(transport))
+		}
+	}
+
+	log.G(ctx).
+		WithField("image", ref.String()).
+		Debug("pulling rootfs")
+
+	image, err := remote.Image(ref, ropts...)
+	if err != nil {
+		return "", fmt.Errorf("could not pull image %s: %w", ref, err)
+	}
+
+	rootfs, err := initrd.NewFromOCIImage(ctx, image, initrd.WithArchitecture(arch))
+	if err != nil {
+		return "", err
+	}
+
+	return rootfs.Build(ctx)
+}
+
+// ociArchitecture converts the name of an architecture as used by Unikraft to
+// its equivalent in an OCI image platform.
+func ociArchitecture(arch string) string {
+	switch arch {
+	case "x86_64":
+		return "amd64"
+	default:
+		return arch
+	}
+}
@@ -59,3 +59,21 @@ type PackageManager interface {
 	// Format returns the name of the implementation.
 	Format() pack.PackageFormat
 }
+
+// Importer is implemented by package managers which can add the package
+// contained in an archive, e.g. an OCI image layout, to their local catalog.
+type Importer interface {
+	// Import reads the archive at the provided path and stores its contents
+	// locally under the provided name, returning the resulting package.
+	Import(ctx context.Context, path, name string) (pack.Package, error)
+}
+
+// RootfsPuller is implemented by package managers which can retrieve an
+// arbitrary (container) image, e.g. `docker.io/library/alpine:3`, to use its
+// filesystem as the root filesystem of a unikernel.
+type RootfsPuller interface {
+	// PullRootfs retrieves the image with the provided reference for the
+	// provided architecture and serializes its filesystem into an initramfs,
+	// returning its path.
+	PullRootfs(ctx context.Context, ref, arch string) (string, error)
+}
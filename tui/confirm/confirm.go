@@ -14,10 +14,17 @@ import (
 // NewConfirm is a utility method used in a CLI context to prompt the user with
 // a yes/no question.
 func NewConfirm(question string) (bool, error) {
+	return NewConfirmWithDefault(question, true)
+}
+
+// NewConfirmWithDefault is a utility method used in a CLI context to prompt
+// the user with a yes/no question where the pre-selected answer is set to the
+// provided default.
+func NewConfirmWithDefault(question string, def bool) (bool, error) {
 	input := confirmation.New(
 		tui.TextWhiteBgBlue("[?]")+" "+
 			question,
-		confirmation.NewValue(true),
+		confirmation.NewValue(def),
 	)
 	input.Template = confirmation.TemplateYN
 	input.ResultTemplate = confirmation.ResultTemplateYN
@@ -6,6 +6,7 @@ package processtree
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
@@ -44,7 +45,10 @@ const (
 	LOGLEN  = 5
 )
 
-var tprog *tea.Program
+// ErrForceQuit is returned by Start when the tree was quit, e.g. following an
+// interrupt, before all of its processes finished.  The contexts of the
+// processes which were still running are cancelled.
+var ErrForceQuit = errors.New("force quit")
 
 type ProcessTreeItem struct {
 	textLeft  string
@@ -70,6 +74,7 @@ type ProcessTree struct {
 	tree      []*ProcessTreeItem
 	quitting  bool
 	ctx       context.Context
+	cancel    context.CancelFunc
 	timer     stopwatch.Model
 	width     int
 	rightPad  int
@@ -91,9 +96,12 @@ func NewProcessTree(ctx context.Context, opts []ProcessTreeOption, tree ...*Proc
 		return nil, fmt.Errorf("cannot instantiate process tree without sub processes")
 	}
 
+	ctx, cancel := context.WithCancel(ctx)
+
 	pt := &ProcessTree{
 		tree:      tree,
 		ctx:       ctx,
+		cancel:    cancel,
 		timer:     stopwatch.NewWithInterval(time.Millisecond * 100),
 		channel:   make(chan *ProcessTreeItem),
 		errChan:   make(chan error),
@@ -104,6 +112,7 @@ func NewProcessTree(ctx context.Context, opts []ProcessTreeOption, tree ...*Proc
 
 	for _, opt := range opts {
 		if err := opt(pt); err != nil {
+			cancel()
 			return nil, err
 		}
 	}
@@ -198,6 +207,9 @@ func (pti *ProcessTreeItem) Close() error {
 }
 
 func (pt *ProcessTree) Start() error {
+	// Cancel any process which is still running once the program exits.
+	defer pt.cancel()
+
 	teaOpts := []tea.ProgramOption{
 		tea.WithInput(nil),
 		tea.WithContext(pt.ctx),
@@ -218,12 +230,20 @@ func (pt *ProcessTree) Start() error {
 		pt.width, _, _ = term.GetSize(int(os.Stdout.Fd()))
 	}
 
-	tprog = tea.NewProgram(pt, teaOpts...)
+	// The program is local to each tree such that multiple trees can be run
+	// concurrently, e.g. when each of them is an item of a parallel tree.
+	prog := tea.NewProgram(pt, teaOpts...)
 
-	if _, err := tprog.Run(); err != nil {
+	if _, err := prog.Run(); err != nil {
 		return err
 	}
 
+	// The program was quit by the signal handler of bubbletea before all
+	// processes finished.
+	if pt.err == nil && pt.finished < pt.total {
+		return ErrForceQuit
+	}
+
 	return pt.err
 }
 
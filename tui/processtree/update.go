@@ -37,7 +37,7 @@ func (pt *ProcessTree) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch msg.String() {
 		case "ctrl+c":
 			pt.quitting = true
-			pt.err = fmt.Errorf("force quit")
+			pt.err = ErrForceQuit
 			return pt, tea.Quit
 		}
 
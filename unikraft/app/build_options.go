@@ -6,6 +6,7 @@ package app
 
 import (
 	"fmt"
+	"io"
 	"os"
 
 	"kraftkit.sh/exec"
@@ -68,6 +69,22 @@ func WithBuildLogFile(path string) BuildOption {
 	}
 }
 
+// WithBuildLogWriter specifies a writer which will receive the output from
+// Unikraft's build invocation as it is produced
+func WithBuildLogWriter(w io.Writer) BuildOption {
+	return func(bo *BuildOptions) error {
+		if w == nil {
+			return nil
+		}
+
+		bo.mopts = append(bo.mopts, make.WithExecOptions(
+			exec.WithStdoutCallback(w),
+		))
+
+		return nil
+	}
+}
+
 // WithBuildNoPrepare disables calling `make prepare` befere invoking the
 // main Unikraft's build invocation.
 func WithBuildNoPrepare(noPrepare bool) BuildOption {